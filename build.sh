@@ -48,9 +48,10 @@ if [[ "${CLEAN}" -eq 1 ]]; then
     exit 0
 fi
 
-make ccr_syncer
+make ccr_syncer ccrctl
 
 cp ${SYNCER_HOME}/bin/ccr_syncer ${SYNCER_OUTPUT}/bin/
+cp ${SYNCER_HOME}/bin/ccrctl ${SYNCER_OUTPUT}/bin/
 cp ${SYNCER_HOME}/shell/* ${SYNCER_OUTPUT}/bin/
 cp -r ${SYNCER_HOME}/doc ${SYNCER_OUTPUT}/
 cp ${SYNCER_HOME}/CHANGELOG.md ${SYNCER_OUTPUT}/
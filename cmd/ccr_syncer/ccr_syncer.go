@@ -30,12 +30,14 @@ import (
 
 	"github.com/selectdb/ccr_syncer/pkg/ccr"
 	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/notify"
 	"github.com/selectdb/ccr_syncer/pkg/rpc"
 	"github.com/selectdb/ccr_syncer/pkg/service"
 	"github.com/selectdb/ccr_syncer/pkg/storage"
 	"github.com/selectdb/ccr_syncer/pkg/utils"
 	"github.com/selectdb/ccr_syncer/pkg/version"
 	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	"github.com/selectdb/ccr_syncer/pkg/xtrace"
 
 	"github.com/hashicorp/go-metrics"
 	"github.com/hashicorp/go-metrics/prometheus"
@@ -140,6 +142,13 @@ func main() {
 		}
 	}
 
+	if hasKey, err := base.HasCredentialKey(); err != nil {
+		log.Fatalf("load credential key error: %+v", err)
+	} else if !hasKey {
+		log.Warnf("no credential key is supplied, the passwords of the jobs are persisted in plaintext, "+
+			"set --credential_key_file or env %s to encrypt them", base.CredentialKeyEnv)
+	}
+
 	// Step 1: Check db
 	var db storage.DB
 	var err error
@@ -165,9 +174,15 @@ func main() {
 
 	// Step 3: create job manager && http service && checker
 	hostInfo := fmt.Sprintf("%s:%d", syncer.Host, syncer.Port)
+	if err := notify.Init(hostInfo); err != nil {
+		log.Fatalf("init notify failed: %+v", err)
+	}
+	if err := xtrace.Init(hostInfo); err != nil {
+		log.Fatalf("init trace failed: %+v", err)
+	}
 	jobManager := ccr.NewJobManager(db, factory, hostInfo)
-	httpService := service.NewHttpServer(syncer.Host, syncer.Port, db, jobManager)
 	checker := ccr.NewChecker(hostInfo, db, jobManager)
+	httpService := service.NewHttpServer(syncer.Host, syncer.Port, db, jobManager, checker)
 
 	// Step 4: http service start
 	var wg sync.WaitGroup
@@ -221,6 +236,7 @@ func main() {
 			checker.Stop()
 			jobManager.Stop()
 			monitor.Stop()
+			xtrace.Shutdown()
 			log.Info("all service stop")
 			return true
 		case syscall.SIGHUP:
@@ -244,7 +260,14 @@ func main() {
 		go func() {
 			defer wg.Done()
 			var pprof_info string = fmt.Sprintf("%s:%d", syncer.Host, syncer.Ppof_port)
-			if err := http.ListenAndServe(pprof_info, nil); err != nil {
+			// The pprof handlers are registered in the default mux, they require the admin role
+			// if the http api is authenticated.
+			handler, err := service.WrapAdmin("/debug/pprof/", http.DefaultServeMux)
+			if err != nil {
+				log.Errorf("start pprof failed on: %s, error : %+v", pprof_info, err)
+				return
+			}
+			if err := service.ListenAndServe(&http.Server{Addr: pprof_info, Handler: handler}); err != nil {
 				log.Infof("start pprof failed on: %s, error : %+v", pprof_info, err)
 			}
 		}()
@@ -85,6 +85,8 @@ func (m *Monitor) dump() {
 		numJobs, numRunning, numDbSync, numTableSync)
 	log.Infof("[JOB STATUS] FullSync = %v, PartialSync = %v, IncrementalSync = %v",
 		numFullSync, numPartialSync, numIncremental)
+
+	m.jobManager.UpdateLagMetrics()
 }
 
 func (m *Monitor) Start() {
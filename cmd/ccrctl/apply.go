@@ -0,0 +1,452 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	planCreate   = "create"
+	planUpdate   = "update"
+	planDelete   = "delete"
+	planConflict = "conflict" // the immutable fields are changed, the job must be recreated manually
+)
+
+const applyDetails = `The options of the job are compared with the file, an option absent from the file is removed
+if it is mutable, or reported as a conflict. The host mapping is only compared if it is listed,
+the states set by the other commands (stop-at, skip-binlog) are never compared. The passwords are
+redacted by the syncer, so they are never compared nor updated, use 'ccrctl update' to rotate
+them. skip_error and allow_table_exists only take effect on creation.`
+
+// desiredState is the file of `ccrctl apply`, each job is a create request.
+type desiredState struct {
+	Jobs []map[string]any `json:"jobs"`
+}
+
+// The fields of the create request which are not persisted in the job, they are ignored in diff.
+var unpersistedFields = map[string]bool{
+	"skip_error":         true,
+	"allow_table_exists": true,
+}
+
+// The identity fields of the spec, they can't be changed once the job is created. The frontend,
+// the user and the host mapping are updated in place.
+var specIdentityFields = []string{"cluster", "database", "table"}
+
+// The options of the create request, the other fields of the job extra are the states set by
+// the other commands, eg. stop-at, they are not compared.
+var declaredOptions = map[string]bool{
+	"reuse_binlog_label":  true,
+	"table_filter":        true,
+	"table_rename":        true,
+	"owned_tables":        true,
+	"fanout_group":        true,
+	"bidirectional":       true,
+	"write_tables":        true,
+	"apply_delay_seconds": true,
+	"ddl_policy":          true,
+	"row_filter":          true,
+}
+
+// The options updated in place, => the zero value to remove the option.
+var mutableOptions = map[string]any{
+	"reuse_binlog_label":  false,
+	"apply_delay_seconds": 0,
+	"ddl_policy":          map[string]any{},
+}
+
+type specDetail struct {
+	Host       string `json:"host"`
+	Port       string `json:"port"`
+	ThriftPort string `json:"thrift_port"`
+	Frontends  []struct {
+		Host       string `json:"host"`
+		Port       string `json:"port"`
+		ThriftPort string `json:"thrift_port"`
+	} `json:"frontends"`
+	User        string            `json:"user"`
+	Cluster     string            `json:"cluster"`
+	Database    string            `json:"database"`
+	Table       string            `json:"table"`
+	HostMapping map[string]string `json:"host_mapping"`
+}
+
+type jobDetail struct {
+	Name  string         `json:"name"`
+	Src   specDetail     `json:"src"`
+	Dest  specDetail     `json:"dest"`
+	Extra map[string]any `json:"extra"`
+}
+
+type planItem struct {
+	Action  string   `json:"action"`
+	Name    string   `json:"name"`
+	Changes []string `json:"changes,omitempty"`
+
+	request map[string]any // the create request
+	update  map[string]any // the update request
+}
+
+type applyResult struct {
+	Plan    []*planItem `json:"plan"`
+	Applied bool        `json:"applied"`
+}
+
+func applyJobs(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	file := fs.String("f", "", "the json or yaml file of the desired jobs, - to read from stdin")
+	prune := fs.Bool("prune", false, "delete the jobs not listed in the file")
+	dryRun := fs.Bool("dry-run", false, "only print the plan")
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return newCliError(exitUsage, "the desired state file is required")
+	}
+
+	var desired desiredState
+	if err := readDocument(*file, &desired); err != nil {
+		return err
+	}
+	plan, err := makePlan(ctx.client, &desired, *prune)
+	if err != nil {
+		return err
+	}
+
+	result := applyResult{Plan: plan}
+	if !ctx.out.isJson() {
+		printPlan(ctx.out, plan)
+	}
+	if *dryRun || len(plan) == 0 {
+		if ctx.out.isJson() {
+			return ctx.out.printJson(result)
+		}
+		return nil
+	}
+
+	err = executePlan(ctx, plan)
+	result.Applied = err == nil
+	if ctx.out.isJson() {
+		if printErr := ctx.out.printJson(result); printErr != nil {
+			return printErr
+		}
+	}
+	return err
+}
+
+func makePlan(c *client, desired *desiredState, prune bool) ([]*planItem, error) {
+	desiredJobs := make(map[string]map[string]any)
+	for i, request := range desired.Jobs {
+		name, _ := request["name"].(string)
+		if name == "" {
+			return nil, newCliError(exitUsage, "the name of the job %d is empty", i)
+		}
+		if _, ok := desiredJobs[name]; ok {
+			return nil, newCliError(exitUsage, "duplicated job %s", name)
+		}
+		normalizeCreateRequest(request)
+		desiredJobs[name] = request
+	}
+
+	existJobs := make(map[string]bool)
+	for offset := 0; ; {
+		var page listJobsResult
+		path := fmt.Sprintf("%s/jobs?offset=%d&limit=1000", apiV2Prefix, offset)
+		if err := c.do(http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, job := range page.Jobs {
+			existJobs[job.Name] = true
+		}
+		// The invalid jobs exist too, they are not created again.
+		for _, job := range page.InvalidJobs {
+			existJobs[job.Name] = true
+		}
+		if page.NextOffset == 0 {
+			break
+		}
+		offset = page.NextOffset
+	}
+
+	names := make([]string, 0, len(desiredJobs))
+	for name := range desiredJobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	plan := make([]*planItem, 0)
+	for _, name := range names {
+		request := desiredJobs[name]
+		if !existJobs[name] {
+			plan = append(plan, &planItem{Action: planCreate, Name: name, request: request})
+			continue
+		}
+
+		var job jobDetail
+		if err := c.do(http.MethodGet, jobPath(name, ""), nil, &job); err != nil {
+			return nil, err
+		}
+		if item := diffJob(request, &job); item != nil {
+			plan = append(plan, item)
+		}
+	}
+
+	if prune {
+		pruned := make([]string, 0)
+		for name := range existJobs {
+			if _, ok := desiredJobs[name]; !ok {
+				pruned = append(pruned, name)
+			}
+		}
+		sort.Strings(pruned)
+		for _, name := range pruned {
+			plan = append(plan, &planItem{Action: planDelete, Name: name})
+		}
+	}
+	return plan, nil
+}
+
+// Diff the desired job with the exist one, returns nil if nothing is changed.
+func diffJob(request map[string]any, job *jobDetail) *planItem {
+	item := &planItem{Action: planUpdate, Name: job.Name, update: make(map[string]any)}
+	var conflicts []string
+
+	for _, side := range []string{"src", "dest"} {
+		spec, current := specOf(request, side), &job.Src
+		if side == "dest" {
+			current = &job.Dest
+		}
+
+		specUpdate := make(map[string]any)
+		if !hasFrontend(current, spec) {
+			for _, field := range []string{"host", "port", "thrift_port"} {
+				specUpdate[field] = stringField(spec, field)
+			}
+			item.Changes = append(item.Changes, fmt.Sprintf("%s.frontend: %s:%s -> %s:%s", side,
+				current.Host, current.Port, stringField(spec, "host"), stringField(spec, "port")))
+		}
+		if user := stringField(spec, "user"); user != current.User {
+			specUpdate["user"] = user
+			item.Changes = append(item.Changes, fmt.Sprintf("%s.user: %q -> %q", side, current.User, user))
+		}
+
+		currentFields := map[string]string{
+			"cluster": current.Cluster, "database": current.Database, "table": current.Table,
+		}
+		for _, field := range specIdentityFields {
+			if value := stringField(spec, field); value != currentFields[field] {
+				conflicts = append(conflicts, fmt.Sprintf("%s.%s: %q -> %q", side, field, currentFields[field], value))
+			}
+		}
+
+		// The host mapping is managed only if it is listed.
+		if rawMapping, ok := spec["host_mapping"]; ok {
+			desiredMapping := make(map[string]string)
+			if m, ok := rawMapping.(map[string]any); ok {
+				for private, public := range m {
+					desiredMapping[private] = fmt.Sprint(public)
+				}
+			}
+			if !reflect.DeepEqual(normalizeValue(current.HostMapping), normalizeValue(desiredMapping)) {
+				specUpdate["host_mapping"] = desiredMapping
+				item.Changes = append(item.Changes, fmt.Sprintf("%s.host_mapping: %s -> %s", side,
+					formatJson(current.HostMapping), formatJson(desiredMapping)))
+			}
+		}
+
+		if len(specUpdate) > 0 {
+			item.update[side] = specUpdate
+		}
+	}
+
+	// The options absent from the file or the job are compared as the zero value.
+	keySet := make(map[string]bool)
+	for key := range request {
+		if key != "name" && key != "src" && key != "dest" && !unpersistedFields[key] {
+			keySet[key] = true
+		}
+	}
+	for key := range job.Extra {
+		if declaredOptions[key] {
+			keySet[key] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	options := make(map[string]any)
+	for _, key := range keys {
+		desired, current := normalizeValue(request[key]), normalizeValue(job.Extra[key])
+		if reflect.DeepEqual(desired, current) {
+			continue
+		}
+
+		change := fmt.Sprintf("%s: %s -> %s", key, formatJson(current), formatJson(desired))
+		zero, ok := mutableOptions[key]
+		if !ok {
+			conflicts = append(conflicts, change)
+			continue
+		}
+		options[key] = request[key]
+		if desired == nil {
+			// remove the option by the zero value
+			options[key] = zero
+		}
+		item.Changes = append(item.Changes, change)
+	}
+	if len(options) > 0 {
+		item.update["options"] = options
+	}
+
+	if len(conflicts) > 0 {
+		item.Action = planConflict
+		item.Changes = append(conflicts, item.Changes...)
+	}
+	if len(item.Changes) == 0 {
+		return nil
+	}
+	return item
+}
+
+func specOf(request map[string]any, side string) map[string]any {
+	if spec, ok := request[side].(map[string]any); ok {
+		return spec
+	}
+	return map[string]any{}
+}
+
+func stringField(spec map[string]any, key string) string {
+	if value, ok := spec[key]; ok && value != nil {
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// The master frontend of the job changes after failover, so the desired frontend matches if it
+// is any frontend of the job.
+func hasFrontend(current *specDetail, spec map[string]any) bool {
+	host, port, thriftPort := stringField(spec, "host"), stringField(spec, "port"), stringField(spec, "thrift_port")
+	if host == current.Host && port == current.Port && thriftPort == current.ThriftPort {
+		return true
+	}
+	for _, frontend := range current.Frontends {
+		if host == frontend.Host && port == frontend.Port && thriftPort == frontend.ThriftPort {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize the json value to compare, the zero values are the same as the absent ones since
+// the job omits them.
+func normalizeValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return v
+	}
+
+	switch value := normalized.(type) {
+	case nil:
+		return nil
+	case bool:
+		if !value {
+			return nil
+		}
+	case float64:
+		if value == 0 {
+			return nil
+		}
+	case string:
+		if value == "" {
+			return nil
+		}
+	case map[string]any:
+		if len(value) == 0 {
+			return nil
+		}
+	case []any:
+		if len(value) == 0 {
+			return nil
+		}
+	}
+	return normalized
+}
+
+func formatJson(v any) string {
+	if v == nil {
+		return "null"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func printPlan(out *printer, plan []*planItem) {
+	if len(plan) == 0 {
+		fmt.Fprintln(out.w, "no changes, the jobs are up to date")
+		return
+	}
+
+	rows := make([][]string, 0, len(plan))
+	for _, item := range plan {
+		rows = append(rows, []string{item.Action, item.Name, strings.Join(item.Changes, "; ")})
+	}
+	out.printTable([]string{"ACTION", "NAME", "CHANGES"}, rows)
+}
+
+// Execute the plan, the conflicts are reported but not applied.
+func executePlan(ctx *cmdContext, plan []*planItem) error {
+	var conflicts []string
+	for _, item := range plan {
+		var err error
+		switch item.Action {
+		case planCreate:
+			err = ctx.client.do(http.MethodPost, apiV2Prefix+"/jobs", item.request, nil)
+		case planUpdate:
+			err = ctx.client.do(http.MethodPatch, jobPath(item.Name, ""), item.update, nil)
+		case planDelete:
+			err = ctx.client.do(http.MethodDelete, jobPath(item.Name, ""), nil, nil)
+		case planConflict:
+			conflicts = append(conflicts, item.Name)
+			continue
+		}
+		if err != nil {
+			return newCliError(exitCodeOf(err), "%s job %s failed: %v", item.Action, item.Name, err)
+		}
+		ctx.out.printDone("job %s: %s done", item.Name, item.Action)
+	}
+
+	if len(conflicts) > 0 {
+		return newCliError(exitFailed, "the immutable fields of jobs %s are changed, delete and recreate them manually",
+			strings.Join(conflicts, ", "))
+	}
+	return nil
+}
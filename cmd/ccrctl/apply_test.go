@@ -0,0 +1,214 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func parseRequest(t *testing.T, data string) map[string]any {
+	var request map[string]any
+	if err := json.Unmarshal([]byte(data), &request); err != nil {
+		t.Fatalf("parse request %s failed: %v", data, err)
+	}
+	normalizeCreateRequest(request)
+	return request
+}
+
+func TestNormalizeValue(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected any
+	}{
+		{nil, nil},
+		{false, nil},
+		{0, nil},
+		{"", nil},
+		{map[string]any{}, nil},
+		{[]string{}, nil},
+		{true, true},
+		{1800, float64(1800)},
+		{"apply", "apply"},
+		{map[string]string{"a": "b"}, map[string]any{"a": "b"}},
+		{[]string{"tbl"}, []any{"tbl"}},
+	}
+	for i, test := range tests {
+		if normalized := normalizeValue(test.value); !reflect.DeepEqual(normalized, test.expected) {
+			t.Errorf("test %d: normalize %#v, expect %#v, got %#v", i, test.value, test.expected, normalized)
+		}
+	}
+}
+
+func TestDiffJob(t *testing.T) {
+	job := &jobDetail{
+		Name: "ccr_test",
+		Src: specDetail{Host: "src1", Port: "9030", ThriftPort: "9020", User: "root", Database: "demo",
+			HostMapping: map[string]string{"10.0.0.1": "1.1.1.1"}},
+		Dest: specDetail{Host: "dest", Port: "9030", ThriftPort: "9020", User: "root", Database: "demo"},
+		Extra: map[string]any{
+			"reuse_binlog_label": true,
+			"table_filter":       map[string]any{"include_tables": []any{"orders"}},
+			"stop_at_commit_seq": 100, // not compared
+		},
+	}
+	job.Src.Frontends = append(job.Src.Frontends, struct {
+		Host       string `json:"host"`
+		Port       string `json:"port"`
+		ThriftPort string `json:"thrift_port"`
+	}{Host: "src2", Port: "9030", ThriftPort: "9020"})
+
+	const (
+		src   = `"src": {"host": "src1", "port": 9030, "thrift_port": 9020, "user": "root", "database": "demo"}`
+		dest  = `"dest": {"host": "dest", "port": 9030, "thrift_port": 9020, "user": "root", "database": "demo"}`
+		extra = `"reuse_binlog_label": true, "table_filter": {"include_tables": ["orders"]}`
+	)
+
+	tests := []struct {
+		name    string
+		request string
+		action  string // empty if nothing is changed
+		update  string // the json of the update request
+	}{
+		{"unchanged", `{"name": "ccr_test", ` + src + `, ` + dest + `, ` + extra + `}`, "", ""},
+		{"another frontend",
+			`{"name": "ccr_test", "src": {"host": "src2", "port": 9030, "thrift_port": 9020, "user": "root", "database": "demo"}, ` +
+				dest + `, ` + extra + `}`, "", ""},
+		{"create only fields", `{"name": "ccr_test", ` + src + `, ` + dest + `, ` + extra + `, "skip_error": true}`, "", ""},
+		{"password is not compared",
+			`{"name": "ccr_test", "src": {"host": "src1", "port": 9030, "thrift_port": 9020, "user": "root", "password": "x", "database": "demo"}, ` +
+				dest + `, ` + extra + `}`, "", ""},
+		{"frontend",
+			`{"name": "ccr_test", ` + src + `, "dest": {"host": "dest2", "port": 9030, "thrift_port": 9020, "user": "root", "database": "demo"}, ` +
+				extra + `}`, planUpdate, `{"dest": {"host": "dest2", "port": "9030", "thrift_port": "9020"}}`},
+		{"user",
+			`{"name": "ccr_test", ` + src + `, "dest": {"host": "dest", "port": 9030, "thrift_port": 9020, "user": "ccr", "database": "demo"}, ` +
+				extra + `}`, planUpdate, `{"dest": {"user": "ccr"}}`},
+		{"host mapping",
+			`{"name": "ccr_test", "src": {"host": "src1", "port": 9030, "thrift_port": 9020, "user": "root", "database": "demo", "host_mapping": {}}, ` +
+				dest + `, ` + extra + `}`, planUpdate, `{"src": {"host_mapping": {}}}`},
+		// the options absent from the file are removed, or conflict if they are immutable
+		{"absent option",
+			`{"name": "ccr_test", ` + src + `, ` + dest + `, "table_filter": {"include_tables": ["orders"]}}`,
+			planUpdate, `{"options": {"reuse_binlog_label": false}}`},
+		{"absent immutable option", `{"name": "ccr_test", ` + src + `, ` + dest + `, "reuse_binlog_label": true}`,
+			planConflict, ""},
+		{"remove option",
+			`{"name": "ccr_test", ` + src + `, ` + dest + `, "reuse_binlog_label": false, "table_filter": {"include_tables": ["orders"]}}`,
+			planUpdate, `{"options": {"reuse_binlog_label": false}}`},
+		{"add option", `{"name": "ccr_test", ` + src + `, ` + dest + `, ` + extra + `, "apply_delay_seconds": 60}`,
+			planUpdate, `{"options": {"apply_delay_seconds": 60}}`},
+		{"table filter",
+			`{"name": "ccr_test", ` + src + `, ` + dest + `, "reuse_binlog_label": true, "table_filter": {"include_tables": ["items"]}}`,
+			planConflict, ""},
+		{"row filter",
+			`{"name": "ccr_test", ` + src + `, ` + dest + `, ` + extra + `, "row_filter": {"catalog": "c", "predicates": {"orders": [{"column": "id", "op": ">", "values": ["0"]}]}}}`,
+			planConflict, ""},
+		{"database",
+			`{"name": "ccr_test", ` + src + `, "dest": {"host": "dest", "port": 9030, "thrift_port": 9020, "user": "root", "database": "dr"}, ` +
+				extra + `}`, planConflict, ""},
+	}
+	for _, test := range tests {
+		item := diffJob(parseRequest(t, test.request), job)
+		if item == nil {
+			if test.action != "" {
+				t.Errorf("%s: expect %s, got no changes", test.name, test.action)
+			}
+			continue
+		}
+		if item.Action != test.action {
+			t.Errorf("%s: expect %s, got %s, changes: %v", test.name, test.action, item.Action, item.Changes)
+			continue
+		}
+		if test.update == "" {
+			continue
+		}
+		if update := formatJson(item.update); !reflect.DeepEqual(normalizeValue(item.update), parseRequest(t, test.update)) {
+			t.Errorf("%s: expect update %s, got %s", test.name, test.update, update)
+		}
+	}
+}
+
+func TestMakePlan(t *testing.T) {
+	jobs := map[string]*jobDetail{
+		"unchanged": {Name: "unchanged", Src: specDetail{Host: "src", Port: "9030", ThriftPort: "9020", Database: "a"},
+			Dest: specDetail{Host: "dest", Port: "9030", ThriftPort: "9020", Database: "a"}},
+		"changed": {Name: "changed", Src: specDetail{Host: "src", Port: "9030", ThriftPort: "9020", Database: "b"},
+			Dest: specDetail{Host: "dest", Port: "9030", ThriftPort: "9020", Database: "b"}},
+		"pruned": {Name: "pruned"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reply any
+		switch r.URL.Path {
+		case apiV2Prefix + "/jobs":
+			// two pages
+			if r.URL.Query().Get("offset") == "0" {
+				reply = listJobsResult{Jobs: []jobSummary{{Name: "changed"}, {Name: "pruned"}}, NextOffset: 2}
+			} else {
+				reply = listJobsResult{Jobs: []jobSummary{{Name: "unchanged"}}}
+			}
+		default:
+			job, ok := jobs[r.URL.Path[len(apiV2Prefix+"/jobs/"):]]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			reply = job
+		}
+		json.NewEncoder(w).Encode(reply)
+	}))
+	defer server.Close()
+
+	c, err := newClient(&clientOptions{addr: server.URL, timeout: time.Second})
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	desired := &desiredState{Jobs: []map[string]any{
+		parseRequest(t, `{"name": "unchanged", "src": {"host": "src", "port": 9030, "thrift_port": 9020, "database": "a"},
+			"dest": {"host": "dest", "port": 9030, "thrift_port": 9020, "database": "a"}}`),
+		parseRequest(t, `{"name": "changed", "src": {"host": "src", "port": 9030, "thrift_port": 9020, "database": "b"},
+			"dest": {"host": "dest", "port": 9030, "thrift_port": 9020, "database": "b"}, "apply_delay_seconds": 60}`),
+		parseRequest(t, `{"name": "created", "src": {"database": "c"}, "dest": {"database": "c"}}`),
+	}}
+
+	for _, prune := range []bool{false, true} {
+		plan, err := makePlan(c, desired, prune)
+		if err != nil {
+			t.Fatalf("make plan failed: %v", err)
+		}
+		actions := make([]string, 0, len(plan))
+		for _, item := range plan {
+			actions = append(actions, item.Action+" "+item.Name)
+		}
+		expected := []string{"update changed", "create created"}
+		if prune {
+			expected = append(expected, "delete pruned")
+		}
+		if !reflect.DeepEqual(actions, expected) {
+			t.Errorf("prune %t: expect plan %v, got %v", prune, expected, actions)
+		}
+	}
+
+	desired.Jobs = append(desired.Jobs, parseRequest(t, `{"name": "created"}`))
+	if _, err := makePlan(c, desired, false); err == nil {
+		t.Errorf("the duplicated jobs should be rejected")
+	}
+}
@@ -0,0 +1,142 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+
+// ccrctl is the command-line client of the syncer http api.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	envAddr         = "CCR_SYNCER_ADDR"
+	envToken        = "CCR_SYNCER_TOKEN"
+	envTrustedHosts = "CCR_SYNCER_TRUSTED_HOSTS"
+)
+
+type cmdContext struct {
+	client *client
+	out    *printer
+}
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	details string // the notes in the usage of the command, optional
+	run     func(ctx *cmdContext, fs *flag.FlagSet, args []string) error
+}
+
+func usage(w io.Writer, global *flag.FlagSet) {
+	fmt.Fprintf(w, "usage: ccrctl [flags] <command> [args]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nflags:\n")
+	global.SetOutput(w)
+	global.PrintDefaults()
+	fmt.Fprintf(w, "\nexit codes: 0 ok, 1 failed, 2 usage or invalid argument, 3 not found, "+
+		"4 unauthenticated or forbidden, 5 syncer unreachable, 6 invalid reply\n")
+}
+
+func getenv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	var opts clientOptions
+	var output string
+	global := flag.NewFlagSet("ccrctl", flag.ContinueOnError)
+	global.SetOutput(io.Discard)
+	global.StringVar(&opts.addr, "addr", getenv(envAddr, "127.0.0.1:9190"),
+		"the syncer address, host:port or the url, env "+envAddr)
+	global.StringVar(&opts.token, "token", os.Getenv(envToken), "the bearer token, env "+envToken)
+	global.StringVar(&opts.trustedHosts, "trusted-hosts", os.Getenv(envTrustedHosts),
+		"the comma separated syncer hosts which receive the token on the http redirects, env "+envTrustedHosts)
+	global.BoolVar(&opts.useTLS, "tls", false, "connect the syncer with https")
+	global.StringVar(&opts.caFile, "ca", "", "the ca file to verify the syncer cert, implies -tls")
+	global.StringVar(&opts.certFile, "cert", "", "the client cert file, implies -tls")
+	global.StringVar(&opts.keyFile, "key", "", "the client key file")
+	global.DurationVar(&opts.timeout, "timeout", 30*time.Second, "the timeout of each request")
+	global.StringVar(&output, "o", outputTable, "the output format, table or json")
+	global.BoolVar(&opts.verbose, "v", false, "print the requests and the redirects to stderr")
+
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			usage(stdout, global)
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "ccrctl: %v\n", err)
+		usage(stderr, global)
+		return exitUsage
+	}
+	if global.NArg() == 0 {
+		usage(stderr, global)
+		return exitUsage
+	}
+	if output != outputTable && output != outputJson {
+		fmt.Fprintf(stderr, "ccrctl: unknown output format %s\n", output)
+		return exitUsage
+	}
+
+	name := global.Arg(0)
+	if name == "help" {
+		usage(stdout, global)
+		return exitOK
+	}
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(stderr, "ccrctl: unknown command %s\n", name)
+		usage(stderr, global)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: ccrctl %s %s\n\n%s\n", cmd.name, cmd.args, cmd.summary)
+		if cmd.details != "" {
+			fmt.Fprintf(fs.Output(), "\n%s\n", cmd.details)
+		}
+		fs.PrintDefaults()
+	}
+
+	c, err := newClient(&opts)
+	if err == nil {
+		ctx := &cmdContext{client: c, out: &printer{w: stdout, format: output}}
+		err = cmd.run(ctx, fs, global.Args()[1:])
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "ccrctl: %v\n", err)
+	}
+	return exitCodeOf(err)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
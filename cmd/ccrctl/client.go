@@ -0,0 +1,271 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	apiV2Prefix  = "/api/v2"
+	maxRedirects = 10
+)
+
+// The exit codes of ccrctl.
+const (
+	exitOK           = 0
+	exitFailed       = 1 // the syncer failed to handle the request
+	exitUsage        = 2 // invalid command, flags or request
+	exitNotFound     = 3 // the job or the api is not found
+	exitDenied       = 4 // unauthenticated or forbidden
+	exitUnreachable  = 5 // the syncer is unreachable
+	exitInvalidReply = 6 // the reply of the syncer is not understood
+)
+
+// cliError is an error with the exit code.
+type cliError struct {
+	code int
+	msg  string
+}
+
+func (e *cliError) Error() string {
+	return e.msg
+}
+
+func newCliError(code int, format string, args ...any) *cliError {
+	return &cliError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+func exitCodeOf(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if cliErr, ok := err.(*cliError); ok {
+		return cliErr.code
+	}
+	return exitFailed
+}
+
+// The exit code of the http status of a failed request.
+func exitCodeOfStatus(status int) int {
+	switch status {
+	case http.StatusBadRequest, http.StatusMethodNotAllowed:
+		return exitUsage
+	case http.StatusNotFound:
+		return exitNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return exitDenied
+	default:
+		return exitFailed
+	}
+}
+
+type clientOptions struct {
+	addr     string
+	token    string
+	useTLS   bool
+	caFile   string
+	certFile string
+	keyFile  string
+	timeout  time.Duration
+	verbose  bool
+	// The comma separated hosts which receive the token on the plain http redirects.
+	trustedHosts string
+}
+
+type client struct {
+	baseUrl      string
+	token        string
+	trustedHosts map[string]bool
+	verbose      bool
+	http         *http.Client
+}
+
+func newClient(opts *clientOptions) (*client, error) {
+	addr := strings.TrimSuffix(opts.addr, "/")
+	if !strings.Contains(addr, "://") {
+		scheme := "http"
+		if opts.useTLS || opts.caFile != "" || opts.certFile != "" {
+			scheme = "https"
+		}
+		addr = scheme + "://" + addr
+	}
+	baseUrl, err := url.Parse(addr)
+	if err != nil {
+		return nil, newCliError(exitUsage, "invalid addr %s: %v", opts.addr, err)
+	}
+	trustedHosts := map[string]bool{baseUrl.Hostname(): true}
+	for _, host := range strings.Split(opts.trustedHosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			trustedHosts[host] = true
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if strings.HasPrefix(addr, "https://") {
+		tlsConfig, err := newTLSConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	c := &client{
+		baseUrl:      addr,
+		token:        opts.token,
+		trustedHosts: trustedHosts,
+		verbose:      opts.verbose,
+	}
+	c.http = &http.Client{
+		Transport:     transport,
+		Timeout:       opts.timeout,
+		CheckRedirect: c.checkRedirect,
+	}
+	return c, nil
+}
+
+func newTLSConfig(opts *clientOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.caFile != "" {
+		caPem, err := os.ReadFile(opts.caFile)
+		if err != nil {
+			return nil, newCliError(exitUsage, "read ca file %s failed: %v", opts.caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPem) {
+			return nil, newCliError(exitUsage, "no cert found in ca file %s", opts.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if opts.certFile != "" || opts.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.certFile, opts.keyFile)
+		if err != nil {
+			return nil, newCliError(exitUsage, "load client cert %s failed: %v", opts.certFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// The syncers redirect the requests of the jobs owned by the other syncers with 307, which keeps
+// the method and the body. The token is dropped by net/http if the host changes, so add it back,
+// all syncers of the cluster share the same auth config. The token is only sent over https, or
+// over the original scheme to the trusted hosts, not to an arbitrary redirect target.
+func (c *client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return newCliError(exitFailed, "stopped after %d redirects", maxRedirects)
+	}
+	if c.verbose {
+		fmt.Fprintf(os.Stderr, "redirect to %s\n", req.URL)
+	}
+	if c.token == "" {
+		return nil
+	}
+	if target := req.URL; target.Scheme == "https" ||
+		(target.Scheme == via[0].URL.Scheme && c.trustedHosts[target.Hostname()]) {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else {
+		req.Header.Del("Authorization")
+		if c.verbose {
+			fmt.Fprintf(os.Stderr, "the token is not sent to the untrusted host %s\n", target.Host)
+		}
+	}
+	return nil
+}
+
+type apiErrorResult struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Do the request and decode the reply into result if it is not nil.
+func (c *client) do(method, path string, request any, result any) error {
+	var body io.Reader
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return newCliError(exitUsage, "marshal request failed: %v", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseUrl+path, body)
+	if err != nil {
+		return newCliError(exitUsage, "new request failed: %v", err)
+	}
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.verbose {
+		fmt.Fprintf(os.Stderr, "%s %s\n", method, req.URL)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			if cliErr, ok := urlErr.Err.(*cliError); ok {
+				return cliErr
+			}
+		}
+		return newCliError(exitUnreachable, "%v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return newCliError(exitUnreachable, "read reply of %s failed: %v", req.URL, err)
+	}
+
+	if resp.StatusCode >= 300 {
+		var errResult apiErrorResult
+		if err := json.Unmarshal(data, &errResult); err == nil && errResult.Error.Code != "" {
+			return newCliError(exitCodeOfStatus(resp.StatusCode), "%s: %s", errResult.Error.Code, errResult.Error.Message)
+		}
+		return newCliError(exitCodeOfStatus(resp.StatusCode), "%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return newCliError(exitInvalidReply, "decode reply of %s failed: %v", req.URL, err)
+	}
+	return nil
+}
+
+func jobPath(name string, sub string) string {
+	path := apiV2Prefix + "/jobs/" + url.PathEscape(name)
+	if sub != "" {
+		path += "/" + sub
+	}
+	return path
+}
@@ -0,0 +1,50 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCheckRedirectToken(t *testing.T) {
+	c, err := newClient(&clientOptions{addr: "syncer1:9190", token: "secret", trustedHosts: "syncer2"})
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	origin, _ := http.NewRequest(http.MethodGet, "http://syncer1:9190/api/v2/jobs/a", nil)
+
+	tests := []struct {
+		url   string
+		token bool
+	}{
+		{"http://syncer1:9191/api/v2/jobs/a", true},
+		{"http://syncer2:9190/api/v2/jobs/a", true},
+		{"https://other:9190/api/v2/jobs/a", true},
+		{"http://other:9190/api/v2/jobs/a", false},
+	}
+	for _, test := range tests {
+		req, _ := http.NewRequest(http.MethodGet, test.url, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		if err := c.checkRedirect(req, []*http.Request{origin}); err != nil {
+			t.Fatalf("check redirect to %s failed: %v", test.url, err)
+		}
+		if token := req.Header.Get("Authorization") != ""; token != test.token {
+			t.Errorf("redirect to %s, expect token %t, but got %t", test.url, test.token, token)
+		}
+	}
+}
@@ -0,0 +1,615 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The replies of the v2 api, only the fields printed in the table format.
+type jobSummary struct {
+	Name     string `json:"name"`
+	SyncType string `json:"sync_type"`
+	State    string `json:"state"`
+	SrcDb    string `json:"src_database"`
+	SrcTable string `json:"src_table,omitempty"`
+	DestDb   string `json:"dest_database"`
+	DestTbl  string `json:"dest_table,omitempty"`
+}
+
+type listJobsResult struct {
+	Jobs       []jobSummary `json:"jobs"`
+	Total      int          `json:"total"`
+	NextOffset int          `json:"next_offset,omitempty"`
+	// The jobs which can't be unmarshaled by the syncer.
+	InvalidJobs []struct {
+		Name  string `json:"name"`
+		Error string `json:"error"`
+	} `json:"invalid_jobs,omitempty"`
+}
+
+type lagResult struct {
+	Name              string `json:"name"`
+	Lag               int64  `json:"lag"`
+	LagSeconds        int64  `json:"lag_seconds"`
+	LastUpsertSeconds int64  `json:"last_upsert_seconds"`
+	Tables            []struct {
+		Table      string `json:"table"`
+		CommitSeq  int64  `json:"commit_seq"`
+		LagSeconds int64  `json:"lag_seconds"`
+	} `json:"tables"`
+	Error string `json:"error"`
+}
+
+type jobEvent struct {
+	Type      string `json:"type"`
+	Time      int64  `json:"time"`
+	Message   string `json:"message"`
+	CommitSeq int64  `json:"commit_seq"`
+	Reason    string `json:"reason"`
+}
+
+var commands = []*command{
+	{name: "list", args: "[-offset N] [-limit N]", summary: "List the jobs", run: listJobs},
+	{name: "create", args: "-f FILE [-name NAME] [-dry-run]",
+		summary: "Create a job from a json or yaml file, or only run the preflight checks", run: createJob},
+	{name: "apply", args: "-f FILE [-prune] [-dry-run]",
+		summary: "Reconcile the jobs with the desired jobs in a json or yaml file", details: applyDetails, run: applyJobs},
+	{name: "get", args: "NAME", summary: "Get the job, the passwords are redacted", run: getJob("")},
+	{name: "update", args: "NAME -f FILE",
+		summary: "Update the credentials, the frontends, the host mapping and the options in place", run: updateJob},
+	{name: "delete", args: "NAME", summary: "Delete the job", run: jobAction(http.MethodDelete, "", "deleted")},
+	{name: "status", args: "NAME", summary: "Get the status of the job", run: getJob("status")},
+	{name: "progress", args: "NAME", summary: "Get the progress of the job", run: getJob("progress")},
+	{name: "lag", args: "[NAME]", summary: "Get the lag of the job, or all jobs of the syncer", run: getLag},
+	{name: "events", args: "NAME [-type TYPE,...] [-since DURATION] [-limit N]",
+		summary: "Get the events of the job, the latest first", run: getEvents},
+	{name: "pending-binlog", args: "NAME", summary: "Get the binlog held by the ddl policy",
+		run: getJob("pending_binlog")},
+	{name: "pause", args: "NAME", summary: "Pause the job", run: jobAction(http.MethodPost, "pause", "paused")},
+	{name: "resume", args: "NAME", summary: "Resume the job", run: jobAction(http.MethodPost, "resume", "resumed")},
+	{name: "stop-at", args: "NAME -commit-seq N | -timestamp N",
+		summary: "Pause the job at the commit seq or the timestamp", run: stopAt},
+	{name: "approve-binlog", args: "NAME -commit-seq N [-operator NAME]", summary: "Approve the held binlog",
+		run: decideBinlog("approve_binlog", "approved")},
+	{name: "reject-binlog", args: "NAME -commit-seq N [-operator NAME]", summary: "Reject the held binlog",
+		run: decideBinlog("reject_binlog", "rejected")},
+	{name: "skip-binlog", args: "NAME [-commit-seq N] [-by silence|fullsync]", summary: "Skip the binlogs",
+		run: skipBinlog},
+	{name: "force-fullsync", args: "NAME", summary: "Force a full sync",
+		run: jobAction(http.MethodPost, "force_fullsync", "is going to full sync")},
+	{name: "desync", args: "NAME", summary: "Desync the dest tables",
+		run: jobAction(http.MethodPost, "desync", "desynced")},
+	{name: "host-mapping", args: "NAME [-src PRIVATE=PUBLIC]... [-dest PRIVATE=PUBLIC]...",
+		summary: "Update the host mapping, an empty PUBLIC removes the mapping", run: updateHostMapping},
+	{name: "version", args: "", summary: "Get the version of the syncer", run: getVersion},
+	{name: "openapi", args: "", summary: "Get the openapi document of the api", run: getOpenAPI},
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return newCliError(exitUsage, "%v", err)
+	}
+	return nil
+}
+
+// Parse the args without the job name, eg. `list -limit 10`.
+func parseNoArgs(fs *flag.FlagSet, args []string) error {
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return newCliError(exitUsage, "unexpected args: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// Parse the args with the job name, the name is allowed before or after the flags.
+func parseJobArgs(fs *flag.FlagSet, args []string) (string, error) {
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return "", err
+	}
+
+	rest := fs.Args()
+	if name == "" && len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+	if name == "" {
+		return "", newCliError(exitUsage, "the job name is required")
+	}
+	if len(rest) > 0 {
+		return "", newCliError(exitUsage, "unexpected args: %s", strings.Join(rest, " "))
+	}
+	return name, nil
+}
+
+func listJobs(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	offset := fs.Int("offset", 0, "skip the first N jobs")
+	limit := fs.Int("limit", 0, "list at most N jobs, 0 to list all jobs")
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+	if *offset < 0 || *limit < 0 {
+		return newCliError(exitUsage, "invalid offset %d or limit %d", *offset, *limit)
+	}
+
+	result := listJobsResult{Jobs: []jobSummary{}}
+	next := *offset
+	for {
+		pageLimit := 1000
+		if *limit > 0 {
+			if remain := *limit - len(result.Jobs); remain < pageLimit {
+				pageLimit = remain
+			}
+		}
+
+		var page listJobsResult
+		path := fmt.Sprintf("%s/jobs?offset=%d&limit=%d", apiV2Prefix, next, pageLimit)
+		if err := ctx.client.do(http.MethodGet, path, nil, &page); err != nil {
+			return err
+		}
+		result.Jobs = append(result.Jobs, page.Jobs...)
+		result.InvalidJobs = append(result.InvalidJobs, page.InvalidJobs...)
+		result.Total = page.Total
+		result.NextOffset = page.NextOffset
+		if page.NextOffset == 0 || (*limit > 0 && len(result.Jobs) >= *limit) {
+			break
+		}
+		next = page.NextOffset
+	}
+
+	if ctx.out.isJson() {
+		return ctx.out.printJson(result)
+	}
+	printJobSummaries(ctx.out, result.Jobs...)
+	for _, job := range result.InvalidJobs {
+		ctx.out.printDone("job %s is invalid: %s", job.Name, job.Error)
+	}
+	return nil
+}
+
+func printJobSummaries(out *printer, jobs ...jobSummary) {
+	rows := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		src, dest := job.SrcDb, job.DestDb
+		if job.SrcTable != "" {
+			src += "." + job.SrcTable
+		}
+		if job.DestTbl != "" {
+			dest += "." + job.DestTbl
+		}
+		rows = append(rows, []string{job.Name, job.SyncType, job.State, src, dest})
+	}
+	out.printTable([]string{"NAME", "SYNC_TYPE", "STATE", "SRC", "DEST"}, rows)
+}
+
+// Read the json or yaml file into v, `-` is the stdin.
+func readDocument(file string, v any) error {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return newCliError(exitUsage, "read %s failed: %v", file, err)
+	}
+
+	if strings.ToLower(filepath.Ext(file)) != ".json" {
+		// yaml is a superset of json, convert it to json to decode by the json tags
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return newCliError(exitUsage, "parse %s failed: %v", file, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return newCliError(exitUsage, "parse %s failed: %v", file, err)
+		}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return newCliError(exitUsage, "parse %s failed: %v", file, err)
+	}
+	return nil
+}
+
+// The ports are strings in the spec, allow them to be numbers in the file.
+func normalizeCreateRequest(request map[string]any) {
+	for _, side := range []string{"src", "dest"} {
+		spec, ok := request[side].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"port", "thrift_port"} {
+			if port, ok := spec[key].(float64); ok {
+				spec[key] = strconv.FormatInt(int64(port), 10)
+			}
+		}
+	}
+}
+
+func createJob(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	file := fs.String("f", "", "the json or yaml file of the create request, - to read from stdin")
+	name := fs.String("name", "", "the job name, overrides the name in the file")
+	dryRun := fs.Bool("dry-run", false, "only run the preflight checks, the job is not created")
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return newCliError(exitUsage, "the request file is required")
+	}
+
+	request := make(map[string]any)
+	if err := readDocument(*file, &request); err != nil {
+		return err
+	}
+	normalizeCreateRequest(request)
+	if *name != "" {
+		request["name"] = *name
+	}
+	if name, _ := request["name"].(string); name == "" {
+		return newCliError(exitUsage, "the job name is required")
+	}
+	if *dryRun {
+		return preflightJob(ctx, request)
+	}
+
+	var result jobSummary
+	if err := ctx.client.do(http.MethodPost, apiV2Prefix+"/jobs", request, &result); err != nil {
+		return err
+	}
+	if ctx.out.isJson() {
+		return ctx.out.printJson(result)
+	}
+	printJobSummaries(ctx.out, result)
+	return nil
+}
+
+type preflightReport struct {
+	Passed bool `json:"passed"`
+	Checks []struct {
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"checks"`
+}
+
+// preflightJob prints the preflight report, it fails if any check fails.
+func preflightJob(ctx *cmdContext, request map[string]any) error {
+	var report preflightReport
+	if err := ctx.client.do(http.MethodPost, apiV2Prefix+"/preflight", request, &report); err != nil {
+		return err
+	}
+	if ctx.out.isJson() {
+		if err := ctx.out.printJson(report); err != nil {
+			return err
+		}
+	} else {
+		rows := make([][]string, 0, len(report.Checks))
+		for _, check := range report.Checks {
+			rows = append(rows, []string{check.Name, strings.ToUpper(check.Status), check.Message})
+		}
+		ctx.out.printTable([]string{"CHECK", "STATUS", "MESSAGE"}, rows)
+	}
+	if !report.Passed {
+		return newCliError(exitFailed, "preflight checks failed")
+	}
+	return nil
+}
+
+func updateJob(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	file := fs.String("f", "", "the json or yaml file of the update request, - to read from stdin")
+	name, err := parseJobArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return newCliError(exitUsage, "the request file is required")
+	}
+
+	request := make(map[string]any)
+	if err := readDocument(*file, &request); err != nil {
+		return err
+	}
+	normalizeCreateRequest(request)
+	if err := ctx.client.do(http.MethodPatch, jobPath(name, ""), request, nil); err != nil {
+		return err
+	}
+	ctx.out.printDone("job %s updated", name)
+	return nil
+}
+
+// Get the job or the sub resource of the job, eg. status.
+func getJob(sub string) func(*cmdContext, *flag.FlagSet, []string) error {
+	return func(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+		name, err := parseJobArgs(fs, args)
+		if err != nil {
+			return err
+		}
+
+		var result json.RawMessage
+		if err := ctx.client.do(http.MethodGet, jobPath(name, sub), nil, &result); err != nil {
+			return err
+		}
+		return ctx.out.printObject(result)
+	}
+}
+
+func getLag(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	if len(args) == 0 {
+		return listLag(ctx, fs, args)
+	}
+	name, err := parseJobArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var result lagResult
+	if err := ctx.client.do(http.MethodGet, jobPath(name, "lag"), nil, &result); err != nil {
+		return err
+	}
+	if ctx.out.isJson() {
+		return ctx.out.printJson(result)
+	}
+	printLags(ctx.out, []lagResult{result})
+	if len(result.Tables) > 0 {
+		rows := make([][]string, 0, len(result.Tables))
+		for _, table := range result.Tables {
+			rows = append(rows, []string{table.Table, strconv.FormatInt(table.CommitSeq, 10),
+				strconv.FormatInt(table.LagSeconds, 10)})
+		}
+		fmt.Fprintln(ctx.out.w)
+		ctx.out.printTable([]string{"TABLE", "COMMIT_SEQ", "LAG_SECONDS"}, rows)
+	}
+	return nil
+}
+
+func listLag(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	var result struct {
+		Jobs []lagResult `json:"jobs"`
+	}
+	if err := ctx.client.do(http.MethodGet, apiV2Prefix+"/lag", nil, &result); err != nil {
+		return err
+	}
+	if ctx.out.isJson() {
+		return ctx.out.printJson(result)
+	}
+	printLags(ctx.out, result.Jobs)
+	return nil
+}
+
+func printLags(out *printer, lags []lagResult) {
+	rows := make([][]string, 0, len(lags))
+	for _, lag := range lags {
+		rows = append(rows, []string{lag.Name, strconv.FormatInt(lag.Lag, 10), strconv.FormatInt(lag.LagSeconds, 10),
+			strconv.FormatInt(lag.LastUpsertSeconds, 10), lag.Error})
+	}
+	out.printTable([]string{"NAME", "LAG", "LAG_SECONDS", "LAST_UPSERT_SECONDS", "ERROR"}, rows)
+}
+
+func getEvents(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	types := fs.String("type", "", "only the events of the comma separated types, eg. full_sync_started")
+	since := fs.Duration("since", 0, "only the events in the duration, eg. 24h, 0 for all events")
+	limit := fs.Int("limit", 0, "get at most N events, 0 for the default limit of the syncer")
+	name, err := parseJobArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *since < 0 || *limit < 0 {
+		return newCliError(exitUsage, "invalid since %s or limit %d", *since, *limit)
+	}
+
+	query := url.Values{}
+	if *types != "" {
+		query.Set("type", *types)
+	}
+	if *since > 0 {
+		query.Set("since", strconv.FormatInt(time.Now().Add(-*since).UnixMilli(), 10))
+	}
+	if *limit > 0 {
+		query.Set("limit", strconv.Itoa(*limit))
+	}
+	path := jobPath(name, "events")
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var result struct {
+		Events []jobEvent `json:"events"`
+	}
+	if err := ctx.client.do(http.MethodGet, path, nil, &result); err != nil {
+		return err
+	}
+	if ctx.out.isJson() {
+		return ctx.out.printJson(result)
+	}
+	rows := make([][]string, 0, len(result.Events))
+	for _, event := range result.Events {
+		commitSeq := ""
+		if event.CommitSeq > 0 {
+			commitSeq = strconv.FormatInt(event.CommitSeq, 10)
+		}
+		rows = append(rows, []string{time.UnixMilli(event.Time).Format(time.RFC3339), event.Type, commitSeq,
+			event.Reason, event.Message})
+	}
+	ctx.out.printTable([]string{"TIME", "TYPE", "COMMIT_SEQ", "REASON", "MESSAGE"}, rows)
+	return nil
+}
+
+// The action without the request body, eg. pause.
+func jobAction(method, sub, done string) func(*cmdContext, *flag.FlagSet, []string) error {
+	return func(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+		name, err := parseJobArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		if err := ctx.client.do(method, jobPath(name, sub), nil, nil); err != nil {
+			return err
+		}
+		ctx.out.printDone("job %s %s", name, done)
+		return nil
+	}
+}
+
+func stopAt(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	commitSeq := fs.Int64("commit-seq", 0, "pause after the binlog of the commit seq is synced")
+	timestamp := fs.Int64("timestamp", 0, "pause before the binlogs newer than the timestamp, in ms")
+	name, err := parseJobArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	request := map[string]int64{"commit_seq": *commitSeq, "timestamp": *timestamp}
+	if err := ctx.client.do(http.MethodPost, jobPath(name, "stop_at"), request, nil); err != nil {
+		return err
+	}
+	ctx.out.printDone("job %s will stop at commit seq %d, timestamp %d", name, *commitSeq, *timestamp)
+	return nil
+}
+
+func decideBinlog(sub, done string) func(*cmdContext, *flag.FlagSet, []string) error {
+	return func(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+		commitSeq := fs.Int64("commit-seq", 0, "the commit seq of the held binlog")
+		operator := fs.String("operator", os.Getenv("USER"), "the operator recorded in the decision, ignored if the syncer authenticates the requests")
+		name, err := parseJobArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		if *commitSeq <= 0 {
+			return newCliError(exitUsage, "the commit seq is required")
+		}
+
+		request := map[string]any{"commit_seq": *commitSeq, "operator": *operator}
+		if err := ctx.client.do(http.MethodPost, jobPath(name, sub), request, nil); err != nil {
+			return err
+		}
+		ctx.out.printDone("binlog %d of job %s %s", *commitSeq, name, done)
+		return nil
+	}
+}
+
+func skipBinlog(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	commitSeq := fs.Int64("commit-seq", 0, "skip the binlog of the commit seq, required if skip by silence")
+	skipBy := fs.String("by", "silence", "silence skips the binlog, fullsync skips by a full sync")
+	name, err := parseJobArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	request := map[string]any{"skip_commit_seq": *commitSeq, "skip_by": *skipBy}
+	if err := ctx.client.do(http.MethodPost, jobPath(name, "skip_binlog"), request, nil); err != nil {
+		return err
+	}
+	ctx.out.printDone("job %s skipped binlog %d by %s", name, *commitSeq, *skipBy)
+	return nil
+}
+
+// hostMappingFlag is the repeatable PRIVATE=PUBLIC flag.
+type hostMappingFlag map[string]string
+
+func (f hostMappingFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for private, public := range f {
+		pairs = append(pairs, private+"="+public)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f hostMappingFlag) Set(value string) error {
+	private, public, ok := strings.Cut(value, "=")
+	if !ok || private == "" {
+		return fmt.Errorf("invalid host mapping %s, it should be PRIVATE=PUBLIC", value)
+	}
+	f[private] = public
+	return nil
+}
+
+func updateHostMapping(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	src, dest := hostMappingFlag{}, hostMappingFlag{}
+	fs.Var(src, "src", "the host mapping of src, PRIVATE=PUBLIC, repeatable")
+	fs.Var(dest, "dest", "the host mapping of dest, PRIVATE=PUBLIC, repeatable")
+	name, err := parseJobArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(src) == 0 && len(dest) == 0 {
+		return newCliError(exitUsage, "the host mapping is required")
+	}
+
+	request := map[string]any{"src_host_mapping": src, "dest_host_mapping": dest}
+	if err := ctx.client.do(http.MethodPut, jobPath(name, "host_mapping"), request, nil); err != nil {
+		return err
+	}
+	ctx.out.printDone("host mapping of job %s updated", name)
+	return nil
+}
+
+func getVersion(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	var result json.RawMessage
+	if err := ctx.client.do(http.MethodGet, "/version", nil, &result); err != nil {
+		return err
+	}
+	return ctx.out.printObject(result)
+}
+
+func getOpenAPI(ctx *cmdContext, fs *flag.FlagSet, args []string) error {
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	var result json.RawMessage
+	if err := ctx.client.do(http.MethodGet, apiV2Prefix+"/openapi.json", nil, &result); err != nil {
+		return err
+	}
+	return ctx.out.printJson(result)
+}
@@ -0,0 +1,105 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJson  = "json"
+)
+
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func (p *printer) isJson() bool {
+	return p.format == outputJson
+}
+
+func (p *printer) printJson(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return newCliError(exitInvalidReply, "marshal output failed: %v", err)
+	}
+	fmt.Fprintln(p.w, string(data))
+	return nil
+}
+
+func (p *printer) printTable(headers []string, rows [][]string) {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+}
+
+// Print the fields of the object as KEY VALUE rows in the table format, the nested values are
+// printed as the compact json.
+func (p *printer) printObject(v any) error {
+	if p.isJson() {
+		return p.printJson(v)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return newCliError(exitInvalidReply, "marshal output failed: %v", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		fmt.Fprintln(p.w, string(data))
+		return nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, []string{key, formatValue(fields[key])})
+	}
+	p.printTable([]string{"KEY", "VALUE"}, rows)
+	return nil
+}
+
+func formatValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// Print the message of the actions in the table format, eg. `job ccr_test paused`.
+func (p *printer) printDone(format string, args ...any) {
+	if p.isJson() {
+		return
+	}
+	fmt.Fprintf(p.w, format+"\n", args...)
+}
@@ -2,5 +2,10 @@
 
 curl -X POST -H "Content-Type: application/json" -d '{
     "name": "ccr_test",
-    "skip": true
+    "src": {
+        "password": "env:SRC_PASSWORD"
+    },
+    "options": {
+        "apply_delay_seconds": 0
+    }
 }' http://127.0.0.1:9190/update_job
@@ -0,0 +1,191 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package base
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"flag"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+const (
+	// The env of the credential key, used if the credential_key_file is not set.
+	CredentialKeyEnv = "CCR_SYNCER_CREDENTIAL_KEY"
+
+	// The secret is redacted to it in the http responses.
+	RedactedSecret = "******"
+
+	encryptedSecretPrefix = "enc:"
+	envSecretPrefix       = "env:"
+	fileSecretPrefix      = "file:"
+)
+
+var (
+	credentialKeyFile string
+
+	credentialKeyOnce sync.Once
+	credentialKey     []byte
+	credentialKeyErr  error
+)
+
+func init() {
+	flag.StringVar(&credentialKeyFile, "credential_key_file", "",
+		"the file of the key to encrypt the credentials at rest, env "+CredentialKeyEnv+" is used if not set")
+}
+
+// Set the credential key directly, the key loaded from the flag or env is ignored.
+func SetCredentialKey(key string) {
+	credentialKeyOnce.Do(func() {})
+	credentialKey, credentialKeyErr = deriveCredentialKey(key)
+}
+
+func deriveCredentialKey(key string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, nil
+	}
+	sum := sha256.Sum256([]byte(key))
+	return sum[:], nil
+}
+
+// The AES-256 key of the credentials, nil if no key is supplied.
+func getCredentialKey() ([]byte, error) {
+	credentialKeyOnce.Do(func() {
+		key := os.Getenv(CredentialKeyEnv)
+		if credentialKeyFile != "" {
+			data, err := os.ReadFile(credentialKeyFile)
+			if err != nil {
+				credentialKeyErr = xerror.Wrapf(err, xerror.Normal, "read credential key file %s failed", credentialKeyFile)
+				return
+			}
+			key = string(data)
+		}
+		credentialKey, credentialKeyErr = deriveCredentialKey(key)
+	})
+	return credentialKey, credentialKeyErr
+}
+
+// Whether the secret is a reference, eg. `env:DORIS_PASSWORD` or `file:/etc/doris/password`.
+func IsSecretReference(secret string) bool {
+	return strings.HasPrefix(secret, envSecretPrefix) || strings.HasPrefix(secret, fileSecretPrefix)
+}
+
+// Resolve the secret reference to the plain secret, the other secrets are returned as is.
+func ResolveSecret(secret string) (string, error) {
+	switch {
+	case strings.HasPrefix(secret, envSecretPrefix):
+		name := strings.TrimPrefix(secret, envSecretPrefix)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", xerror.Errorf(xerror.Normal, "the env %s of the secret is not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(secret, fileSecretPrefix):
+		path := strings.TrimPrefix(secret, fileSecretPrefix)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", xerror.Wrapf(err, xerror.Normal, "read the secret file %s failed", path)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return secret, nil
+	}
+}
+
+// Whether the credential key is supplied, the secrets are persisted in plaintext if not.
+func HasCredentialKey() (bool, error) {
+	key, err := getCredentialKey()
+	return key != nil, err
+}
+
+// Encrypt the plain secret with the credential key. The empty, redacted, encrypted secrets and
+// the secret references are returned as is, so are all secrets if no key is supplied.
+func EncryptSecret(secret string) (string, error) {
+	if secret == "" || secret == RedactedSecret || IsSecretReference(secret) ||
+		strings.HasPrefix(secret, encryptedSecretPrefix) {
+		return secret, nil
+	}
+
+	key, err := getCredentialKey()
+	if err != nil || key == nil {
+		return secret, err
+	}
+
+	gcm, err := newCredentialCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", xerror.Wrap(err, xerror.Normal, "generate nonce failed")
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return encryptedSecretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt the secret encrypted by EncryptSecret, the other secrets are returned as is.
+func DecryptSecret(secret string) (string, error) {
+	if !strings.HasPrefix(secret, encryptedSecretPrefix) {
+		return secret, nil
+	}
+
+	key, err := getCredentialKey()
+	if err != nil {
+		return "", err
+	} else if key == nil {
+		return "", xerror.New(xerror.Normal, "the credential key is required to decrypt the secret")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, encryptedSecretPrefix))
+	if err != nil {
+		return "", xerror.Wrap(err, xerror.Normal, "decode the encrypted secret failed")
+	}
+	gcm, err := newCredentialCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", xerror.New(xerror.Normal, "the encrypted secret is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", xerror.Wrap(err, xerror.Normal, "decrypt the secret failed, the credential key might be wrong")
+	}
+	return string(plain), nil
+}
+
+func newCredentialCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, xerror.Wrap(err, xerror.Normal, "new credential cipher failed")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, xerror.Wrap(err, xerror.Normal, "new credential gcm failed")
+	}
+	return gcm, nil
+}
@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package base_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+)
+
+func TestSpecPasswordEncryptedAtRest(t *testing.T) {
+	base.SetCredentialKey("test-credential-key")
+	defer base.SetCredentialKey("")
+	if hasKey, err := base.HasCredentialKey(); err != nil || !hasKey {
+		t.Fatalf("expect the credential key is supplied, err: %v", err)
+	}
+
+	spec := base.Spec{User: "root", Password: "secret-password", Database: "db"}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("marshal spec failed: %v", err)
+	}
+	if strings.Contains(string(data), "secret-password") {
+		t.Fatalf("the password is not encrypted: %s", data)
+	}
+	if strings.Contains(spec.String(), "secret-password") {
+		t.Fatalf("the password is not redacted in string: %s", spec.String())
+	}
+
+	var restored base.Spec
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("unmarshal spec failed: %v", err)
+	}
+	if restored.Password != "secret-password" || restored.GetPassword() != "secret-password" {
+		t.Fatalf("unexpected password after decrypt: %s", restored.Password)
+	}
+
+	base.SetCredentialKey("another-key")
+	if err := json.Unmarshal(data, &restored); err == nil {
+		t.Fatalf("expect error when decrypt with the wrong key")
+	}
+
+	restored.Password = "secret-password"
+	restored.Redact()
+	if data, err := json.Marshal(restored); err != nil || !strings.Contains(string(data), base.RedactedSecret) {
+		t.Fatalf("the password is not redacted: %s, err: %v", data, err)
+	}
+}
+
+func TestSpecPasswordReference(t *testing.T) {
+	t.Setenv("CCR_TEST_PASSWORD", "env-password")
+
+	spec := base.Spec{User: "root", Password: "env:CCR_TEST_PASSWORD"}
+	if err := spec.ResolvePassword(); err != nil {
+		t.Fatalf("resolve password failed: %v", err)
+	}
+	if spec.GetPassword() != "env-password" {
+		t.Fatalf("unexpected resolved password: %s", spec.GetPassword())
+	}
+
+	// The reference is persisted rather than the resolved password.
+	if data, err := json.Marshal(spec); err != nil || !strings.Contains(string(data), "env:CCR_TEST_PASSWORD") {
+		t.Fatalf("the password reference is not persisted: %s, err: %v", data, err)
+	}
+
+	spec.Password = "env:CCR_TEST_PASSWORD_NOT_EXISTS"
+	if err := spec.ResolvePassword(); err == nil {
+		t.Fatalf("expect error when the env is not set")
+	}
+}
@@ -18,6 +18,7 @@ package base
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -227,8 +228,11 @@ type Spec struct {
 	Frontend
 	Frontends []Frontend `json:"frontends"`
 
-	User     string `json:"user"`
+	User string `json:"user"`
+	// The password, or a reference to it, eg. `env:DORIS_PASSWORD` or `file:/etc/doris/password`.
+	// It is encrypted at rest if the credential key is supplied.
 	Password string `json:"password"`
+	password string // the resolved password
 	Cluster  string `json:"cluster"`
 
 	Database string `json:"database"`
@@ -242,11 +246,65 @@ type Spec struct {
 	observers []utils.Observer[SpecEvent]
 }
 
-func (s *Spec) String() string {
+func (s Spec) String() string {
 	return fmt.Sprintf("host: %s, port: %s, thrift_port: %s, user: %s, cluster: %s, database: %s, database id: %d, table: %s, table id: %d",
 		s.Host, s.Port, s.ThriftPort, s.User, s.Cluster, s.Database, s.DbId, s.Table, s.TableId)
 }
 
+func (s Spec) MarshalJSON() ([]byte, error) {
+	type plainSpec Spec
+	password, err := EncryptSecret(s.Password)
+	if err != nil {
+		return nil, err
+	}
+	spec := plainSpec(s)
+	spec.Password = password
+	return json.Marshal(spec)
+}
+
+func (s *Spec) UnmarshalJSON(data []byte) error {
+	type plainSpec Spec
+	if err := json.Unmarshal(data, (*plainSpec)(s)); err != nil {
+		return err
+	}
+	password, err := DecryptSecret(s.Password)
+	if err != nil {
+		return xerror.Wrapf(err, xerror.Normal, "decrypt the password of user %s failed", s.User)
+	}
+	s.Password = password
+	return nil
+}
+
+// Resolve the password reference, it must be called before the spec is copied.
+func (s *Spec) ResolvePassword() error {
+	password, err := ResolveSecret(s.Password)
+	if err != nil {
+		return xerror.Wrapf(err, xerror.Normal, "resolve the password of user %s failed", s.User)
+	}
+	s.password = password
+	return nil
+}
+
+// The password to connect the cluster.
+func (s *Spec) GetPassword() string {
+	if s.password != "" || s.Password == "" {
+		return s.password
+	}
+	password, err := ResolveSecret(s.Password)
+	if err != nil {
+		log.Warnf("resolve the password of user %s failed, err: %+v", s.User, err)
+	}
+	return password
+}
+
+// Redact the password, for the http responses.
+func (s *Spec) Redact() {
+	if s.Password != "" {
+		s.Password = RedactedSecret
+	}
+	s.password = ""
+}
+
 // valid table spec
 func (s *Spec) Valid() error {
 	if s.Host == "" {
@@ -287,7 +345,7 @@ func (s *Spec) Valid() error {
 // Since the underlying connections are cached by the DSN, we do not set the DB name in the DSN.
 // The user should not set any session variables in the connections directly.
 func (s *Spec) Connect() (*sql.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/", s.User, s.Password, s.Host, s.Port)
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/", s.User, s.GetPassword(), s.Host, s.Port)
 	return GetMysqlDB(dsn)
 }
 
@@ -495,6 +553,29 @@ func (s *Spec) queryResult(querySQL string, queryColumn string, errMsg string) (
 	return results, nil
 }
 
+// Get the names of all views in the database.
+func (s *Spec) GetAllViews() ([]string, error) {
+	querySql := fmt.Sprintf("SELECT table_name FROM information_schema.tables WHERE table_schema = '%s' AND table_type = 'VIEW'",
+		utils.EscapeStringValue(s.Database))
+	views, err := s.queryResult(querySql, "table_name", "QUERY VIEWS")
+	if err != nil {
+		return nil, xerror.Wrap(err, xerror.Normal, "query views from information schema failed")
+	}
+	return views, nil
+}
+
+func (s *Spec) GetCreateViewSql(viewName string) (string, error) {
+	query := fmt.Sprintf("SHOW CREATE VIEW %s.%s", utils.FormatKeywordName(s.Database), utils.FormatKeywordName(viewName))
+	results, err := s.queryResult(query, "Create View", "SHOW CREATE VIEW")
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "", xerror.Errorf(xerror.Normal, "the create view sql of %s is not found", viewName)
+	}
+	return results[0], nil
+}
+
 func (s *Spec) GetAllViewsFromTable(tableName string) ([]string, error) {
 	log.Debugf("get all view from table %s", tableName)
 
@@ -630,10 +711,14 @@ func (s *Spec) CreateDatabase() error {
 	return nil
 }
 
-func (s *Spec) CreateTableOrView(createTable *record.CreateTable, srcDatabase string) error {
+// The destTableName is optional, the table/view will be created with the dest table name if it is not empty.
+func (s *Spec) CreateTableOrView(createTable *record.CreateTable, srcDatabase, destTableName string) error {
 	//	Creating table will only occur when sync db.
 	//	When create view, the db name of sql is source db name, we should use dest db name to create view
 	createSql := createTable.Sql
+	if destTableName != "" {
+		createSql = RenameCreateTableOrViewSql(destTableName, createSql)
+	}
 	if createTable.IsCreateView() {
 		log.Debugf("create view, use dest db name to replace source db name")
 
@@ -693,6 +778,49 @@ func (s *Spec) CheckDatabaseExists() (bool, error) {
 	return database != "", nil
 }
 
+// Whether the user has the admin privilege, which is required by backup and restore.
+//
+// mysql> show grants;
+// +--------------+---------+----------+-------+--------------------------+-----+
+// | UserIdentity | Comment | Password | Roles | GlobalPrivs              | ... |
+// +--------------+---------+----------+-------+--------------------------+-----+
+// | 'root'@'%'   | ROOT    | No       | admin | Node_priv,Admin_priv     | ... |
+// +--------------+---------+----------+-------+--------------------------+-----+
+func (s *Spec) HasAdminPrivilege() (bool, error) {
+	db, err := s.Connect()
+	if err != nil {
+		return false, err
+	}
+
+	query := "SHOW GRANTS"
+	rows, err := db.Query(query)
+	if err != nil {
+		return false, xerror.Wrap(err, xerror.Normal, query)
+	}
+	defer rows.Close()
+
+	hasAdmin := false
+	for rows.Next() {
+		rowParser := utils.NewRowParser()
+		if err := rowParser.Parse(rows); err != nil {
+			return false, xerror.Wrap(err, xerror.Normal, query)
+		}
+		globalPrivs, err := rowParser.GetString("GlobalPrivs")
+		if err != nil {
+			return false, xerror.Wrap(err, xerror.Normal, query)
+		}
+		roles, _ := rowParser.GetString("Roles")
+		if strings.Contains(globalPrivs, "Admin_priv") || strings.Contains(roles, "admin") {
+			hasAdmin = true
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return false, xerror.Wrap(err, xerror.Normal, query)
+	}
+	return hasAdmin, nil
+}
+
 // check table exits in database dir by spec
 func (s *Spec) CheckTableExists() (bool, error) {
 	log.Debugf("check table exist by spec: %s", s.String())
@@ -1506,6 +1634,67 @@ func (s *Spec) DesyncTables(tables ...string) error {
 	return nil
 }
 
+// Overwrite the partitions of the dest table with the rows of the source table matched by the
+// predicate, the whole table is overwritten if the partitions are empty. The source table is read
+// via the catalog of the dest cluster, the partitions are read by the query table function, so
+// that the source cluster only scans the partitions.
+func (s *Spec) ReloadTableWithPredicate(destTableName, catalog, srcDatabase, srcTableName string,
+	partitions []string, predicate string) error {
+	dbName := utils.FormatKeywordName(s.Database)
+	destTableName = utils.FormatKeywordName(destTableName)
+
+	var reloadSql string
+	if len(partitions) == 0 {
+		srcTableName = fmt.Sprintf("%s.%s.%s", utils.FormatKeywordName(catalog),
+			utils.FormatKeywordName(srcDatabase), utils.FormatKeywordName(srcTableName))
+		reloadSql = fmt.Sprintf("INSERT OVERWRITE TABLE %s.%s SELECT * FROM %s WHERE %s",
+			dbName, destTableName, srcTableName, predicate)
+	} else {
+		partitionNames := make([]string, 0, len(partitions))
+		for _, partition := range partitions {
+			partitionNames = append(partitionNames, utils.FormatKeywordName(partition))
+		}
+		partitionList := strings.Join(partitionNames, ", ")
+		srcQuery := fmt.Sprintf("SELECT * FROM %s.%s PARTITION (%s) WHERE %s",
+			utils.FormatKeywordName(srcDatabase), utils.FormatKeywordName(srcTableName), partitionList, predicate)
+		reloadSql = fmt.Sprintf("INSERT OVERWRITE TABLE %s.%s PARTITION (%s) SELECT * FROM query(\"catalog\" = \"%s\", \"query\" = \"%s\")",
+			dbName, destTableName, partitionList, utils.EscapeStringValue(catalog), utils.EscapeStringValue(srcQuery))
+	}
+	log.Infof("reload table with predicate sql: %s", reloadSql)
+	return s.Exec(reloadSql)
+}
+
+// Get the data size in bytes of the table, which is reported by the backends periodically.
+func (s *Spec) GetTableDataSize(tableName string) (int64, error) {
+	query := fmt.Sprintf("SELECT DATA_LENGTH FROM information_schema.tables WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s'",
+		utils.EscapeStringValue(s.Database), utils.EscapeStringValue(tableName))
+	results, err := s.queryResult(query, "DATA_LENGTH", "query the data size")
+	if err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, xerror.Errorf(xerror.Normal, "table %s is not found in information_schema", tableName)
+	}
+	size, err := strconv.ParseInt(results[0], 10, 64)
+	if err != nil {
+		return 0, xerror.Wrapf(err, xerror.Normal, "parse the data size %s of table %s", results[0], tableName)
+	}
+	return size, nil
+}
+
+// Get the create table sql of the table, it is used to create the dest table without restoring any rows.
+func (s *Spec) GetCreateTableSql(tableName string) (string, error) {
+	query := fmt.Sprintf("SHOW CREATE TABLE %s.%s", utils.FormatKeywordName(s.Database), utils.FormatKeywordName(tableName))
+	results, err := s.queryResult(query, "Create Table", "SHOW CREATE TABLE")
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "", xerror.Errorf(xerror.Normal, "the create table sql of %s is not found", tableName)
+	}
+	return results[0], nil
+}
+
 func (s *Spec) ModifyTableProperty(destTableName string, modifyProperty *record.ModifyTableProperty) error {
 	dbName := utils.FormatKeywordName(s.Database)
 	destTableName = utils.FormatKeywordName(destTableName)
@@ -1581,6 +1770,30 @@ func AddDBPrefixToCreateTableOrViewSql(dbName, createSql string) string {
 	return createSql
 }
 
+func RenameCreateTableOrViewSql(tableName, createSql string) string {
+	// replace the table/view name of the create sql, the db prefix is not allowed.
+	re := regexp.MustCompile("^\\s*CREATE\\s+(VIEW|TABLE)\\s+`([^`]+)`\\s+")
+	matches := re.FindStringSubmatch(createSql)
+	if len(matches) == 3 && matches[2] != tableName {
+		resource := matches[1]
+		createSql = re.ReplaceAllLiteralString(createSql,
+			fmt.Sprintf("CREATE %s %s ", resource, utils.FormatKeywordName(tableName)))
+	}
+	return createSql
+}
+
+// Rename the tables of the source database referenced by the view sql, eg.
+// `internal`.`db`.`t`.`k1` => `internal`.`db`.`dr_t`.`k1`, the database is replaced later.
+func RenameViewTables(viewSql, srcDatabase string, rename func(string) string) string {
+	srcDatabase = regexp.QuoteMeta(strings.TrimSpace(srcDatabase))
+	re := regexp.MustCompile("((?:`internal`\\.`" + srcDatabase + "`|`default_cluster:" + srcDatabase +
+		"`|(?:^|[\\s(,])`" + srcDatabase + "`)\\.)`([^`]+)`")
+	return re.ReplaceAllStringFunc(viewSql, func(match string) string {
+		groups := re.FindStringSubmatch(match)
+		return groups[1] + utils.FormatKeywordName(rename(groups[2]))
+	})
+}
+
 func ReplaceAndEscapeComment(input string) string {
 	re := regexp.MustCompile(`COMMENT '(.*?)'`)
 
@@ -45,6 +45,54 @@ func TestAddDBPrefixToCreateTableOrViewSql(t *testing.T) {
 	}
 }
 
+func TestRenameCreateTableOrViewSql(t *testing.T) {
+	type TestCase struct {
+		name, origin, expect string
+	}
+
+	testCases := []TestCase{
+		{"dr_v", "CREATE VIEW `v` AS SELECT * FROM t", "CREATE VIEW `dr_v` AS SELECT * FROM t"},
+		{"dr_t", "CREATE TABLE `t` (...", "CREATE TABLE `dr_t` (..."},
+		{"dr_t", " CREATE TABLE `t` (...", "CREATE TABLE `dr_t` (..."},
+		{"dr_t", "CREATE TABLE `dr_t` (...", "CREATE TABLE `dr_t` (..."},
+		{"dr_t", "CREATE TABLE `db`.`t` (...", "CREATE TABLE `db`.`t` (..."},
+		{"dr$1_t", "CREATE TABLE `t` (...", "CREATE TABLE `dr$1_t` (..."},
+	}
+
+	for i, c := range testCases {
+		if actual := base.RenameCreateTableOrViewSql(c.name, c.origin); actual != c.expect {
+			t.Errorf("case %d failed, expect %s, but got %s", i, c.expect, actual)
+		}
+	}
+}
+
+func TestRenameViewTables(t *testing.T) {
+	type TestCase struct {
+		origin, expect string
+	}
+
+	rename := func(table string) string { return "dr_" + table }
+	testCases := []TestCase{
+		{
+			"CREATE VIEW `v` AS SELECT `internal`.`src`.`t`.`k1` AS `k1` FROM `internal`.`src`.`t`",
+			"CREATE VIEW `v` AS SELECT `internal`.`src`.`dr_t`.`k1` AS `k1` FROM `internal`.`src`.`dr_t`",
+		},
+		{
+			"SELECT `default_cluster:src`.`t`.`k1` FROM `default_cluster:src`.`t`",
+			"SELECT `default_cluster:src`.`dr_t`.`k1` FROM `default_cluster:src`.`dr_t`",
+		},
+		{"SELECT k1 FROM `src`.`t` JOIN (`src`.`u`)", "SELECT k1 FROM `src`.`dr_t` JOIN (`src`.`dr_u`)"},
+		{"SELECT k1 FROM `internal`.`other`.`t`", "SELECT k1 FROM `internal`.`other`.`t`"},
+		{"SELECT k1 FROM `internal`.`src_2`.`t`", "SELECT k1 FROM `internal`.`src_2`.`t`"},
+	}
+
+	for i, c := range testCases {
+		if actual := base.RenameViewTables(c.origin, "src", rename); actual != c.expect {
+			t.Errorf("case %d failed, expect %s, but got %s", i, c.expect, actual)
+		}
+	}
+}
+
 func TestReplaceAndEscapeComment(t *testing.T) {
 	type TestCase struct {
 		origin, expect string
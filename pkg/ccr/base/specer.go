@@ -34,14 +34,17 @@ type Specer interface {
 	IsDatabaseEnableBinlog() (bool, error)
 	IsEnableRestoreSnapshotCompression() (bool, error)
 	GetAllTables() ([]string, error)
+	GetAllViews() ([]string, error)
+	GetCreateViewSql(viewName string) (string, error)
 	GetAllViewsFromTable(tableName string) ([]string, error)
 	ClearDB() error
 	CreateDatabase() error
-	CreateTableOrView(createTable *record.CreateTable, srcDatabase string) error
+	CreateTableOrView(createTable *record.CreateTable, srcDatabase, destTableName string) error
 	CheckDatabaseExists() (bool, error)
 	CheckTableExists() (bool, error)
 	CheckTablePropertyValid() ([]string, error)
 	CheckTableExistsByName(tableName string) (bool, error)
+	HasAdminPrivilege() (bool, error)
 	GetValidBackupJob(snapshotNamePrefix string) (string, error)
 	GetValidRestoreJob(snapshotNamePrefix string) (string, error)
 	CancelRestoreIfExists(snapshotName string) error
@@ -74,6 +77,9 @@ type Specer interface {
 	DropRollup(destTableName, rollupName string) error
 
 	DesyncTables(tables ...string) error
+	ReloadTableWithPredicate(destTableName, catalog, srcDatabase, srcTableName string, partitions []string, predicate string) error
+	GetCreateTableSql(tableName string) (string, error)
+	GetTableDataSize(tableName string) (int64, error)
 
 	utils.Subject[SpecEvent]
 }
@@ -0,0 +1,275 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"flag"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/rpc"
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+	tstatus "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/status"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	fanoutBinlogCacheSize     int
+	fanoutSnapshotShareWindow time.Duration
+
+	binlogFanouts = newBinlogFanoutRegistry()
+)
+
+func init() {
+	flag.IntVar(&fanoutBinlogCacheSize, "fanout_binlog_cache_size", 4096,
+		"the max number of binlogs cached for the jobs in a fanout group")
+	flag.DurationVar(&fanoutSnapshotShareWindow, "fanout_snapshot_share_window", 10*time.Minute,
+		"the full sync snapshot created by a job in a fanout group is reused by the others within the window")
+}
+
+// The jobs in a fanout group sync the same source database or table into several dest clusters.
+// The binlogs are read from the source FE once and dispatched to all jobs, and the full syncs
+// share the source snapshot.
+//
+// Each job keeps its own progress and runs in its own goroutine, a slow job just reads the
+// binlogs that have been evicted from the cache from the source FE.
+type binlogFanout struct {
+	name string
+
+	lock    sync.Mutex
+	start   int64 // the commit seq before the first cached binlog
+	binlogs []*festruct.TBinlog
+	members map[string]int64 // job name => the commit seq requested
+	// The binlogs being read from the source, commit seq => the fetch, the lock is not held
+	// during the rpc, and the members requesting the same commit seq wait for it.
+	fetches map[int64]*fanoutFetch
+
+	snapshotLock sync.Mutex
+	snapshots    map[string]*fanoutSnapshot // backup tables => snapshot
+}
+
+type fanoutFetch struct {
+	done chan struct{}
+	resp *festruct.TGetBinlogResult_
+	err  error
+}
+
+type fanoutSnapshot struct {
+	name      string
+	createdAt time.Time
+}
+
+func newBinlogFanout(name string) *binlogFanout {
+	return &binlogFanout{
+		name:      name,
+		members:   make(map[string]int64),
+		fetches:   make(map[int64]*fanoutFetch),
+		snapshots: make(map[string]*fanoutSnapshot),
+	}
+}
+
+func (f *binlogFanout) end() int64 {
+	if len(f.binlogs) == 0 {
+		return f.start
+	}
+	return f.binlogs[len(f.binlogs)-1].GetCommitSeq()
+}
+
+// GetBinlog returns the binlogs after the commit seq, from the cache if possible.
+func (f *binlogFanout) GetBinlog(jobName string, srcRpc rpc.IFeRpc, src *base.Spec, commitSeq int64) (*festruct.TGetBinlogResult_, error) {
+	f.lock.Lock()
+	f.members[jobName] = commitSeq
+	if f.start <= commitSeq && commitSeq < f.end() {
+		index := sort.Search(len(f.binlogs), func(i int) bool {
+			return f.binlogs[i].GetCommitSeq() > commitSeq
+		})
+		log.Debugf("fanout %s, job %s get %d binlogs after commit seq %d from cache",
+			f.name, jobName, len(f.binlogs)-index, commitSeq)
+		resp := newFanoutBinlogResult(f.binlogs[index:])
+		f.evict()
+		f.lock.Unlock()
+		return resp, nil
+	}
+
+	if fetch, ok := f.fetches[commitSeq]; ok {
+		f.lock.Unlock()
+		<-fetch.done
+		if fetch.err != nil || fetch.resp.GetStatus().GetStatusCode() != tstatus.TStatusCode_OK {
+			return fetch.resp, fetch.err
+		}
+		return newFanoutBinlogResult(fetch.resp.GetBinlogs()), nil
+	}
+	fetch := &fanoutFetch{done: make(chan struct{})}
+	f.fetches[commitSeq] = fetch
+	f.lock.Unlock()
+
+	fetch.resp, fetch.err = srcRpc.GetBinlog(src, commitSeq)
+
+	f.lock.Lock()
+	delete(f.fetches, commitSeq)
+	if fetch.err == nil {
+		f.cache(commitSeq, fetch.resp)
+	}
+	f.evict()
+	f.lock.Unlock()
+	close(fetch.done)
+	return fetch.resp, fetch.err
+}
+
+func newFanoutBinlogResult(binlogs []*festruct.TBinlog) *festruct.TGetBinlogResult_ {
+	resp := festruct.NewTGetBinlogResult_()
+	resp.Status = tstatus.NewTStatus()
+	resp.Status.StatusCode = tstatus.TStatusCode_OK
+	resp.Binlogs = append([]*festruct.TBinlog(nil), binlogs...)
+	return resp
+}
+
+// cache the binlogs read from the source after the commit seq.
+func (f *binlogFanout) cache(commitSeq int64, resp *festruct.TGetBinlogResult_) {
+	if resp.GetStatus().GetStatusCode() != tstatus.TStatusCode_OK || len(resp.GetBinlogs()) == 0 {
+		return
+	}
+
+	if commitSeq < f.start && len(f.binlogs) > 0 {
+		// keep the cache for the other members, the slow member reads from the source.
+		return
+	}
+	if commitSeq != f.end() {
+		f.start = commitSeq
+		f.binlogs = nil
+	}
+	for _, binlog := range resp.GetBinlogs() {
+		if binlog.GetCommitSeq() > f.end() {
+			f.binlogs = append(f.binlogs, binlog)
+		}
+	}
+}
+
+// evict the binlogs consumed by all members, and the oldest binlogs if the cache is full.
+func (f *binlogFanout) evict() {
+	minCommitSeq := f.end()
+	for _, commitSeq := range f.members {
+		if commitSeq < minCommitSeq {
+			minCommitSeq = commitSeq
+		}
+	}
+
+	index := sort.Search(len(f.binlogs), func(i int) bool {
+		return f.binlogs[i].GetCommitSeq() > minCommitSeq
+	})
+	if overflow := len(f.binlogs) - fanoutBinlogCacheSize; overflow > index {
+		index = overflow
+	}
+	if index > 0 {
+		f.start = f.binlogs[index-1].GetCommitSeq()
+		f.binlogs = append([]*festruct.TBinlog(nil), f.binlogs[index:]...)
+	}
+}
+
+// leave the fanout, returns true if the fanout is idle: no members, no fetches and no shared snapshots.
+func (f *binlogFanout) leave(jobName string) bool {
+	f.lock.Lock()
+	delete(f.members, jobName)
+	idle := len(f.members) == 0 && len(f.fetches) == 0
+	f.lock.Unlock()
+
+	f.snapshotLock.Lock()
+	defer f.snapshotLock.Unlock()
+	for _, snapshot := range f.snapshots {
+		if time.Since(snapshot.createdAt) < fanoutSnapshotShareWindow {
+			return false
+		}
+	}
+	return idle
+}
+
+// AcquireSnapshot returns the snapshot of the backup tables created by the members recently,
+// or creates a new one.
+func (f *binlogFanout) AcquireSnapshot(tables []string, create func() (string, error)) (string, error) {
+	f.snapshotLock.Lock()
+	defer f.snapshotLock.Unlock()
+
+	key := fanoutSnapshotKey(tables)
+	if snapshot, ok := f.snapshots[key]; ok && time.Since(snapshot.createdAt) < fanoutSnapshotShareWindow {
+		log.Infof("fanout %s reuse snapshot %s, created at %s", f.name, snapshot.name, snapshot.createdAt)
+		return snapshot.name, nil
+	}
+
+	name, err := create()
+	if err != nil {
+		return "", err
+	}
+	f.snapshots[key] = &fanoutSnapshot{name: name, createdAt: time.Now()}
+	return name, nil
+}
+
+// ForgetSnapshot stops sharing the snapshot, eg. it is expired or the backup is failed.
+func (f *binlogFanout) ForgetSnapshot(name string) {
+	f.snapshotLock.Lock()
+	defer f.snapshotLock.Unlock()
+
+	for key, snapshot := range f.snapshots {
+		if snapshot.name == name {
+			delete(f.snapshots, key)
+		}
+	}
+}
+
+func fanoutSnapshotKey(tables []string) string {
+	sorted := append([]string(nil), tables...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+type binlogFanoutRegistry struct {
+	lock    sync.Mutex
+	fanouts map[string]*binlogFanout
+}
+
+func newBinlogFanoutRegistry() *binlogFanoutRegistry {
+	return &binlogFanoutRegistry{
+		fanouts: make(map[string]*binlogFanout),
+	}
+}
+
+func (r *binlogFanoutRegistry) get(name string) *binlogFanout {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	fanout, ok := r.fanouts[name]
+	if !ok {
+		fanout = newBinlogFanout(name)
+		r.fanouts[name] = fanout
+	}
+	return fanout
+}
+
+// The fanout is dropped once the last job leaves, the fanout got by the other jobs before
+// is still usable, it is just not shared anymore.
+func (r *binlogFanoutRegistry) leave(name, jobName string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if fanout, ok := r.fanouts[name]; ok && fanout.leave(jobName) {
+		log.Infof("fanout %s is dropped since the last job %s leaves", name, jobName)
+		delete(r.fanouts, name)
+	}
+}
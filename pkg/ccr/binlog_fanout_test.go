@@ -0,0 +1,191 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/rpc"
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+	tstatus "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/status"
+	"github.com/selectdb/ccr_syncer/pkg/utils"
+)
+
+// fakeBinlogRpc returns at most 2 binlogs after the commit seq, the commit seqs are 1..maxCommitSeq.
+type fakeBinlogRpc struct {
+	rpc.IFeRpc
+	maxCommitSeq int64
+	calls        int
+}
+
+func (r *fakeBinlogRpc) GetBinlog(_ *base.Spec, commitSeq int64) (*festruct.TGetBinlogResult_, error) {
+	r.calls += 1
+	resp := festruct.NewTGetBinlogResult_()
+	resp.Status = tstatus.NewTStatus()
+	if commitSeq >= r.maxCommitSeq {
+		resp.Status.StatusCode = tstatus.TStatusCode_BINLOG_TOO_NEW_COMMIT_SEQ
+		return resp, nil
+	}
+	resp.Status.StatusCode = tstatus.TStatusCode_OK
+	for seq := commitSeq + 1; seq <= r.maxCommitSeq && seq <= commitSeq+2; seq++ {
+		binlog := festruct.NewTBinlog()
+		binlog.SetCommitSeq(utils.ThriftValueWrapper(seq))
+		resp.Binlogs = append(resp.Binlogs, binlog)
+	}
+	return resp, nil
+}
+
+func commitSeqsOf(resp *festruct.TGetBinlogResult_) []int64 {
+	seqs := make([]int64, 0)
+	for _, binlog := range resp.GetBinlogs() {
+		seqs = append(seqs, binlog.GetCommitSeq())
+	}
+	return seqs
+}
+
+func TestBinlogFanoutGetBinlog(t *testing.T) {
+	fanout := newBinlogFanout("test")
+	fakeRpc := &fakeBinlogRpc{maxCommitSeq: 5}
+	src := &base.Spec{}
+
+	resp, err := fanout.GetBinlog("a", fakeRpc, src, 0)
+	if err != nil || len(commitSeqsOf(resp)) != 2 {
+		t.Fatalf("get binlog failed, binlogs: %v, err: %v", commitSeqsOf(resp), err)
+	}
+
+	// The other job reads the binlogs from the cache.
+	resp, err = fanout.GetBinlog("b", fakeRpc, src, 0)
+	if err != nil || fakeRpc.calls != 1 {
+		t.Fatalf("expect reading from the cache, calls: %d, err: %v", fakeRpc.calls, err)
+	}
+	if seqs := commitSeqsOf(resp); len(seqs) != 2 || seqs[0] != 1 || seqs[1] != 2 {
+		t.Fatalf("unexpected binlogs from cache: %v", seqs)
+	}
+	resp, _ = fanout.GetBinlog("b", fakeRpc, src, 1)
+	if seqs := commitSeqsOf(resp); len(seqs) != 1 || seqs[0] != 2 || fakeRpc.calls != 1 {
+		t.Fatalf("unexpected binlogs from cache: %v, calls: %d", seqs, fakeRpc.calls)
+	}
+
+	// Both jobs have consumed the binlogs before 2, only the binlogs after 2 are fetched.
+	fanout.GetBinlog("b", fakeRpc, src, 2)
+	resp, _ = fanout.GetBinlog("a", fakeRpc, src, 2)
+	if seqs := commitSeqsOf(resp); len(seqs) != 2 || seqs[0] != 3 || fakeRpc.calls != 2 {
+		t.Fatalf("unexpected binlogs: %v, calls: %d", seqs, fakeRpc.calls)
+	}
+	if fanout.start != 2 {
+		t.Fatalf("the consumed binlogs are not evicted, start: %d", fanout.start)
+	}
+
+	// The slow job reads the evicted binlogs from the source.
+	fanout.leave("b")
+	resp, _ = fanout.GetBinlog("c", fakeRpc, src, 0)
+	if seqs := commitSeqsOf(resp); len(seqs) != 2 || seqs[0] != 1 || fakeRpc.calls != 3 {
+		t.Fatalf("unexpected binlogs: %v, calls: %d", seqs, fakeRpc.calls)
+	}
+	if fanout.start != 2 {
+		t.Fatalf("the cache is replaced by the slow job, start: %d", fanout.start)
+	}
+}
+
+func TestBinlogFanoutAcquireSnapshot(t *testing.T) {
+	fanout := newBinlogFanout("test")
+	creates := 0
+	create := func() (string, error) {
+		creates += 1
+		return "snapshot", nil
+	}
+
+	if name, err := fanout.AcquireSnapshot([]string{"t1", "t2"}, create); err != nil || name != "snapshot" {
+		t.Fatalf("acquire snapshot failed, name: %s, err: %v", name, err)
+	}
+	if _, err := fanout.AcquireSnapshot([]string{"t2", "t1"}, create); err != nil || creates != 1 {
+		t.Fatalf("expect reusing the snapshot, creates: %d, err: %v", creates, err)
+	}
+	if _, err := fanout.AcquireSnapshot([]string{"t1"}, create); err != nil || creates != 2 {
+		t.Fatalf("expect creating a new snapshot, creates: %d, err: %v", creates, err)
+	}
+
+	fanout.ForgetSnapshot("snapshot")
+	if _, err := fanout.AcquireSnapshot([]string{"t1", "t2"}, create); err != nil || creates != 3 {
+		t.Fatalf("expect creating a new snapshot after forgetting, creates: %d, err: %v", creates, err)
+	}
+}
+
+// blockingBinlogRpc blocks the GetBinlog until it is released.
+type blockingBinlogRpc struct {
+	fakeBinlogRpc
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingBinlogRpc) GetBinlog(src *base.Spec, commitSeq int64) (*festruct.TGetBinlogResult_, error) {
+	r.started <- struct{}{}
+	<-r.release
+	return r.fakeBinlogRpc.GetBinlog(src, commitSeq)
+}
+
+func TestBinlogFanoutConcurrentGetBinlog(t *testing.T) {
+	fanout := newBinlogFanout("test")
+	blockingRpc := &blockingBinlogRpc{
+		fakeBinlogRpc: fakeBinlogRpc{maxCommitSeq: 5},
+		started:       make(chan struct{}, 1),
+		release:       make(chan struct{}),
+	}
+	src := &base.Spec{}
+
+	results := make(chan []int64, 2)
+	go func() {
+		resp, _ := fanout.GetBinlog("a", blockingRpc, src, 0)
+		results <- commitSeqsOf(resp)
+	}()
+	<-blockingRpc.started
+
+	// The lock is not held during the rpc, the cache is still readable.
+	fanout.lock.Lock()
+	fanout.lock.Unlock()
+
+	go func() {
+		resp, _ := fanout.GetBinlog("b", blockingRpc, src, 0)
+		results <- commitSeqsOf(resp)
+	}()
+	close(blockingRpc.release)
+	for i := 0; i < 2; i++ {
+		if seqs := <-results; len(seqs) != 2 || seqs[0] != 1 {
+			t.Errorf("unexpected binlogs: %v", seqs)
+		}
+	}
+	if blockingRpc.calls != 1 {
+		t.Errorf("expect the rpc is called once, but got %d", blockingRpc.calls)
+	}
+}
+
+func TestBinlogFanoutRegistryLeave(t *testing.T) {
+	registry := newBinlogFanoutRegistry()
+	fakeRpc := &fakeBinlogRpc{maxCommitSeq: 5}
+	registry.get("group").GetBinlog("a", fakeRpc, &base.Spec{}, 0)
+	registry.get("group").GetBinlog("b", fakeRpc, &base.Spec{}, 0)
+
+	registry.leave("group", "a")
+	if _, ok := registry.fanouts["group"]; !ok {
+		t.Fatalf("the fanout is dropped before the last job leaves")
+	}
+	registry.leave("group", "b")
+	if _, ok := registry.fanouts["group"]; ok {
+		t.Fatalf("the fanout is not dropped after the last job leaves")
+	}
+}
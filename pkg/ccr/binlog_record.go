@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"github.com/selectdb/ccr_syncer/pkg/ccr/record"
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+// The type and data of the binlog, or the ones of the binlog wrapped by the barrier.
+func unwrapBinlog(binlog *festruct.TBinlog) (festruct.TBinlogType, string) {
+	if binlog.GetType() != festruct.TBinlogType_BARRIER {
+		return binlog.GetType(), binlog.GetData()
+	}
+
+	barrierLog, err := record.NewBarrierLogFromJson(binlog.GetData())
+	if err != nil || barrierLog.Binlog == "" {
+		return binlog.GetType(), binlog.GetData()
+	}
+	return festruct.TBinlogType(barrierLog.BinlogType), barrierLog.Binlog
+}
+
+// Parse the binlog data into the record of the binlog type, eg. record.DropTable.
+func parseBinlogRecord(binlogType festruct.TBinlogType, data string) (any, error) {
+	switch binlogType {
+	case festruct.TBinlogType_UPSERT:
+		return record.NewUpsertFromJson(data)
+	case festruct.TBinlogType_ADD_PARTITION:
+		return record.NewAddPartitionFromJson(data)
+	case festruct.TBinlogType_CREATE_TABLE:
+		return record.NewCreateTableFromJson(data)
+	case festruct.TBinlogType_DROP_PARTITION:
+		return record.NewDropPartitionFromJson(data)
+	case festruct.TBinlogType_DROP_TABLE:
+		return record.NewDropTableFromJson(data)
+	case festruct.TBinlogType_ALTER_JOB:
+		return record.NewAlterJobV2FromJson(data)
+	case festruct.TBinlogType_MODIFY_TABLE_ADD_OR_DROP_COLUMNS:
+		return record.NewModifyTableAddOrDropColumnsFromJson(data)
+	case festruct.TBinlogType_MODIFY_TABLE_PROPERTY:
+		return record.NewModifyTablePropertyFromJson(data)
+	case festruct.TBinlogType_REPLACE_PARTITIONS:
+		return record.NewReplacePartitionFromJson(data)
+	case festruct.TBinlogType_TRUNCATE_TABLE:
+		return record.NewTruncateTableFromJson(data)
+	case festruct.TBinlogType_RENAME_TABLE:
+		return record.NewRenameTableFromJson(data)
+	case festruct.TBinlogType_RENAME_COLUMN:
+		return record.NewRenameColumnFromJson(data)
+	case festruct.TBinlogType_MODIFY_COMMENT:
+		return record.NewModifyCommentFromJson(data)
+	case festruct.TBinlogType_MODIFY_VIEW_DEF:
+		return record.NewAlterViewFromJson(data)
+	case festruct.TBinlogType_REPLACE_TABLE:
+		return record.NewReplaceTableRecordFromJson(data)
+	case festruct.TBinlogType_MODIFY_TABLE_ADD_OR_DROP_INVERTED_INDICES:
+		return record.NewModifyTableAddOrDropInvertedIndicesFromJson(data)
+	case festruct.TBinlogType_INDEX_CHANGE_JOB:
+		return record.NewIndexChangeJobFromJson(data)
+	case festruct.TBinlogType_RENAME_ROLLUP:
+		return record.NewRenameRollupFromJson(data)
+	case festruct.TBinlogType_RENAME_PARTITION:
+		return record.NewRenamePartitionFromJson(data)
+	case festruct.TBinlogType_DROP_ROLLUP:
+		return record.NewDropRollupFromJson(data)
+	case festruct.TBinlogType_RECOVER_INFO:
+		return record.NewRecoverInfoFromJson(data)
+	default:
+		return nil, xerror.Errorf(xerror.Normal, "unsupported binlog type to parse: %s", binlogType)
+	}
+}
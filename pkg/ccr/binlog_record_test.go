@@ -0,0 +1,51 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/record"
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+	"github.com/selectdb/ccr_syncer/pkg/utils"
+)
+
+func TestUnwrapAndParseBinlogRecord(t *testing.T) {
+	dropTable := `{"dbId":1,"tableId":2,"tableName":"orders","isView":false,"rawSql":"DROP TABLE orders"}`
+
+	binlog := festruct.NewTBinlog()
+	binlog.SetType(utils.ThriftValueWrapper(festruct.TBinlogType_BARRIER))
+	binlog.SetData(utils.ThriftValueWrapper(`{"dbId":1,"tableId":2,"binlogType":4,"binlog":` +
+		`"{\"dbId\":1,\"tableId\":2,\"tableName\":\"orders\",\"isView\":false,\"rawSql\":\"DROP TABLE orders\"}"}`))
+
+	binlogType, data := unwrapBinlog(binlog)
+	if binlogType != festruct.TBinlogType_DROP_TABLE || data != dropTable {
+		t.Fatalf("unwrap barrier failed, type: %s, data: %s", binlogType, data)
+	}
+
+	r, err := parseBinlogRecord(binlogType, data)
+	if err != nil {
+		t.Fatalf("parse binlog record failed: %v", err)
+	}
+	if drop, ok := r.(*record.DropTable); !ok || drop.TableName != "orders" {
+		t.Fatalf("unexpected record: %#v", r)
+	}
+
+	if _, err := parseBinlogRecord(festruct.TBinlogType_DUMMY, ""); err == nil {
+		t.Fatalf("expect error for the dummy binlog")
+	}
+}
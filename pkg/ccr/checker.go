@@ -18,8 +18,10 @@ package ccr
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/selectdb/ccr_syncer/pkg/notify"
 	"github.com/selectdb/ccr_syncer/pkg/storage"
 	"github.com/selectdb/ccr_syncer/pkg/xerror"
 	log "github.com/sirupsen/logrus"
@@ -71,6 +73,18 @@ type Checker struct {
 	deadSyncers []string
 	err         error
 	stop        chan struct{}
+
+	// The heartbeat, the unix time in ms of the last successful check, and the last error.
+	lastCheckAt atomic.Int64
+	lastError   atomic.Pointer[string]
+}
+
+type CheckerStatus struct {
+	LastCheckAt int64 `json:"last_check_at"`
+	// Whether the last successful check is within the CHECK_TIMEOUT, the jobs of this syncer are
+	// taken over by the others if not.
+	Fresh     bool   `json:"fresh"`
+	LastError string `json:"last_error,omitempty"`
 }
 
 func NewChecker(hostInfo string, db storage.DB, jm *JobManager) *Checker {
@@ -130,7 +144,21 @@ func (c *Checker) handleUpdate() {
 	var jobs []string
 	c.lastStamp, jobs, c.err = c.db.GetStampAndJobs(c.hostInfo)
 	if len(jobs) != 0 {
+		// The jobs recovered after the first check are rebalanced from the dead syncers.
+		var rebalancedJobs []string
+		if c.lastCheckAt.Load() > 0 {
+			for _, job := range jobs {
+				if !c.jobManager.hasJob(job) {
+					rebalancedJobs = append(rebalancedJobs, job)
+				}
+			}
+		}
 		c.err = c.jobManager.Recover(jobs)
+		if c.err == nil {
+			for _, job := range rebalancedJobs {
+				notify.Notify(notify.EventJobRebalanced, job, "job is rebalanced to syncer %s", c.hostInfo)
+			}
+		}
 	}
 	log.Infof("update jobs %v", jobs)
 }
@@ -142,6 +170,11 @@ func (c *Checker) handleCheck() {
 func (c *Checker) handleRebalance() {
 	log.Infof("rebalance dead syncers: %v", c.deadSyncers)
 	c.err = c.db.RebalanceLoadFromDeadSyncers(c.deadSyncers)
+	if c.err == nil {
+		for _, syncer := range c.deadSyncers {
+			notify.Notify(notify.EventSyncerDead, "", "syncer %s is dead, its jobs are rebalanced by syncer %s", syncer, c.hostInfo)
+		}
+	}
 }
 
 func (c *Checker) check() error {
@@ -179,15 +212,40 @@ func (c *Checker) check() error {
 func (c *Checker) Start() error {
 	if err := c.db.AddSyncer(c.hostInfo); err != nil {
 		log.Errorf("add failed, host info: %s, err: %+v", c.hostInfo, err)
+		c.recordCheck(err)
 		return err
 	}
 	if err := c.check(); err != nil {
 		log.Errorf("checker first failed, host info: %s, err: %+v", c.hostInfo, err)
+		c.recordCheck(err)
 		return err
 	}
+	c.recordCheck(nil)
 	return c.run()
 }
 
+func (c *Checker) recordCheck(err error) {
+	if err != nil {
+		message := err.Error()
+		c.lastError.Store(&message)
+		return
+	}
+	c.lastCheckAt.Store(time.Now().UnixMilli())
+	c.lastError.Store(nil)
+}
+
+func (c *Checker) Status() *CheckerStatus {
+	lastCheckAt := c.lastCheckAt.Load()
+	status := &CheckerStatus{
+		LastCheckAt: lastCheckAt,
+		Fresh:       lastCheckAt > 0 && time.Now().UnixMilli()-lastCheckAt < CHECK_TIMEOUT.Milliseconds(),
+	}
+	if lastError := c.lastError.Load(); lastError != nil {
+		status.LastError = *lastError
+	}
+	return status
+}
+
 func (c *Checker) Stop() {
 	log.Info("checker stopping")
 	close(c.stop)
@@ -203,9 +261,11 @@ func (c *Checker) run() error {
 			log.Info("checker stopped")
 			return nil
 		case <-ticker.C:
-			if err := c.check(); err != nil {
+			err := c.check()
+			if err != nil {
 				log.Errorf("checker failed, host info: %s, err: %+v", c.hostInfo, err)
 			}
+			c.recordCheck(err)
 		}
 	}
 }
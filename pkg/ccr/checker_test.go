@@ -0,0 +1,47 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+func TestCheckerStatus(t *testing.T) {
+	checker := NewChecker("127.0.0.1:9190", nil, nil)
+	if status := checker.Status(); status.Fresh || status.LastCheckAt != 0 {
+		t.Errorf("expect not fresh before the first check, but got %+v", status)
+	}
+
+	checker.recordCheck(nil)
+	if status := checker.Status(); !status.Fresh || status.LastError != "" {
+		t.Errorf("expect fresh after the check, but got %+v", status)
+	}
+
+	checker.recordCheck(xerror.Errorf(xerror.DB, "meta db is down"))
+	status := checker.Status()
+	if !status.Fresh || status.LastError == "" {
+		t.Errorf("expect fresh with the last error, but got %+v", status)
+	}
+
+	checker.lastCheckAt.Store(time.Now().Add(-CHECK_TIMEOUT).UnixMilli())
+	if status := checker.Status(); status.Fresh {
+		t.Errorf("expect not fresh after the check timeout, but got %+v", status)
+	}
+}
@@ -0,0 +1,102 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"fmt"
+
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+type DDLAction string
+
+const (
+	DDLApply  DDLAction = "apply"
+	DDLIgnore DDLAction = "ignore"
+	DDLHold   DDLAction = "hold" // pause the job until the binlog is approved
+)
+
+func (a DDLAction) Valid() bool {
+	return a == DDLApply || a == DDLIgnore || a == DDLHold
+}
+
+// DDLPolicy decides how to handle the DDL binlogs of a job, by the binlog type.
+type DDLPolicy struct {
+	// The action of the binlog types not in Types, apply if empty.
+	Default DDLAction `json:"default,omitempty"`
+	// The binlog type name, eg. DROP_TABLE, => the action.
+	Types map[string]DDLAction `json:"types,omitempty"`
+}
+
+func (p *DDLPolicy) String() string {
+	if p == nil {
+		return "DDLPolicy{}"
+	}
+	return fmt.Sprintf("DDLPolicy{Default: %s, Types: %v}", p.Default, p.Types)
+}
+
+func (p *DDLPolicy) IsEmpty() bool {
+	return p == nil || ((p.Default == "" || p.Default == DDLApply) && len(p.Types) == 0)
+}
+
+func (p *DDLPolicy) Valid() error {
+	if p == nil {
+		return nil
+	}
+
+	if p.Default != "" && !p.Default.Valid() {
+		return xerror.Errorf(xerror.Normal, "invalid default ddl action %s", p.Default)
+	}
+	for name, action := range p.Types {
+		binlogType, err := festruct.TBinlogTypeFromString(name)
+		if err != nil {
+			return xerror.Errorf(xerror.Normal, "invalid binlog type %s", name)
+		}
+		if !isDDLBinlogType(binlogType) {
+			return xerror.Errorf(xerror.Normal, "binlog type %s is not a ddl", name)
+		}
+		if !action.Valid() {
+			return xerror.Errorf(xerror.Normal, "invalid ddl action %s of binlog type %s", action, name)
+		}
+	}
+	return nil
+}
+
+// Action returns the action of the binlog type, the non-ddl binlogs are always applied.
+func (p *DDLPolicy) Action(binlogType festruct.TBinlogType) DDLAction {
+	if p.IsEmpty() || !isDDLBinlogType(binlogType) {
+		return DDLApply
+	}
+
+	if action, ok := p.Types[binlogType.String()]; ok {
+		return action
+	}
+	if p.Default != "" {
+		return p.Default
+	}
+	return DDLApply
+}
+
+func isDDLBinlogType(binlogType festruct.TBinlogType) bool {
+	switch binlogType {
+	case festruct.TBinlogType_UPSERT, festruct.TBinlogType_DUMMY, festruct.TBinlogType_BARRIER:
+		return false
+	default:
+		return binlogType < festruct.TBinlogType_MIN_UNKNOWN
+	}
+}
@@ -0,0 +1,74 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr_test
+
+import (
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr"
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+)
+
+func TestDDLPolicyAction(t *testing.T) {
+	policy := &ccr.DDLPolicy{
+		Default: ccr.DDLIgnore,
+		Types: map[string]ccr.DDLAction{
+			"DROP_TABLE":   ccr.DDLHold,
+			"CREATE_TABLE": ccr.DDLApply,
+		},
+	}
+
+	type TestCase struct {
+		policy     *ccr.DDLPolicy
+		binlogType festruct.TBinlogType
+		expect     ccr.DDLAction
+	}
+	tests := []TestCase{
+		{policy: nil, binlogType: festruct.TBinlogType_DROP_TABLE, expect: ccr.DDLApply},
+		{policy: policy, binlogType: festruct.TBinlogType_DROP_TABLE, expect: ccr.DDLHold},
+		{policy: policy, binlogType: festruct.TBinlogType_CREATE_TABLE, expect: ccr.DDLApply},
+		{policy: policy, binlogType: festruct.TBinlogType_TRUNCATE_TABLE, expect: ccr.DDLIgnore},
+		{policy: policy, binlogType: festruct.TBinlogType_UPSERT, expect: ccr.DDLApply},
+		{policy: policy, binlogType: festruct.TBinlogType_BARRIER, expect: ccr.DDLApply},
+	}
+	for i, test := range tests {
+		if got := test.policy.Action(test.binlogType); got != test.expect {
+			t.Errorf("test %d failed, policy: %s, binlog type: %s, expect %s, but got %s",
+				i, test.policy, test.binlogType, test.expect, got)
+		}
+	}
+}
+
+func TestDDLPolicyValid(t *testing.T) {
+	type TestCase struct {
+		policy *ccr.DDLPolicy
+		valid  bool
+	}
+	tests := []TestCase{
+		{policy: nil, valid: true},
+		{policy: &ccr.DDLPolicy{Types: map[string]ccr.DDLAction{"DROP_TABLE": ccr.DDLHold}}, valid: true},
+		{policy: &ccr.DDLPolicy{Default: "unknown"}, valid: false},
+		{policy: &ccr.DDLPolicy{Types: map[string]ccr.DDLAction{"DROP_TABLES": ccr.DDLHold}}, valid: false},
+		{policy: &ccr.DDLPolicy{Types: map[string]ccr.DDLAction{"UPSERT": ccr.DDLIgnore}}, valid: false},
+		{policy: &ccr.DDLPolicy{Types: map[string]ccr.DDLAction{"DROP_TABLE": "drop"}}, valid: false},
+	}
+	for i, test := range tests {
+		if err := test.policy.Valid(); (err == nil) != test.valid {
+			t.Errorf("test %d failed, policy: %s, expect valid %t, but got err %v", i, test.policy, test.valid, err)
+		}
+	}
+}
@@ -20,12 +20,15 @@ import (
 	"context"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/modern-go/gls"
 	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
 	"github.com/selectdb/ccr_syncer/pkg/ccr/record"
 	utils "github.com/selectdb/ccr_syncer/pkg/utils"
 	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	"github.com/selectdb/ccr_syncer/pkg/xmetrics"
+	"github.com/selectdb/ccr_syncer/pkg/xtrace"
 
 	bestruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/backendservice"
 	tstatus "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/status"
@@ -104,12 +107,27 @@ type tabletIngestBinlogHandler struct {
 	destTablet      *TabletMeta
 	destPartitionId int64
 	destTableId     int64
+	srcTableId      int64
+	isBaseIndex     bool
 
 	*commitInfosCollector
 	*subTxnInfosCollector
 
 	cancel atomic.Bool
 	wg     sync.WaitGroup
+	err    atomic.Pointer[error] // the first error of the tablet, for the tracing
+}
+
+func (h *tabletIngestBinlogHandler) setError(err error) {
+	h.err.CompareAndSwap(nil, &err)
+	h.ingestJob.setError(err)
+}
+
+func (h *tabletIngestBinlogHandler) Error() error {
+	if err := h.err.Load(); err != nil {
+		return *err
+	}
+	return nil
 }
 
 // handle Replica
@@ -130,20 +148,20 @@ func (h *tabletIngestBinlogHandler) handleReplica(srcReplica, destReplica *Repli
 
 	destBackend := j.GetDestBackend(destReplica.BackendId)
 	if destBackend == nil {
-		j.setError(xerror.XWrapf(errBackendNotFound, "backend id: %d", destReplica.BackendId))
+		h.setError(xerror.XWrapf(errBackendNotFound, "backend id: %d", destReplica.BackendId))
 		return false
 	}
 	destTabletId := destReplica.TabletId
 
 	destRpc, err := h.ingestJob.ccrJob.factory.NewBeRpc(destBackend)
 	if err != nil {
-		j.setError(err)
+		h.setError(err)
 		return false
 	}
 	srcBackendId := srcReplica.BackendId
 	srcBackend := j.GetSrcBackend(srcBackendId)
 	if srcBackend == nil {
-		j.setError(xerror.XWrapf(errBackendNotFound, "backend id: %d", srcBackendId))
+		h.setError(xerror.XWrapf(errBackendNotFound, "backend id: %d", srcBackendId))
 		return false
 	}
 	loadId := ttypes.NewTUniqueId()
@@ -182,20 +200,22 @@ func (h *tabletIngestBinlogHandler) handleReplica(srcReplica, destReplica *Repli
 		cwind.Acquire()
 		defer cwind.Release()
 
+		ingestAt := time.Now()
 		resp, err := destRpc.IngestBinlog(req)
+		xmetrics.ObserveIngestBinlog(j.ccrJob.Name, time.Since(ingestAt))
 		if err != nil {
-			j.setError(err)
+			h.setError(err)
 			return
 		}
 
 		log.Debugf("ingest resp: %v", resp)
 		if !resp.IsSetStatus() {
 			err = xerror.Errorf(xerror.BE, "ingest resp status not set, req: %+v", req)
-			j.setError(err)
+			h.setError(err)
 			return
 		} else if resp.Status.StatusCode != tstatus.TStatusCode_OK {
 			err = xerror.Errorf(xerror.BE, "ingest error, req %v, resp status code: %v, msg: %v", req, resp.Status.StatusCode, resp.Status.ErrorMsgs)
-			j.setError(err)
+			h.setError(err)
 			return
 		} else {
 			h.appendCommitInfos(commitInfo)
@@ -222,8 +242,26 @@ func (h *tabletIngestBinlogHandler) handle() {
 		return true
 	})
 
+	srcBackendIds := make([]int64, 0, len(srcReplicas))
+	for _, srcReplica := range srcReplicas {
+		srcBackendIds = append(srcBackendIds, srcReplica.BackendId)
+	}
+	destBackendIds := make([]int64, 0, h.destTablet.ReplicaMetas.Len())
+	h.destTablet.ReplicaMetas.Scan(func(destReplicaId int64, destReplica *ReplicaMeta) bool {
+		destBackendIds = append(destBackendIds, destReplica.BackendId)
+		return true
+	})
+	span := xtrace.StartWithParent(h.ingestJob.traceCtx, "IngestBinlog",
+		xtrace.Job(h.ingestJob.ccrJob.Name),
+		xtrace.TxnId(h.ingestJob.txnId),
+		xtrace.TableId(h.destTableId),
+		xtrace.TabletId(h.destTablet.Id),
+		xtrace.SrcBackendIds(srcBackendIds),
+		xtrace.DestBackendIds(destBackendIds))
+	defer func() { span.End(h.Error()) }()
+
 	if len(srcReplicas) == 0 {
-		h.ingestJob.setError(xerror.Errorf(xerror.Meta, "no src replica version > %d", h.binlogVersion))
+		h.setError(xerror.Errorf(xerror.Meta, "no src replica version > %d", h.binlogVersion))
 		return
 	}
 
@@ -235,6 +273,10 @@ func (h *tabletIngestBinlogHandler) handle() {
 		return h.handleReplica(srcReplica, destReplica)
 	})
 	h.wg.Wait()
+	if featureIngestStats && h.isBaseIndex && h.Error() == nil {
+		// The rollups have the same rows, so only the tablets of the base index are counted.
+		h.collectIngestStats(srcReplicas[0])
+	}
 
 	h.ingestJob.appendCommitInfos(h.CommitInfos()...)
 	// for txn insert
@@ -244,6 +286,20 @@ func (h *tabletIngestBinlogHandler) handle() {
 	}
 }
 
+func (h *tabletIngestBinlogHandler) collectIngestStats(srcReplica *ReplicaMeta) {
+	srcBackend := h.ingestJob.GetSrcBackend(srcReplica.BackendId)
+	if srcBackend == nil {
+		return
+	}
+	stats, err := getBinlogRowsetStats(srcBackend, h.srcTablet.Id, h.binlogVersion)
+	if err != nil {
+		// the stats are only for the metrics, never fail the ingest
+		log.Warnf("get the ingest stats of tablet %d failed, err: %+v", h.srcTablet.Id, err)
+		return
+	}
+	h.ingestJob.addIngestStats(h.srcTableId, stats)
+}
+
 type IngestContext struct {
 	context.Context
 	txnId        int64
@@ -292,10 +348,16 @@ type IngestBinlogJob struct {
 	*commitInfosCollector
 	*subTxnInfosCollector
 
+	statsLock   sync.Mutex
+	ingestStats map[int64]*IngestStats // src table id => stats
+
 	err     error
 	errLock sync.RWMutex
 
 	wg sync.WaitGroup
+
+	// the parent of the tablet ingest spans, since they run in the other goroutines
+	traceCtx context.Context
 }
 
 func NewIngestBinlogJob(ctx context.Context, ccrJob *Job) (*IngestBinlogJob, error) {
@@ -316,6 +378,8 @@ func NewIngestBinlogJob(ctx context.Context, ccrJob *Job) (*IngestBinlogJob, err
 
 		commitInfosCollector: newCommitInfosCollector(),
 		subTxnInfosCollector: newSubTxnInfosCollector(),
+
+		traceCtx: xtrace.Current(),
 	}, nil
 }
 
@@ -339,6 +403,28 @@ func (j *IngestBinlogJob) GetTabletCommitInfos() []*ttypes.TTabletCommitInfo {
 	return j.commitInfos
 }
 
+func (j *IngestBinlogJob) addIngestStats(srcTableId int64, stats *IngestStats) {
+	j.statsLock.Lock()
+	defer j.statsLock.Unlock()
+
+	if j.ingestStats == nil {
+		j.ingestStats = make(map[int64]*IngestStats)
+	}
+	if _, ok := j.ingestStats[srcTableId]; !ok {
+		j.ingestStats[srcTableId] = &IngestStats{}
+	}
+	j.ingestStats[srcTableId].add(stats)
+}
+
+// The rows and bytes of the ingested binlogs by the src table id, the tables failed to get
+// the stats are absent.
+func (j *IngestBinlogJob) IngestStats() map[int64]*IngestStats {
+	j.statsLock.Lock()
+	defer j.statsLock.Unlock()
+
+	return j.ingestStats
+}
+
 func (j *IngestBinlogJob) setError(err error) {
 	j.errLock.Lock()
 	defer j.errLock.Unlock()
@@ -415,6 +501,8 @@ func (j *IngestBinlogJob) prepareIndex(arg *prepareIndexArg) {
 			destTablet:      destTablet,
 			destPartitionId: arg.destPartitionId,
 			destTableId:     arg.destTableId,
+			srcTableId:      arg.srcTableId,
+			isBaseIndex:     arg.srcIndexMeta.IsBaseIndex,
 
 			commitInfosCollector: newCommitInfosCollector(),
 			subTxnInfosCollector: newSubTxnInfosCollector(),
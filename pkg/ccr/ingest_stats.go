@@ -0,0 +1,124 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// The field numbers of RowsetMetaPB, see gensrc/proto/olap_file.proto of doris.
+	rowsetMetaNumRowsField       = 11
+	rowsetMetaTotalDiskSizeField = 12
+
+	binlogStatsTimeout = 10 * time.Second
+)
+
+var binlogStatsClient = &http.Client{Timeout: binlogStatsTimeout}
+
+// IngestStats is the rows and bytes of the ingested rowsets.
+type IngestStats struct {
+	Rows  int64 `json:"rows"`
+	Bytes int64 `json:"bytes"`
+}
+
+func (s *IngestStats) add(other *IngestStats) {
+	s.Rows += other.Rows
+	s.Bytes += other.Bytes
+}
+
+// Get the stats of the binlog rowset of the tablet, by the same http api that the dest backends
+// download the binlog with.
+func getBinlogRowsetStats(backend *base.Backend, tabletId, binlogVersion int64) (*IngestStats, error) {
+	api := fmt.Sprintf("http://%s:%d/api/_binlog/_download", backend.Host, backend.HttpPort)
+
+	info, err := binlogStatsGet(fmt.Sprintf("%s?method=get_binlog_info&tablet_id=%d&binlog_version=%d",
+		api, tabletId, binlogVersion))
+	if err != nil {
+		return nil, err
+	}
+	// the binlog info is rowset_id:num_segments
+	rowsetId, _, ok := strings.Cut(string(info), ":")
+	if !ok {
+		return nil, xerror.Errorf(xerror.BE, "invalid binlog info %q, tablet id: %d", info, tabletId)
+	}
+
+	meta, err := binlogStatsGet(fmt.Sprintf("%s?method=get_rowset_meta&tablet_id=%d&rowset_id=%s&binlog_version=%d",
+		api, tabletId, rowsetId, binlogVersion))
+	if err != nil {
+		return nil, err
+	}
+	return parseRowsetMetaStats(meta)
+}
+
+func binlogStatsGet(url string) ([]byte, error) {
+	resp, err := binlogStatsClient.Get(url)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.BE, "get url: %s failed", url)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.BE, "read the response of url: %s failed", url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerror.Errorf(xerror.BE, "get url: %s failed, status: %s, body: %.256s", url, resp.Status, body)
+	}
+	return body, nil
+}
+
+// Read the num_rows and total_disk_size of the serialized RowsetMetaPB.
+func parseRowsetMetaStats(data []byte) (*IngestStats, error) {
+	stats := &IngestStats{}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, xerror.Wrap(protowire.ParseError(n), xerror.BE, "parse rowset meta failed")
+		}
+		data = data[n:]
+
+		if typ == protowire.VarintType && (num == rowsetMetaNumRowsField || num == rowsetMetaTotalDiskSizeField) {
+			value, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, xerror.Wrap(protowire.ParseError(n), xerror.BE, "parse rowset meta failed")
+			}
+			if num == rowsetMetaNumRowsField {
+				stats.Rows = int64(value)
+			} else {
+				stats.Bytes = int64(value)
+			}
+			data = data[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return nil, xerror.Wrap(protowire.ParseError(n), xerror.BE, "parse rowset meta failed")
+		}
+		data = data[n:]
+	}
+	return stats, nil
+}
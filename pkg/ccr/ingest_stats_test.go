@@ -0,0 +1,92 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func newRowsetMeta(rows, bytes int64) []byte {
+	var meta []byte
+	meta = protowire.AppendTag(meta, 1, protowire.VarintType)
+	meta = protowire.AppendVarint(meta, 10001)
+	meta = protowire.AppendTag(meta, 7, protowire.BytesType) // an unknown field
+	meta = protowire.AppendString(meta, "ignored")
+	meta = protowire.AppendTag(meta, rowsetMetaNumRowsField, protowire.VarintType)
+	meta = protowire.AppendVarint(meta, uint64(rows))
+	meta = protowire.AppendTag(meta, rowsetMetaTotalDiskSizeField, protowire.VarintType)
+	meta = protowire.AppendVarint(meta, uint64(bytes))
+	return meta
+}
+
+func TestParseRowsetMetaStats(t *testing.T) {
+	stats, err := parseRowsetMetaStats(newRowsetMeta(1024, 65536))
+	if err != nil {
+		t.Fatalf("parse rowset meta failed: %v", err)
+	}
+	if stats.Rows != 1024 || stats.Bytes != 65536 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if _, err := parseRowsetMetaStats([]byte{0xff}); err == nil {
+		t.Errorf("the truncated rowset meta should be invalid")
+	}
+}
+
+func TestGetBinlogRowsetStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/_binlog/_download" || query.Get("tablet_id") != "10" || query.Get("binlog_version") != "3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch query.Get("method") {
+		case "get_binlog_info":
+			fmt.Fprint(w, "020000000000000abc:2")
+		case "get_rowset_meta":
+			if query.Get("rowset_id") != "020000000000000abc" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(newRowsetMeta(7, 4096))
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	backend := &base.Backend{Host: u.Hostname(), HttpPort: uint16(port)}
+	stats, err := getBinlogRowsetStats(backend, 10, 3)
+	if err != nil {
+		t.Fatalf("get binlog rowset stats failed: %v", err)
+	}
+	if stats.Rows != 7 || stats.Bytes != 4096 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if _, err := getBinlogRowsetStats(backend, 11, 3); err == nil {
+		t.Errorf("the stats of the unknown tablet should fail")
+	}
+}
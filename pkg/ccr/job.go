@@ -25,6 +25,7 @@ import (
 	"math"
 	"math/rand"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -32,11 +33,13 @@ import (
 
 	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
 	"github.com/selectdb/ccr_syncer/pkg/ccr/record"
+	"github.com/selectdb/ccr_syncer/pkg/notify"
 	"github.com/selectdb/ccr_syncer/pkg/rpc"
 	"github.com/selectdb/ccr_syncer/pkg/storage"
 	utils "github.com/selectdb/ccr_syncer/pkg/utils"
 	"github.com/selectdb/ccr_syncer/pkg/xerror"
 	"github.com/selectdb/ccr_syncer/pkg/xmetrics"
+	"github.com/selectdb/ccr_syncer/pkg/xtrace"
 
 	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
 	tstatus "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/status"
@@ -66,6 +69,7 @@ var (
 	featureSkipRollupBinlogs            bool
 	featureTxnInsert                    bool
 	featureFilterStorageMedium          bool
+	featureIngestStats                  bool
 
 	ErrMaterializedViewTable = xerror.NewWithoutStack(xerror.Meta, "Not support table type: materialized view")
 )
@@ -95,6 +99,8 @@ func init() {
 		"enable txn insert support")
 	flag.BoolVar(&featureFilterStorageMedium, "feature_filter_storage_medium", true,
 		"enable filter storage medium property")
+	flag.BoolVar(&featureIngestStats, "feature_ingest_stats", true,
+		"read the rows and bytes of the ingested binlogs from the src backends, for the metrics")
 }
 
 type SyncType int
@@ -145,6 +151,42 @@ type JobExtra struct {
 	SkipBinlog    bool   `json:"skip_binlog,omitempty"`
 	SkipCommitSeq int64  `json:"skip_commit_seq,omitempty"`
 	SkipBy        string `json:"skip_by,omitempty"`
+
+	// Pause the job after the binlogs up to the commit seq or the source timestamp (in ms) are synced.
+	StopAtCommitSeq int64 `json:"stop_at_commit_seq,omitempty"`
+	StopAtTimestamp int64 `json:"stop_at_timestamp,omitempty"`
+	// The commit seq of the last synced binlog when the stop point is reached.
+	StopReachedCommitSeq int64 `json:"stop_reached_commit_seq,omitempty"`
+
+	// Only sync the binlogs older than the delay (in seconds), to keep a lagged standby.
+	ApplyDelaySeconds int64 `json:"apply_delay_seconds,omitempty"`
+
+	// Apply, ignore or hold the ddl binlogs by the binlog type.
+	DDLPolicy *DDLPolicy `json:"ddl_policy,omitempty"`
+
+	// Only the tables matched by the filter are synced, for db sync only.
+	TableFilter *TableFilter `json:"table_filter,omitempty"`
+
+	// Only sync the rows matched by the predicates of the tables.
+	RowFilter *RowFilter `json:"row_filter,omitempty"`
+
+	// Rename the dest tables, for db sync only.
+	TableRename *TableRenameRule `json:"table_rename,omitempty"`
+
+	// The dest tables owned by this job, for db sync only. Jobs syncing into the same dest
+	// database must own disjoint tables.
+	OwnedTables *TableOwnership `json:"owned_tables,omitempty"`
+
+	// The jobs in the same fanout group share the binlog reads and the full sync snapshots
+	// of the same source database or table.
+	FanoutGroup string `json:"fanout_group,omitempty"`
+
+	// Replicate in both directions with an opposite job, for db sync only. The txns committed by
+	// this job are tagged, and the tagged binlogs are skipped to prevent the replication loop.
+	Bidirectional bool `json:"bidirectional,omitempty"`
+	// The source tables written in this direction, they must be disjoint with the opposite job.
+	// Required if bidirectional.
+	WriteTables *TableOwnership `json:"write_tables,omitempty"`
 }
 
 type Job struct {
@@ -171,18 +213,30 @@ type Job struct {
 
 	concurrencyManager *rpc.ConcurrencyManager `json:"-"`
 
+	// The time of beginning to apply the upsert, for the apply latency.
+	upsertBeginAt time.Time `json:"-"`
+
 	lock sync.Mutex `json:"-"`
 }
 
 type JobContext struct {
 	context.Context
-	Src              base.Spec
-	Dest             base.Spec
-	Db               storage.DB
-	SkipError        bool
-	AllowTableExists bool
-	ReuseBinlogLabel bool
-	Factory          *Factory
+	Src               base.Spec
+	Dest              base.Spec
+	Db                storage.DB
+	SkipError         bool
+	AllowTableExists  bool
+	ReuseBinlogLabel  bool
+	TableFilter       *TableFilter
+	TableRename       *TableRenameRule
+	OwnedTables       *TableOwnership
+	FanoutGroup       string
+	Bidirectional     bool
+	WriteTables       *TableOwnership
+	ApplyDelaySeconds int64
+	DDLPolicy         *DDLPolicy
+	RowFilter         *RowFilter
+	Factory           *Factory
 }
 
 // new job
@@ -192,6 +246,13 @@ func NewJobFromService(name string, ctx context.Context) (*Job, error) {
 		return nil, xerror.Errorf(xerror.Normal, "invalid context type: %T", ctx)
 	}
 
+	if err := jobContext.Src.ResolvePassword(); err != nil {
+		return nil, err
+	}
+	if err := jobContext.Dest.ResolvePassword(); err != nil {
+		return nil, err
+	}
+
 	factory := jobContext.Factory
 	src := jobContext.Src
 	dest := jobContext.Dest
@@ -206,9 +267,18 @@ func NewJobFromService(name string, ctx context.Context) (*Job, error) {
 		State:    JobRunning,
 
 		Extra: JobExtra{
-			allowTableExists: jobContext.AllowTableExists,
-			ReuseBinlogLabel: jobContext.ReuseBinlogLabel,
-			SkipBinlog:       false,
+			allowTableExists:  jobContext.AllowTableExists,
+			ReuseBinlogLabel:  jobContext.ReuseBinlogLabel,
+			SkipBinlog:        false,
+			TableFilter:       jobContext.TableFilter,
+			TableRename:       jobContext.TableRename,
+			OwnedTables:       jobContext.OwnedTables,
+			FanoutGroup:       jobContext.FanoutGroup,
+			Bidirectional:     jobContext.Bidirectional,
+			WriteTables:       jobContext.WriteTables,
+			ApplyDelaySeconds: jobContext.ApplyDelaySeconds,
+			DDLPolicy:         jobContext.DDLPolicy,
+			RowFilter:         jobContext.RowFilter,
 		},
 
 		factory: factory,
@@ -231,6 +301,7 @@ func NewJobFromService(name string, ctx context.Context) (*Job, error) {
 	}
 
 	job.jobFactory = NewJobFactory()
+	job.storeConnectivitySpecs()
 
 	return job, nil
 }
@@ -239,7 +310,15 @@ func NewJobFromJson(jsonData string, db storage.DB, factory *Factory) (*Job, err
 	var job Job
 	err := json.Unmarshal([]byte(jsonData), &job)
 	if err != nil {
-		return nil, xerror.Wrapf(err, xerror.Normal, "unmarshal json failed, json: %s", jsonData)
+		return nil, xerror.Wrapf(err, xerror.Normal, "unmarshal job json failed")
+	}
+
+	// The job is still recovered, and the password will be resolved again when it is used.
+	if err := job.Src.ResolvePassword(); err != nil {
+		log.Warnf("job %s resolve src password failed, err: %+v", job.Name, err)
+	}
+	if err := job.Dest.ResolvePassword(); err != nil {
+		log.Warnf("job %s resolve dest password failed, err: %+v", job.Name, err)
 	}
 
 	// recover all not json fields
@@ -253,6 +332,7 @@ func NewJobFromJson(jsonData string, db storage.DB, factory *Factory) (*Job, err
 	job.stop = make(chan struct{})
 	job.jobFactory = NewJobFactory()
 	job.concurrencyManager = rpc.NewConcurrencyManager()
+	job.storeConnectivitySpecs()
 	return &job, nil
 }
 
@@ -282,6 +362,89 @@ func (j *Job) valid() error {
 		return xerror.New(xerror.Normal, "src/dest are not both db or table sync")
 	}
 
+	return j.validExtra()
+}
+
+// Valid the options of the job.
+func (j *Job) validExtra() error {
+	if !j.Extra.TableFilter.IsEmpty() {
+		if j.Src.Table != "" {
+			return xerror.New(xerror.Normal, "table filter is only supported in db sync")
+		}
+		if err := j.Extra.TableFilter.Valid(); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "table filter is invalid")
+		}
+	}
+
+	if !j.Extra.TableRename.IsEmpty() {
+		if j.Src.Table != "" {
+			return xerror.New(xerror.Normal, "table rename is only supported in db sync")
+		}
+		if err := j.Extra.TableRename.Valid(); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "table rename is invalid")
+		}
+	}
+
+	if !j.Extra.OwnedTables.IsEmpty() {
+		if j.Src.Table != "" {
+			return xerror.New(xerror.Normal, "owned tables is only supported in db sync")
+		}
+		if err := j.Extra.OwnedTables.Valid(); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "owned tables is invalid")
+		}
+	}
+
+	if j.Extra.Bidirectional {
+		if j.Src.Table != "" {
+			return xerror.New(xerror.Normal, "bidirectional is only supported in db sync")
+		}
+		if j.Extra.ReuseBinlogLabel {
+			return xerror.New(xerror.Normal, "bidirectional is conflict with reuse binlog label")
+		}
+		// The writes of both directions would loop back on each other without the disjoint tables.
+		if j.Extra.WriteTables.IsEmpty() {
+			return xerror.New(xerror.Normal, "write tables is required in bidirectional sync")
+		}
+	}
+
+	if j.Extra.ApplyDelaySeconds < 0 {
+		return xerror.Errorf(xerror.Normal, "invalid apply delay %d", j.Extra.ApplyDelaySeconds)
+	} else if j.Extra.ApplyDelaySeconds > 0 && j.Src.Table == "" {
+		// the partial snapshots triggered by the ddl of db sync restore the current upstream data.
+		return xerror.New(xerror.Normal, "apply delay is only supported in table sync")
+	}
+
+	if err := j.Extra.DDLPolicy.Valid(); err != nil {
+		return xerror.Wrap(err, xerror.Normal, "ddl policy is invalid")
+	}
+
+	if !j.Extra.RowFilter.IsEmpty() {
+		if err := j.Extra.RowFilter.Valid(); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "row filter is invalid")
+		}
+		if j.Extra.Bidirectional {
+			return xerror.New(xerror.Normal, "row filter is conflict with bidirectional")
+		}
+		if j.Extra.ApplyDelaySeconds > 0 {
+			// the filtered tables are reloaded from the current upstream data, which is not delayed.
+			return xerror.New(xerror.Normal, "row filter is conflict with apply delay")
+		}
+		if j.Src.Table != "" {
+			if _, ok := j.Extra.RowFilter.Predicate(j.Src.Table); !ok || len(j.Extra.RowFilter.Predicates) > 1 {
+				return xerror.Errorf(xerror.Normal, "row filter of table sync must only have the table %s", j.Src.Table)
+			}
+		}
+	}
+
+	if !j.Extra.WriteTables.IsEmpty() {
+		if !j.Extra.Bidirectional {
+			return xerror.New(xerror.Normal, "write tables is only supported in bidirectional sync")
+		}
+		if err := j.Extra.WriteTables.Valid(); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "write tables is invalid")
+		}
+	}
+
 	return nil
 }
 
@@ -472,6 +635,9 @@ func (j *Job) partialSync() error {
 			log.Infof("partial sync status: backup job %s is running", snapshotName)
 			return nil
 		}
+		j.recordEvent(JobEventSnapshotCreated,
+			JobEventDetail{CommitSeq: j.progress.CommitSeq, SyncId: j.progress.SyncId, Table: table, Snapshot: snapshotName},
+			"partial snapshot %s of table %s created", snapshotName, table)
 
 		j.progress.NextSubCheckpoint(GetSnapshotInfo, snapshotName)
 
@@ -578,6 +744,13 @@ func (j *Job) partialSync() error {
 		// Step 5.1: try reuse the exists restore job.
 		inMemoryData := j.progress.InMemoryData.(*inMemoryData)
 		snapshotName := inMemoryData.SnapshotName
+		if _, ok := j.Extra.RowFilter.Predicate(table); ok {
+			// The filtered table is loaded through the predicate in PersistRestoreInfo.
+			log.Infof("partial sync table %s is filtered by the row filter, skip restore", table)
+			inMemoryData.RestoreLabel = ""
+			j.progress.NextSubVolatile(WaitRestoreDone, inMemoryData)
+			break
+		}
 		if featureReuseRunningBackupRestoreJob {
 			name, err := j.IDest.GetValidRestoreJob(snapshotName)
 			if err != nil {
@@ -613,14 +786,10 @@ func (j *Job) partialSync() error {
 				AliasName: &aliasName,
 			}
 			tableRefs = append(tableRefs, tableRef)
-		} else if j.isTableSyncWithAlias() {
-			log.Infof("table sync snapshot not same name, table: %s, dest table: %s", j.Src.Table, j.Dest.Table)
+		} else if destTableName := j.getDestTableName(table); destTableName != table {
+			log.Infof("partial sync snapshot not same name, table: %s, dest table: %s", table, destTableName)
 			tableRefs = make([]*festruct.TTableRef, 0)
-			tableRef := &festruct.TTableRef{
-				Table:     &j.Src.Table,
-				AliasName: &j.Dest.Table,
-			}
-			tableRefs = append(tableRefs, tableRef)
+			tableRefs = append(tableRefs, j.newRestoreTableRef(table))
 		}
 
 		restoreReq := rpc.RestoreSnapshotRequest{
@@ -653,7 +822,7 @@ func (j *Job) partialSync() error {
 		restoreSnapshotName := inMemoryData.RestoreLabel
 		snapshotResp := inMemoryData.SnapshotResp
 
-		if snapshotResp.GetExpiredAt() > 0 && time.Now().UnixMilli() > snapshotResp.GetExpiredAt() {
+		if restoreSnapshotName != "" && snapshotResp.GetExpiredAt() > 0 && time.Now().UnixMilli() > snapshotResp.GetExpiredAt() {
 			log.Infof("partial sync snapshot %s is expired, cancel and retry with new partial sync", restoreSnapshotName)
 			if err := j.IDest.CancelRestoreIfExists(restoreSnapshotName); err != nil {
 				return err
@@ -662,7 +831,7 @@ func (j *Job) partialSync() error {
 			return j.newPartialSnapshot(tableId, table, partitions, replace)
 		}
 
-		restoreFinished, err := j.IDest.CheckRestoreFinished(restoreSnapshotName)
+		restoreFinished, err := j.checkRestoreFinished(restoreSnapshotName)
 		if err != nil {
 			j.progress.NextSubVolatile(RestoreSnapshot, inMemoryData)
 			return err
@@ -672,6 +841,9 @@ func (j *Job) partialSync() error {
 			log.Infof("partial sync status: restore job %s is running", restoreSnapshotName)
 			return nil
 		}
+		j.recordEvent(JobEventRestoreFinished,
+			JobEventDetail{CommitSeq: j.progress.CommitSeq, SyncId: j.progress.SyncId, Table: table, Snapshot: restoreSnapshotName},
+			"restore %s of table %s finished", restoreSnapshotName, table)
 
 		// save the entire commit seq map, this value will be used in PersistRestoreInfo.
 		j.progress.TableCommitSeqMap = utils.MergeMap(
@@ -683,10 +855,7 @@ func (j *Job) partialSync() error {
 	case PersistRestoreInfo:
 		// Step 7: Update job progress && dest table id
 		// update job info, only for dest table id
-		var targetName = table
-		if j.isTableSyncWithAlias() {
-			targetName = j.Dest.Table
-		}
+		var targetName = j.getDestTableName(table)
 		if alias, ok := j.progress.TableAliases[table]; ok {
 			// check table exists to ensure the idempotent
 			if exist, err := j.IDest.CheckTableExistsByName(alias); err != nil {
@@ -718,12 +887,17 @@ func (j *Job) partialSync() error {
 		}
 
 		log.Infof("partial sync status: persist restore info")
+		if _, err := j.rebuildFilteredTable(table); err != nil {
+			return err
+		}
 		destTable, err := j.destMeta.UpdateTable(targetName, 0)
 		if err != nil {
 			return err
 		}
+		var restoredCommitSeq int64
 		switch j.SyncType {
 		case DBSync:
+			restoredCommitSeq = j.progress.TableCommitSeqMap[tableId]
 			j.progress.TableMapping[tableId] = destTable.Id
 			j.progress.NextWithPersist(j.progress.CommitSeq, DBTablesIncrementalSync, Done, "")
 		case TableSync:
@@ -731,6 +905,7 @@ func (j *Job) partialSync() error {
 			if !ok {
 				return xerror.Errorf(xerror.Normal, "table id %d, commit seq not found", j.Src.TableId)
 			}
+			restoredCommitSeq = commitSeq
 			j.Dest.TableId = destTable.Id
 			j.progress.TableMapping = nil
 			j.progress.TableCommitSeqMap = nil
@@ -739,7 +914,7 @@ func (j *Job) partialSync() error {
 			return xerror.Errorf(xerror.Normal, "invalid sync type %d", j.SyncType)
 		}
 
-		return nil
+		return j.checkStopPointAfterRestore(restoredCommitSeq)
 
 	default:
 		return xerror.Errorf(xerror.Normal, "invalid job sub sync state %d", j.progress.SubSyncState)
@@ -792,12 +967,21 @@ func (j *Job) fullSync() error {
 			count := 0
 			for _, table := range tables {
 				// See fe/fe-core/src/main/java/org/apache/doris/backup/BackupHandler.java:backup() for details
-				if table.Type == record.TableTypeOlap || table.Type == record.TableTypeView {
-					count += 1
+				if table.Type != record.TableTypeOlap && table.Type != record.TableTypeView {
+					continue
+				}
+				if !j.matchTable(table.Name) {
+					log.Infof("fullsync skip table %s, it doesn't match the table filter or isn't owned", table.Name)
+					continue
+				}
+				count += 1
+				if j.hasTableSelection() {
+					backupTableList = append(backupTableList, table.Name)
 				}
 			}
 			if count == 0 {
-				log.Warnf("full sync but source db is empty! retry later")
+				log.Warnf("full sync but source db is empty or no tables match the filter %s and owned tables %s! retry later",
+					j.Extra.TableFilter, j.Extra.OwnedTables)
 				return nil
 			}
 		case TableSync:
@@ -806,8 +990,8 @@ func (j *Job) fullSync() error {
 			return xerror.Errorf(xerror.Normal, "invalid sync type %s", j.SyncType)
 		}
 
-		snapshotName := NewLabelWithTs(prefix)
-		if err := j.ISrc.CreateSnapshot(snapshotName, backupTableList); err != nil {
+		snapshotName, err := j.createFullSyncSnapshot(prefix, backupTableList)
+		if err != nil {
 			return err
 		}
 		j.progress.NextSubVolatile(WaitBackupDone, snapshotName)
@@ -818,6 +1002,7 @@ func (j *Job) fullSync() error {
 		snapshotName := j.progress.InMemoryData.(string)
 		backupFinished, err := j.ISrc.CheckBackupFinished(snapshotName)
 		if err != nil {
+			j.forgetFanoutSnapshot(snapshotName)
 			j.progress.NextSubVolatile(BeginCreateSnapshot, snapshotName)
 			return err
 		}
@@ -825,6 +1010,9 @@ func (j *Job) fullSync() error {
 			log.Infof("fullsync status: backup job %s is running", snapshotName)
 			return nil
 		}
+		j.recordEvent(JobEventSnapshotCreated,
+			JobEventDetail{CommitSeq: j.progress.CommitSeq, SyncId: j.progress.SyncId, Snapshot: snapshotName},
+			"snapshot %s created", snapshotName)
 
 		j.progress.NextSubCheckpoint(GetSnapshotInfo, snapshotName)
 
@@ -851,6 +1039,7 @@ func (j *Job) fullSync() error {
 			log.Warnf("get snapshot %s: %s (%s), retry with new full sync", snapshotName,
 				utils.FirstOr(snapshotResp.Status.GetErrorMsgs(), "unknown"),
 				snapshotResp.Status.GetStatusCode())
+			j.forgetFanoutSnapshot(snapshotName)
 			return j.newSnapshot(j.progress.CommitSeq)
 		} else if snapshotResp.Status.GetStatusCode() != tstatus.TStatusCode_OK {
 			err = xerror.Errorf(xerror.FE, "get snapshot failed, status: %v", snapshotResp.Status)
@@ -984,14 +1173,13 @@ func (j *Job) fullSync() error {
 			}
 			tableRefs = append(tableRefs, tableRef)
 		}
-		if len(j.progress.TableAliases) > 0 {
+		if len(j.progress.TableAliases) > 0 || !j.Extra.TableRename.IsEmpty() {
 			tableRefs = make([]*festruct.TTableRef, 0)
 			viewMap := make(map[string]interface{})
 			for _, viewName := range inMemoryData.Views {
 				log.Debugf("fullsync alias with view ref %s", viewName)
 				viewMap[viewName] = nil
-				tableRef := &festruct.TTableRef{Table: utils.ThriftValueWrapper(viewName)}
-				tableRefs = append(tableRefs, tableRef)
+				tableRefs = append(tableRefs, j.newRestoreTableRef(viewName))
 			}
 			for _, tableName := range tableNameMapping {
 				if alias, ok := j.progress.TableAliases[tableName]; ok {
@@ -1002,8 +1190,7 @@ func (j *Job) fullSync() error {
 					continue
 				}
 				log.Debugf("fullsync alias with table ref %s", tableName)
-				tableRef := &festruct.TTableRef{Table: utils.ThriftValueWrapper(tableName)}
-				tableRefs = append(tableRefs, tableRef)
+				tableRefs = append(tableRefs, j.newRestoreTableRef(tableName))
 			}
 			for table, alias := range j.progress.TableAliases {
 				log.Infof("fullsync alias table from %s to %s", table, alias)
@@ -1014,6 +1201,16 @@ func (j *Job) fullSync() error {
 				tableRefs = append(tableRefs, tableRef)
 			}
 		}
+		if !j.Extra.RowFilter.IsEmpty() {
+			// The filtered tables are loaded through the predicates in PersistRestoreInfo.
+			tableRefs = j.excludeFilteredTableRefs(tableRefs, inMemoryData.Views, tableNameMapping)
+			if len(tableRefs) == 0 {
+				log.Infof("fullsync all tables are filtered by the row filter, skip restore")
+				inMemoryData.RestoreLabel = ""
+				j.progress.NextSubVolatile(WaitRestoreDone, inMemoryData)
+				return nil
+			}
+		}
 
 		compress := false
 		if featureCompressedSnapshot {
@@ -1035,7 +1232,8 @@ func (j *Job) fullSync() error {
 		if featureCleanTableAndPartitions {
 			// drop exists partitions, and drop tables if in db sync.
 			restoreReq.CleanPartitions = true
-			if j.SyncType == DBSync {
+			// the tables not owned by this job belong to other jobs or the opposite direction, keep them.
+			if j.SyncType == DBSync && j.Extra.OwnedTables.IsEmpty() && !j.Extra.Bidirectional && j.Extra.RowFilter.IsEmpty() {
 				restoreReq.CleanTables = true
 			}
 		}
@@ -1062,7 +1260,7 @@ func (j *Job) fullSync() error {
 		tableNameMapping := inMemoryData.TableNameMapping
 		snapshotResp := inMemoryData.SnapshotResp
 
-		if snapshotResp.GetExpiredAt() > 0 && time.Now().UnixMilli() > snapshotResp.GetExpiredAt() {
+		if restoreSnapshotName != "" && snapshotResp.GetExpiredAt() > 0 && time.Now().UnixMilli() > snapshotResp.GetExpiredAt() {
 			log.Infof("fullsync snapshot %s is expired, cancel and retry with new full sync", restoreSnapshotName)
 			if err := j.IDest.CancelRestoreIfExists(restoreSnapshotName); err != nil {
 				return err
@@ -1071,7 +1269,7 @@ func (j *Job) fullSync() error {
 		}
 
 		for {
-			restoreFinished, err := j.IDest.CheckRestoreFinished(restoreSnapshotName)
+			restoreFinished, err := j.checkRestoreFinished(restoreSnapshotName)
 			if err != nil && errors.Is(err, base.ErrRestoreSignatureNotMatched) {
 				// We need rebuild the exists table.
 				var tableName string
@@ -1094,7 +1292,9 @@ func (j *Job) fullSync() error {
 					if j.progress.TableAliases == nil {
 						j.progress.TableAliases = make(map[string]string)
 					}
-					j.progress.TableAliases[tableName] = TableAlias(tableName)
+					// ATTN: The table name of the alias is from the source cluster.
+					srcTableName := j.getSrcTableNameByDest(tableNameMapping, tableName)
+					j.progress.TableAliases[srcTableName] = TableAlias(tableName)
 					j.progress.NextSubVolatile(RestoreSnapshot, inMemoryData)
 					break
 				}
@@ -1143,6 +1343,9 @@ func (j *Job) fullSync() error {
 				commitSeq = tableCommitSeqMap[j.Src.TableId]
 			}
 
+			j.recordEvent(JobEventRestoreFinished,
+				JobEventDetail{CommitSeq: commitSeq, SyncId: j.progress.SyncId, Snapshot: restoreSnapshotName},
+				"restore %s finished, sync from commit seq %d", restoreSnapshotName, commitSeq)
 			j.progress.CommitNextSubWithPersist(commitSeq, PersistRestoreInfo, restoreSnapshotName)
 			break
 		}
@@ -1160,10 +1363,7 @@ func (j *Job) fullSync() error {
 			}
 			for _, table := range tables {
 				alias := j.progress.TableAliases[table]
-				targetName := table
-				if j.isTableSyncWithAlias() {
-					targetName = j.Dest.Table
-				}
+				targetName := j.getDestTableName(table)
 
 				// check table exists to ensure the idempotent
 				if exist, err := j.IDest.CheckTableExistsByName(alias); err != nil {
@@ -1187,9 +1387,16 @@ func (j *Job) fullSync() error {
 		}
 
 		log.Infof("fullsync status: persist restore info")
+		if err := j.rebuildAllFilteredTables(); err != nil {
+			return err
+		}
 
+		restoredCommitSeq := j.getRestoredCommitSeq()
 		switch j.SyncType {
 		case DBSync:
+			if err := j.recreateRenamedViews(); err != nil {
+				return err
+			}
 			// refresh dest meta cache before building table mapping.
 			j.destMeta.ClearTablesCache()
 			tableMapping := make(map[int64]int64)
@@ -1214,13 +1421,14 @@ func (j *Job) fullSync() error {
 					}
 				}
 
-				destTableId, err := j.destMeta.GetTableId(srcTableName)
+				destTableName := j.getDestTableName(srcTableName)
+				destTableId, err := j.destMeta.GetTableId(destTableName)
 				if err != nil {
 					return err
 				}
 
-				log.Debugf("fullsync table mapping, src: %d, dest: %d, name: %s",
-					srcTableId, destTableId, srcTableName)
+				log.Debugf("fullsync table mapping, src: %d, dest: %d, name: %s, dest name: %s",
+					srcTableId, destTableId, srcTableName, destTableName)
 				tableMapping[srcTableId] = destTableId
 			}
 
@@ -1246,7 +1454,7 @@ func (j *Job) fullSync() error {
 			return xerror.Errorf(xerror.Normal, "invalid sync type %d", j.SyncType)
 		}
 
-		return nil
+		return j.checkStopPointAfterRestore(restoredCommitSeq)
 	default:
 		return xerror.Errorf(xerror.Normal, "invalid job sub sync state %d", j.progress.SubSyncState)
 	}
@@ -1254,6 +1462,29 @@ func (j *Job) fullSync() error {
 	return j.fullSync()
 }
 
+// Build the restore table ref of the source table, with the dest table name as the alias if they are different.
+func (j *Job) newRestoreTableRef(srcTableName string) *festruct.TTableRef {
+	tableRef := &festruct.TTableRef{Table: utils.ThriftValueWrapper(srcTableName)}
+	if destTableName := j.getDestTableName(srcTableName); destTableName != srcTableName {
+		tableRef.AliasName = utils.ThriftValueWrapper(destTableName)
+	}
+	return tableRef
+}
+
+// Get the source table name of the dest table from the table name mapping of the snapshot,
+// returns the dest table name if not found.
+func (j *Job) getSrcTableNameByDest(tableNameMapping map[int64]string, destTableName string) string {
+	if j.SyncType == TableSync {
+		return j.Src.Table
+	}
+	for _, srcTableName := range tableNameMapping {
+		if j.getDestTableName(srcTableName) == destTableName {
+			return srcTableName
+		}
+	}
+	return destTableName
+}
+
 func (j *Job) persistJob() error {
 	data, err := json.Marshal(j)
 	if err != nil {
@@ -1271,7 +1502,10 @@ func (j *Job) newLabel(commitSeq int64) string {
 	src := &j.Src
 	dest := &j.Dest
 	randNum := rand.Intn(65536) // hex 4 chars
-	if j.SyncType == DBSync {
+	if j.SyncType == DBSync && j.Extra.Bidirectional {
+		// label "ccrb-rand:${sync_type}:${src_db_id}:${dest_db_id}:${commit_seq}"
+		return fmt.Sprintf("%s%x:%s:%d:%d:%d", BidirectionalLabelPrefix, randNum, j.SyncType, src.DbId, dest.DbId, commitSeq)
+	} else if j.SyncType == DBSync {
 		// label "ccrj-rand:${sync_type}:${src_db_id}:${dest_db_id}:${commit_seq}"
 		return fmt.Sprintf("ccrj-%x:%s:%d:%d:%d", randNum, j.SyncType, src.DbId, dest.DbId, commitSeq)
 	} else {
@@ -1307,7 +1541,7 @@ func (j *Job) getDestTableIdBySrc(srcTableId int64) (int64, error) {
 	srcTableName := srcTable.Name
 	if j.isTableSyncWithAlias() {
 		return j.Dest.TableId, nil
-	} else if destTableId, err := j.destMeta.GetTableId(srcTableName); err != nil {
+	} else if destTableId, err := j.destMeta.GetTableId(j.getDestTableName(srcTableName)); err != nil {
 		return 0, err
 	} else {
 		j.progress.TableMapping[srcTableId] = destTableId
@@ -1315,6 +1549,23 @@ func (j *Job) getDestTableIdBySrc(srcTableId int64) (int64, error) {
 	}
 }
 
+// Get the dest table name of the source table, the table might be renamed by the table rename rule.
+func (j *Job) getDestTableName(srcTableName string) string {
+	if j.SyncType == TableSync {
+		return j.Dest.Table
+	}
+	return j.Extra.TableRename.DestName(srcTableName)
+}
+
+// Get the source table name by id, the name in the table name mapping is preferred, since
+// the upstream table might be renamed. Returns empty string if the table is not found.
+func (j *Job) getSrcTableNameById(srcTableId int64) (string, error) {
+	if name, ok := j.progress.TableNameMapping[srcTableId]; ok {
+		return name, nil
+	}
+	return j.srcMeta.GetTableNameById(srcTableId)
+}
+
 func (j *Job) getDestNameBySrcId(srcTableId int64) (string, error) {
 	destTableId, err := j.getDestTableIdBySrc(srcTableId)
 	if err != nil {
@@ -1345,6 +1596,59 @@ func (j *Job) isBinlogCommitted(tableId int64, binlogCommitSeq int64) bool {
 	return false
 }
 
+// The dest tables owned by this job, an empty ownership means the whole dest database.
+func (j *Job) destOwnership() *TableOwnership {
+	if j.SyncType == TableSync {
+		return &TableOwnership{Tables: []string{j.Dest.Table}}
+	}
+	return j.Extra.OwnedTables
+}
+
+// Whether only part of the tables are synced in db sync.
+func (j *Job) hasTableSelection() bool {
+	return !j.Extra.TableFilter.IsEmpty() || !j.Extra.OwnedTables.IsEmpty() || !j.Extra.WriteTables.IsEmpty()
+}
+
+// Whether the source table matches the table filter, is written in this direction, and its dest
+// table is owned by this job.
+func (j *Job) matchTable(srcTableName string) bool {
+	return j.Extra.TableFilter.Match(srcTableName) &&
+		j.Extra.WriteTables.Owns(srcTableName) &&
+		j.Extra.OwnedTables.Owns(j.getDestTableName(srcTableName))
+}
+
+// Whether the binlogs of the table should be filtered by the table filter or the owned tables of db sync.
+//
+// The table name is optional, it will be read from the table name mapping or the
+// upstream if it is empty.
+func (j *Job) isTableFiltered(tableId int64, tableName string) (bool, error) {
+	if j.SyncType != DBSync || !j.hasTableSelection() {
+		return false, nil
+	}
+
+	if tableName == "" {
+		if name, err := j.getSrcTableNameById(tableId); err != nil {
+			return false, err
+		} else {
+			tableName = name
+		}
+	}
+
+	if tableName == "" {
+		// The table might be dropped in the upstream, keep syncing it only if it is already synced.
+		_, ok := j.progress.TableMapping[tableId]
+		return !ok, nil
+	}
+
+	if j.matchTable(tableName) {
+		return false, nil
+	}
+
+	log.Infof("filter the binlog of table %s, table id: %d, it doesn't match the table filter or isn't owned, commit seq: %d",
+		tableName, tableId, j.progress.CommitSeq)
+	return true, nil
+}
+
 func (j *Job) getDbSyncTableRecords(upsert *record.Upsert) []*record.TableRecord {
 	commitSeq := upsert.CommitSeq
 	tableCommitSeqMap := j.progress.TableCommitSeqMap
@@ -1395,125 +1699,443 @@ func (j *Job) getRelatedTableRecords(upsert *record.Upsert) ([]*record.TableReco
 	return tableRecords, nil
 }
 
-// Table ingestBinlog
-func (j *Job) ingestBinlog(txnId int64, tableRecords []*record.TableRecord) ([]*ttypes.TTabletCommitInfo, error) {
-	log.Infof("ingestBinlog, txnId: %d", txnId)
+// Reload the partitions of the dest table with the rows matched by the row filter, the whole table is
+// reloaded if the partitions are empty. Returns false if the table is not filtered.
+//
+// The catalog reads the current rows of the source, the table or the partitions might have been
+// dropped or renamed by the binlogs not applied yet.
+func (j *Job) reloadFilteredTable(srcTableName string, partitions []string) (bool, error) {
+	predicate, ok := j.Extra.RowFilter.Predicate(srcTableName)
+	if !ok {
+		return false, nil
+	}
 
-	job, err := j.jobFactory.CreateJob(NewIngestContext(txnId, tableRecords, j.progress.TableMapping), j, "IngestBinlog")
-	if err != nil {
-		return nil, err
+	if exists, err := j.ISrc.CheckTableExistsByName(srcTableName); err != nil {
+		return true, err
+	} else if !exists {
+		log.Warnf("the filtered table %s is not exists in the source, skip reload", srcTableName)
+		return true, nil
+	}
+	if len(partitions) > 0 {
+		if exists, err := j.checkSrcPartitionsExists(srcTableName, partitions); err != nil {
+			return true, err
+		} else if !exists {
+			log.Warnf("the partitions %v of the filtered table %s are not all exists in the source, reload the whole table",
+				partitions, srcTableName)
+			partitions = nil
+		}
+	}
+	if len(partitions) == 0 {
+		if err := j.checkFilteredTableSize(srcTableName); err != nil {
+			return true, err
+		}
 	}
 
-	ingestBinlogJob, ok := job.(*IngestBinlogJob)
-	if !ok {
-		return nil, xerror.Errorf(xerror.Normal, "invalid job type, job: %+v", job)
+	destTableName := j.getDestTableName(srcTableName)
+	log.Infof("reload the filtered table %s, dest table: %s, partitions: %v, predicate: %s",
+		srcTableName, destTableName, partitions, predicate)
+	err := j.IDest.ReloadTableWithPredicate(destTableName, j.Extra.RowFilter.Catalog, j.Src.Database,
+		srcTableName, partitions, predicate)
+	return true, err
+}
+
+func (j *Job) checkSrcPartitionsExists(srcTableName string, partitions []string) (bool, error) {
+	tableId, err := j.srcMeta.GetTableId(srcTableName)
+	if err != nil {
+		return false, err
+	}
+	if err := j.srcMeta.UpdatePartitions(tableId); err != nil {
+		return false, err
+	}
+	partitionMap, err := j.srcMeta.GetPartitionIdMap(tableId)
+	if err != nil {
+		return false, err
 	}
 
-	job.Run()
-	if err := job.Error(); err != nil {
-		return nil, err
+	names := make(map[string]struct{}, len(partitionMap))
+	for _, partition := range partitionMap {
+		names[partition.Name] = struct{}{}
+	}
+	for _, partition := range partitions {
+		if _, ok := names[partition]; !ok {
+			return false, nil
+		}
 	}
-	return ingestBinlogJob.CommitInfos(), nil
+	return true, nil
 }
 
-// Table ingestBinlog for txn insert
-func (j *Job) ingestBinlogForTxnInsert(txnId int64, tableRecords []*record.TableRecord, stidMap map[int64]int64, destTableId int64) ([]*festruct.TSubTxnInfo, error) {
-	log.Infof("ingestBinlogForTxnInsert, txnId: %d", txnId)
+// The whole filtered table is read from the source by the full sync and the reload of the
+// unpartitioned table, so the size of it is limited.
+func (j *Job) checkFilteredTableSize(srcTableName string) error {
+	if rowFilterMaxTableSize <= 0 {
+		return nil
+	}
 
-	job, err := j.jobFactory.CreateJob(NewIngestContextForTxnInsert(txnId, tableRecords, j.progress.TableMapping, stidMap), j, "IngestBinlog")
+	size, err := j.ISrc.GetTableDataSize(srcTableName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	ingestBinlogJob, ok := job.(*IngestBinlogJob)
-	if !ok {
-		return nil, xerror.Errorf(xerror.Normal, "invalid job type, job: %+v", job)
+	if size > rowFilterMaxTableSize {
+		return xerror.Errorf(xerror.Normal, "the filtered table %s is too large to reload, size: %d, "+
+			"limit: %d, see row_filter_max_table_size", srcTableName, size, rowFilterMaxTableSize)
 	}
+	return nil
+}
 
-	job.Run()
-	if err := job.Error(); err != nil {
-		return nil, err
-	}
+// Defer the reload of the partitions changed by the upsert to flushFilteredTables, returns the remaining
+// table records and dest table ids to ingest.
+func (j *Job) deferFilteredTables(tableRecords []*record.TableRecord, destTableIds []int64) ([]*record.TableRecord, []int64, error) {
+	savedRecords := make([]*record.TableRecord, 0, len(tableRecords))
+	savedTableIds := make([]int64, 0, len(destTableIds))
+	for i, tableRecord := range tableRecords {
+		srcTableName := j.Src.Table
+		if j.SyncType == DBSync {
+			name, err := j.getSrcTableNameById(tableRecord.Id)
+			if err != nil {
+				return nil, nil, err
+			}
+			srcTableName = name
+		}
 
-	stidToCommitInfos := ingestBinlogJob.SubTxnToCommitInfos()
-	subTxnInfos := make([]*festruct.TSubTxnInfo, 0, len(stidMap))
-	for sourceStid, destStid := range stidMap {
-		destStid := destStid // if no this line, every element in subTxnInfos is the last tSubTxnInfo
-		commitInfos := stidToCommitInfos[destStid]
-		if commitInfos == nil {
-			log.Warnf("no commit infos from source stid: %d; dest stid %d, just skip", sourceStid, destStid)
+		if _, ok := j.Extra.RowFilter.Predicate(srcTableName); !ok {
+			savedRecords = append(savedRecords, tableRecord)
+			savedTableIds = append(savedTableIds, destTableIds[i])
 			continue
 		}
-
-		tSubTxnInfo := &festruct.TSubTxnInfo{
-			SubTxnId:          &destStid,
-			TableId:           &destTableId,
-			TabletCommitInfos: commitInfos,
+		partitions, wholeTable := j.getChangedPartitionNames(srcTableName, tableRecord)
+		if !wholeTable && len(partitions) == 0 {
+			continue
 		}
-
-		subTxnInfos = append(subTxnInfos, tSubTxnInfo)
+		log.Debugf("defer the reload of the filtered table %s, partitions: %v, commit seq: %d",
+			srcTableName, partitions, j.progress.CommitSeq)
+		j.addFilteredTableToReload(srcTableName, partitions, wholeTable)
 	}
+	return savedRecords, savedTableIds, nil
+}
 
-	return subTxnInfos, nil
+// Get the names of the partitions changed by the upsert, returns true if the whole table should be
+// reloaded, eg. the table is not partitioned.
+func (j *Job) getChangedPartitionNames(srcTableName string, tableRecord *record.TableRecord) ([]string, bool) {
+	partitions := make([]string, 0, len(tableRecord.PartitionRecords))
+	for _, partitionRecord := range tableRecord.PartitionRecords {
+		if partitionRecord.IsTemp {
+			// the temp partitions are invisible until they replace the formal partitions.
+			continue
+		}
+		name, err := j.srcMeta.GetPartitionName(tableRecord.Id, partitionRecord.Id)
+		if err != nil {
+			log.Warnf("get the name of partition %d of the filtered table %s failed, reload the whole table, err: %+v",
+				partitionRecord.Id, srcTableName, err)
+			return nil, true
+		}
+		if name == srcTableName {
+			// the single partition of the unpartitioned table has the table name.
+			return nil, true
+		}
+		partitions = append(partitions, name)
+	}
+	return partitions, false
 }
 
-func (j *Job) handleUpsertWithRetry(binlog *festruct.TBinlog) error {
-	err := j.handleUpsert(binlog)
-	if !xerror.IsCategory(err, xerror.Meta) {
-		return err
+// Add the partitions to reload, nil partitions means the whole table.
+func (j *Job) addFilteredTableToReload(srcTableName string, partitions []string, wholeTable bool) {
+	if j.progress.FilteredTablesToReload == nil {
+		j.progress.FilteredTablesToReload = make(map[string][]string)
 	}
 
-	log.Warnf("a meta error occurred, retry to handle upsert binlog again, commitSeq: %d", binlog.GetCommitSeq())
-	if j.progress.SubSyncState == RollbackTransaction {
-		// rollback transaction firstly
-		if err = j.handleUpsert(nil); err != nil {
-			return err
+	pending, ok := j.progress.FilteredTablesToReload[srcTableName]
+	if wholeTable || (ok && pending == nil) {
+		j.progress.FilteredTablesToReload[srcTableName] = nil
+		return
+	}
+	for _, partition := range partitions {
+		exists := false
+		for _, name := range pending {
+			if name == partition {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			pending = append(pending, partition)
 		}
 	}
-	return j.handleUpsert(binlog)
+	j.progress.FilteredTablesToReload[srcTableName] = pending
 }
 
-func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
-	log.Infof("handle upsert binlog, sub sync state: %s, prevCommitSeq: %d, commitSeq: %d",
-		j.progress.SubSyncState, j.progress.PrevCommitSeq, j.progress.CommitSeq)
+// Reload the filtered tables changed by the upserts, it is called once per batch of binlogs and before
+// the other binlogs, so the partitions are reloaded at most once per batch, and before the ddl.
+func (j *Job) flushFilteredTables() error {
+	for srcTableName, partitions := range j.progress.FilteredTablesToReload {
+		if _, err := j.reloadFilteredTable(srcTableName, partitions); err != nil {
+			return err
+		}
+		delete(j.progress.FilteredTablesToReload, srcTableName)
+		j.progress.Persist()
+	}
+	return nil
+}
 
-	// inMemory will be update in state machine, but progress keep any, so progress.inMemory is also latest, well call NextSubCheckpoint don't need to upate inMemory in progress
-	type inMemoryData struct {
-		CommitSeq    int64                       `json:"commit_seq"`
-		TxnId        int64                       `json:"txn_id"`
-		DestTableIds []int64                     `json:"dest_table_ids"`
-		TableRecords []*record.TableRecord       `json:"table_records"`
-		CommitInfos  []*ttypes.TTabletCommitInfo `json:"commit_infos"`
-		IsTxnInsert  bool                        `json:"is_txn_insert"`
-		SourceStids  []int64                     `json:"source_stid"`
-		DestStids    []int64                     `json:"desc_stid"`
-		SubTxnInfos  []*festruct.TSubTxnInfo     `json:"sub_txn_infos"`
-		Label        string                      `json:"label"`
+// Rebuild the dest table from the create table sql of the source table and load the rows matched by
+// the row filter, so the filtered out rows never reach the dest cluster. Returns false if the table
+// is not filtered.
+func (j *Job) rebuildFilteredTable(srcTableName string) (bool, error) {
+	predicate, ok := j.Extra.RowFilter.Predicate(srcTableName)
+	if !ok {
+		return false, nil
 	}
 
-	updateInMemory := func() error {
-		if j.progress.InMemoryData == nil {
-			persistData := j.progress.PersistData
-			inMemoryData := &inMemoryData{}
-			if err := json.Unmarshal([]byte(persistData), inMemoryData); err != nil {
-				return xerror.Errorf(xerror.Normal, "unmarshal persistData failed, persistData: %s", persistData)
-			}
-			j.progress.InMemoryData = inMemoryData
-		}
-		return nil
+	createSql, err := j.ISrc.GetCreateTableSql(srcTableName)
+	if err != nil {
+		return true, err
+	}
+	if featureFilterStorageMedium {
+		createSql = FilterStorageMediumFromCreateTableSql(createSql)
 	}
 
-	rollback := func(err error, inMemoryData *inMemoryData) {
-		log.Errorf("need rollback, err: %+v", err)
-		j.progress.NextSubCheckpoint(RollbackTransaction, inMemoryData)
+	if err := j.checkFilteredTableSize(srcTableName); err != nil {
+		return true, err
 	}
 
-	committed := func() {
+	destTableName := j.getDestTableName(srcTableName)
+	tmpTableName := TableAlias(destTableName)
+	log.Infof("rebuild the filtered table %s, dest table: %s, tmp table: %s, predicate: %s",
+		srcTableName, destTableName, tmpTableName, predicate)
+	if err := j.IDest.CreateTableOrView(&record.CreateTable{Sql: createSql}, j.Src.Database, tmpTableName); err != nil {
+		return true, err
+	}
+	if err := j.IDest.ReloadTableWithPredicate(tmpTableName, j.Extra.RowFilter.Catalog,
+		j.Src.Database, srcTableName, nil, predicate); err != nil {
+		if dropErr := j.IDest.DropTable(tmpTableName, true); dropErr != nil {
+			log.Warnf("drop the tmp table %s failed, err: %+v", tmpTableName, dropErr)
+		}
+		return true, err
+	}
+
+	if exists, err := j.IDest.CheckTableExistsByName(destTableName); err != nil {
+		return true, err
+	} else if exists {
+		swap := false // drop the old table
+		err = j.IDest.ReplaceTable(tmpTableName, destTableName, swap)
+	} else {
+		err = j.IDest.RenameTableWithName(tmpTableName, destTableName)
+	}
+	if err != nil {
+		return true, err
+	}
+	// Since the meta of dest table has been changed, refresh it.
+	j.destMeta.ClearTablesCache()
+	delete(j.progress.FilteredTablesToReload, srcTableName)
+	return true, nil
+}
+
+// Rebuild all the filtered tables of the snapshot after the full sync, they are not restored.
+func (j *Job) rebuildAllFilteredTables() error {
+	j.progress.FilteredTablesToReload = nil
+	if j.Extra.RowFilter.IsEmpty() {
+		return nil
+	}
+
+	tables := make(map[string]struct{})
+	switch j.SyncType {
+	case DBSync:
+		for _, tableName := range j.progress.TableNameMapping {
+			tables[tableName] = struct{}{}
+		}
+	case TableSync:
+		tables[j.Src.Table] = struct{}{}
+	}
+
+	for srcTableName := range j.Extra.RowFilter.Predicates {
+		if _, ok := tables[srcTableName]; !ok {
+			log.Warnf("the filtered table %s is not in the snapshot, skip rebuild", srcTableName)
+			continue
+		}
+		if _, err := j.rebuildFilteredTable(srcTableName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exclude the filtered tables from the table refs of the restore, all tables and views of the snapshot
+// are listed if the table refs are empty, since the empty table refs restore the whole snapshot.
+func (j *Job) excludeFilteredTableRefs(tableRefs []*festruct.TTableRef, views []string,
+	tableNameMapping map[int64]string) []*festruct.TTableRef {
+	if j.SyncType == TableSync {
+		if _, ok := j.Extra.RowFilter.Predicate(j.Src.Table); ok {
+			return nil
+		}
+		return tableRefs
+	}
+
+	if len(tableRefs) == 0 {
+		viewMap := make(map[string]interface{})
+		for _, viewName := range views {
+			viewMap[viewName] = nil
+			tableRefs = append(tableRefs, j.newRestoreTableRef(viewName))
+		}
+		for _, tableName := range tableNameMapping {
+			if _, ok := viewMap[tableName]; ok {
+				continue
+			}
+			tableRefs = append(tableRefs, j.newRestoreTableRef(tableName))
+		}
+	}
+
+	refs := make([]*festruct.TTableRef, 0, len(tableRefs))
+	for _, tableRef := range tableRefs {
+		if _, ok := j.Extra.RowFilter.Predicate(tableRef.GetTable()); ok {
+			log.Infof("fullsync skip restore the filtered table %s", tableRef.GetTable())
+			continue
+		}
+		refs = append(refs, tableRef)
+	}
+	return refs
+}
+
+// The restore with empty label is skipped since all tables are filtered by the row filter.
+func (j *Job) checkRestoreFinished(restoreSnapshotName string) (bool, error) {
+	if restoreSnapshotName == "" {
+		return true, nil
+	}
+	return j.IDest.CheckRestoreFinished(restoreSnapshotName)
+}
+
+// Table ingestBinlog
+func (j *Job) ingestBinlog(txnId int64, tableRecords []*record.TableRecord) ([]*ttypes.TTabletCommitInfo, map[int64]*IngestStats, error) {
+	log.Infof("ingestBinlog, txnId: %d", txnId)
+
+	job, err := j.jobFactory.CreateJob(NewIngestContext(txnId, tableRecords, j.progress.TableMapping), j, "IngestBinlog")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ingestBinlogJob, ok := job.(*IngestBinlogJob)
+	if !ok {
+		return nil, nil, xerror.Errorf(xerror.Normal, "invalid job type, job: %+v", job)
+	}
+
+	job.Run()
+	if err := job.Error(); err != nil {
+		return nil, nil, err
+	}
+	return ingestBinlogJob.CommitInfos(), ingestBinlogJob.IngestStats(), nil
+}
+
+// Table ingestBinlog for txn insert
+func (j *Job) ingestBinlogForTxnInsert(txnId int64, tableRecords []*record.TableRecord, stidMap map[int64]int64, destTableId int64) ([]*festruct.TSubTxnInfo, map[int64]*IngestStats, error) {
+	log.Infof("ingestBinlogForTxnInsert, txnId: %d", txnId)
+
+	job, err := j.jobFactory.CreateJob(NewIngestContextForTxnInsert(txnId, tableRecords, j.progress.TableMapping, stidMap), j, "IngestBinlog")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ingestBinlogJob, ok := job.(*IngestBinlogJob)
+	if !ok {
+		return nil, nil, xerror.Errorf(xerror.Normal, "invalid job type, job: %+v", job)
+	}
+
+	job.Run()
+	if err := job.Error(); err != nil {
+		return nil, nil, err
+	}
+
+	stidToCommitInfos := ingestBinlogJob.SubTxnToCommitInfos()
+	subTxnInfos := make([]*festruct.TSubTxnInfo, 0, len(stidMap))
+	for sourceStid, destStid := range stidMap {
+		destStid := destStid // if no this line, every element in subTxnInfos is the last tSubTxnInfo
+		commitInfos := stidToCommitInfos[destStid]
+		if commitInfos == nil {
+			log.Warnf("no commit infos from source stid: %d; dest stid %d, just skip", sourceStid, destStid)
+			continue
+		}
+
+		tSubTxnInfo := &festruct.TSubTxnInfo{
+			SubTxnId:          &destStid,
+			TableId:           &destTableId,
+			TabletCommitInfos: commitInfos,
+		}
+
+		subTxnInfos = append(subTxnInfos, tSubTxnInfo)
+	}
+
+	return subTxnInfos, ingestBinlogJob.IngestStats(), nil
+}
+
+func (j *Job) handleUpsertWithRetry(binlog *festruct.TBinlog) error {
+	err := j.handleUpsert(binlog)
+	if !xerror.IsCategory(err, xerror.Meta) {
+		return err
+	}
+
+	log.Warnf("a meta error occurred, retry to handle upsert binlog again, commitSeq: %d", binlog.GetCommitSeq())
+	if j.progress.SubSyncState == RollbackTransaction {
+		// rollback transaction firstly
+		if err = j.handleUpsert(nil); err != nil {
+			return err
+		}
+	}
+	return j.handleUpsert(binlog)
+}
+
+func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
+	log.Infof("handle upsert binlog, sub sync state: %s, prevCommitSeq: %d, commitSeq: %d",
+		j.progress.SubSyncState, j.progress.PrevCommitSeq, j.progress.CommitSeq)
+
+	// inMemory will be update in state machine, but progress keep any, so progress.inMemory is also latest, well call NextSubCheckpoint don't need to upate inMemory in progress
+	type inMemoryData struct {
+		CommitSeq    int64                       `json:"commit_seq"`
+		TxnId        int64                       `json:"txn_id"`
+		DestTableIds []int64                     `json:"dest_table_ids"`
+		TableRecords []*record.TableRecord       `json:"table_records"`
+		CommitInfos  []*ttypes.TTabletCommitInfo `json:"commit_infos"`
+		IsTxnInsert  bool                        `json:"is_txn_insert"`
+		SourceStids  []int64                     `json:"source_stid"`
+		DestStids    []int64                     `json:"desc_stid"`
+		SubTxnInfos  []*festruct.TSubTxnInfo     `json:"sub_txn_infos"`
+		Label        string                      `json:"label"`
+		IngestStats  map[int64]*IngestStats      `json:"ingest_stats,omitempty"` // src table id => stats
+	}
+
+	updateInMemory := func() error {
+		if j.progress.InMemoryData == nil {
+			persistData := j.progress.PersistData
+			inMemoryData := &inMemoryData{}
+			if err := json.Unmarshal([]byte(persistData), inMemoryData); err != nil {
+				return xerror.Errorf(xerror.Normal, "unmarshal persistData failed, persistData: %s", persistData)
+			}
+			j.progress.InMemoryData = inMemoryData
+		}
+		return nil
+	}
+
+	rollback := func(err error, inMemoryData *inMemoryData) {
+		log.Errorf("need rollback, err: %+v", err)
+		j.progress.NextSubCheckpoint(RollbackTransaction, inMemoryData)
+	}
+
+	committed := func() {
 		log.Infof("txn committed, commitSeq: %d, cleanup", j.progress.CommitSeq)
 
 		inMemoryData := j.progress.InMemoryData.(*inMemoryData)
 		commitSeq := j.progress.CommitSeq
 		destTableIds := inMemoryData.DestTableIds
+		if !j.upsertBeginAt.IsZero() {
+			xmetrics.ObserveUpsertApply(j.Name, time.Since(j.upsertBeginAt))
+			j.upsertBeginAt = time.Time{}
+		}
+		for _, tableRecord := range inMemoryData.TableRecords {
+			table, err := j.getSrcTableNameById(tableRecord.Id)
+			if err != nil || table == "" {
+				table = strconv.FormatInt(tableRecord.Id, 10)
+			}
+			xmetrics.UpsertTable(j.Name, table)
+			if stats, ok := inMemoryData.IngestStats[tableRecord.Id]; ok {
+				xmetrics.IngestTable(j.Name, table, stats.Rows, stats.Bytes)
+			}
+		}
 		if j.SyncType == DBSync && len(j.progress.TableCommitSeqMap) > 0 {
 			for _, tableId := range destTableIds {
 				tableCommitSeq, ok := j.progress.TableCommitSeqMap[tableId]
@@ -1546,6 +2168,12 @@ func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
 		}
 		log.Debugf("upsert: %v", upsert)
 
+		if j.Extra.Bidirectional && IsBidirectionalLabel(upsert.Label) {
+			log.Infof("skip the upsert committed by the opposite bidirectional job, label: %s, commit seq: %d",
+				upsert.Label, upsert.CommitSeq)
+			return nil
+		}
+
 		// Step 1: get related tableRecords
 		var isTxnInsert bool = false
 		if len(upsert.Stids) > 0 {
@@ -1574,6 +2202,12 @@ func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
 		if j.SyncType == DBSync {
 			savedRecords := make([]*record.TableRecord, 0, len(tableRecords))
 			for _, tableRecord := range tableRecords {
+				if filtered, err := j.isTableFiltered(tableRecord.Id, ""); err != nil {
+					return err
+				} else if filtered {
+					continue
+				}
+
 				if destTableId, err := j.getDestTableIdBySrc(tableRecord.Id); err == ErrMaterializedViewTable {
 					// ignore the upsert of materialized view table.
 					continue
@@ -1588,6 +2222,12 @@ func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
 		} else {
 			destTableIds = append(destTableIds, j.Dest.TableId)
 		}
+		if !j.Extra.RowFilter.IsEmpty() {
+			tableRecords, destTableIds, err = j.deferFilteredTables(tableRecords, destTableIds)
+			if err != nil {
+				return err
+			}
+		}
 		if len(tableRecords) == 0 {
 			log.Debug("no related table records")
 			return nil
@@ -1602,6 +2242,7 @@ func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
 			SourceStids:  upsert.Stids,
 			Label:        upsert.Label,
 		}
+		j.upsertBeginAt = time.Now()
 		j.progress.NextSubVolatile(BeginTransaction, inMemoryData)
 
 	case BeginTransaction:
@@ -1625,12 +2266,20 @@ func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
 		}
 
 		var beginTxnResp *festruct.TBeginTxnResult_
+		beginAt := time.Now()
+		span := xtrace.Start("BeginTransaction",
+			xtrace.Job(j.Name), xtrace.CommitSeq(commitSeq), xtrace.TableIds(inMemoryData.DestTableIds))
 		if isTxnInsert {
 			// when txn insert, give an array length in BeginTransaction, it will return a list of stid
 			beginTxnResp, err = destRpc.BeginTransactionForTxnInsert(dest, label, inMemoryData.DestTableIds, int64(len(sourceStids)))
 		} else {
 			beginTxnResp, err = destRpc.BeginTransaction(dest, label, inMemoryData.DestTableIds)
 		}
+		if beginTxnResp != nil {
+			span.SetAttributes(xtrace.TxnId(beginTxnResp.GetTxnId()))
+		}
+		span.End(err)
+		xmetrics.ObserveTxn(j.Name, xmetrics.TxnBegin, time.Since(beginAt))
 
 		if err != nil {
 			return err
@@ -1687,21 +2336,23 @@ func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
 			destTableId := inMemoryData.DestTableIds[0]
 
 			// When txn insert, use subTxnInfos to commit rather than commitInfos.
-			subTxnInfos, err := j.ingestBinlogForTxnInsert(txnId, tableRecords, stidMap, destTableId)
+			subTxnInfos, ingestStats, err := j.ingestBinlogForTxnInsert(txnId, tableRecords, stidMap, destTableId)
 			if err != nil {
 				rollback(err, inMemoryData)
 				return err
 			} else {
 				inMemoryData.SubTxnInfos = subTxnInfos
+				inMemoryData.IngestStats = ingestStats
 				j.progress.NextSubCheckpoint(CommitTransaction, inMemoryData)
 			}
 		} else {
-			commitInfos, err := j.ingestBinlog(txnId, tableRecords)
+			commitInfos, ingestStats, err := j.ingestBinlog(txnId, tableRecords)
 			if err != nil {
 				rollback(err, inMemoryData)
 				return err
 			} else {
 				inMemoryData.CommitInfos = commitInfos
+				inMemoryData.IngestStats = ingestStats
 				j.progress.NextSubCheckpoint(CommitTransaction, inMemoryData)
 			}
 		}
@@ -1725,11 +2376,16 @@ func (j *Job) handleUpsert(binlog *festruct.TBinlog) error {
 		isTxnInsert := inMemoryData.IsTxnInsert
 		subTxnInfos := inMemoryData.SubTxnInfos
 		var resp *festruct.TCommitTxnResult_
+		commitAt := time.Now()
+		span := xtrace.Start("CommitTransaction",
+			xtrace.Job(j.Name), xtrace.CommitSeq(inMemoryData.CommitSeq), xtrace.TxnId(txnId))
 		if isTxnInsert {
 			resp, err = destRpc.CommitTransactionForTxnInsert(dest, txnId, true, subTxnInfos)
 		} else {
 			resp, err = destRpc.CommitTransaction(dest, txnId, commitInfos)
 		}
+		span.End(err)
+		xmetrics.ObserveTxn(j.Name, xmetrics.TxnCommit, time.Since(commitAt))
 		if err != nil {
 			rollback(err, inMemoryData)
 			return err
@@ -1806,6 +2462,12 @@ func (j *Job) handleAddPartition(binlog *festruct.TBinlog) error {
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(addPartition.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	if addPartition.IsTemp {
 		log.Infof("skip add temporary partition because backup/restore table with temporary partitions is not supported yet")
 		return nil
@@ -1852,6 +2514,12 @@ func (j *Job) handleDropPartition(binlog *festruct.TBinlog) error {
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(dropPartition.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	var destTableName string
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
@@ -1892,13 +2560,26 @@ func (j *Job) handleCreateTable(binlog *festruct.TBinlog) error {
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(createTable.TableId, createTable.TableName); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	if createTable.IsCreateMaterializedView() {
 		log.Warnf("create async materialized view is not supported yet, skip this binlog")
 		return nil
 	}
 
+	// The dest table name is empty if the `TableName` is not set, see below for details.
+	destTableName := j.getDestTableName(strings.TrimSpace(createTable.TableName))
+	if destTableName == "" && !j.Extra.TableRename.IsEmpty() {
+		return xerror.Errorf(xerror.Normal, "the table name of create table binlog is required by the table rename rule, table id: %d",
+			createTable.TableId)
+	}
+
 	if featureCreateViewDropExists {
-		tableName := strings.TrimSpace(createTable.TableName)
+		tableName := destTableName
 		if createTable.IsCreateView() && len(tableName) > 0 {
 			// drop view if exists
 			log.Infof("feature_create_view_drop_exists is enabled, try drop view %s before creating", tableName)
@@ -1921,11 +2602,11 @@ func (j *Job) handleCreateTable(binlog *festruct.TBinlog) error {
 	//
 	// See test_cds_fullsync_tbl_drop_create.groovy for details
 	if j.SyncType == DBSync && !createTable.IsCreateView() {
-		if exists, err := j.IDest.CheckTableExistsByName(createTable.TableName); err != nil {
+		if exists, err := j.IDest.CheckTableExistsByName(destTableName); err != nil {
 			return err
 		} else if exists {
 			log.Warnf("the dest table %s already exists, force partial snapshot, commit seq: %d",
-				createTable.TableName, binlog.GetCommitSeq())
+				destTableName, binlog.GetCommitSeq())
 			replace := true
 			return j.newPartialSnapshot(createTable.TableId, createTable.TableName, nil, replace)
 		}
@@ -1934,8 +2615,11 @@ func (j *Job) handleCreateTable(binlog *festruct.TBinlog) error {
 	if featureFilterStorageMedium {
 		createTable.Sql = FilterStorageMediumFromCreateTableSql(createTable.Sql)
 	}
+	if createTable.IsCreateView() {
+		createTable.Sql = j.renameViewTables(createTable.Sql)
+	}
 
-	if err = j.IDest.CreateTableOrView(createTable, j.Src.Database); err != nil {
+	if err = j.IDest.CreateTableOrView(createTable, j.Src.Database, destTableName); err != nil {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "Can not found function") {
 			log.Warnf("skip creating table/view because the UDF function is not supported yet: %s", errMsg)
@@ -1969,7 +2653,7 @@ func (j *Job) handleCreateTable(binlog *festruct.TBinlog) error {
 	}
 
 	var destTableId int64
-	destTableId, err = j.destMeta.GetTableId(srcTableName)
+	destTableId, err = j.destMeta.GetTableId(j.getDestTableName(srcTableName))
 	if err != nil {
 		return err
 	}
@@ -2001,6 +2685,12 @@ func (j *Job) handleDropTable(binlog *festruct.TBinlog) error {
 		return err
 	}
 
+	if filtered, err := j.isTableFiltered(dropTable.TableId, dropTable.TableName); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	if !dropTable.IsView {
 		if _, ok := j.progress.TableMapping[dropTable.TableId]; !ok {
 			log.Warnf("the dest table is not found, skip drop table binlog, src table id: %d, commit seq: %d",
@@ -2030,6 +2720,7 @@ func (j *Job) handleDropTable(binlog *festruct.TBinlog) error {
 
 		tableName = srcTable.Name
 	}
+	tableName = j.getDestTableName(tableName)
 
 	if dropTable.IsView {
 		if err = j.IDest.DropView(tableName); err != nil {
@@ -2077,6 +2768,12 @@ func (j *Job) handleModifyProperty(binlog *festruct.TBinlog) error {
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(modifyProperty.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	var destTableName string
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
@@ -2101,6 +2798,12 @@ func (j *Job) handleAlterJob(binlog *festruct.TBinlog) error {
 		return err
 	}
 
+	if filtered, err := j.isTableFiltered(alterJob.TableId, alterJob.TableName); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	if _, err := j.getDestTableIdBySrc(alterJob.TableId); err == ErrMaterializedViewTable {
 		log.Warnf("skip alter job for materialized view table %d", alterJob.TableId)
 		return nil
@@ -2176,7 +2879,7 @@ func (j *Job) handleSchemaChange(alterJob *record.AlterJobV2) error {
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
 	} else {
-		destTableName = alterJob.TableName
+		destTableName = j.getDestTableName(alterJob.TableName)
 	}
 
 	if featureSchemaChangePartialSync && alterJob.Type == record.ALTER_JOB_SCHEMA_CHANGE {
@@ -2236,9 +2939,19 @@ func (j *Job) handleLightningSchemaChange(binlog *festruct.TBinlog) error {
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(lightningSchemaChange.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	tableAlias := ""
 	if j.isTableSyncWithAlias() {
 		tableAlias = j.Dest.Table
+	} else if j.SyncType == DBSync && !j.Extra.TableRename.IsEmpty() {
+		if tableAlias, err = j.getDestNameBySrcId(lightningSchemaChange.TableId); err != nil {
+			return err
+		}
 	}
 	return j.IDest.LightningSchemaChange(j.Src.Database, tableAlias, lightningSchemaChange)
 }
@@ -2262,6 +2975,12 @@ func (j *Job) handleRenameColumnRecord(commitSeq int64, renameColumn *record.Ren
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(renameColumn.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	var destTableName string
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
@@ -2295,6 +3014,12 @@ func (j *Job) handleModifyCommentRecord(commitSeq int64, modifyComment *record.M
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(modifyComment.TblId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	var destTableName string
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
@@ -2323,10 +3048,16 @@ func (j *Job) handleTruncateTable(binlog *festruct.TBinlog) error {
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(truncateTable.TableId, truncateTable.TableName); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	var destTableName string
 	switch j.SyncType {
 	case DBSync:
-		destTableName = truncateTable.TableName
+		destTableName = j.getDestTableName(truncateTable.TableName)
 	case TableSync:
 		destTableName = j.Dest.Table
 	default:
@@ -2356,6 +3087,12 @@ func (j *Job) handleReplacePartitions(binlog *festruct.TBinlog) error {
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(replacePartition.TableId, replacePartition.TableName); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	if _, err := j.getDestTableIdBySrc(replacePartition.TableId); err == ErrMaterializedViewTable {
 		log.Warnf("skip replace partitions for materialized view table %d", replacePartition.TableId)
 		return nil
@@ -2419,6 +3156,12 @@ func (j *Job) handleRenameTableRecord(commitSeq int64, renameTable *record.Renam
 		return nil
 	}
 
+	if j.hasTableSelection() {
+		if skip, err := j.handleRenameFilteredTable(renameTable); err != nil || skip {
+			return err
+		}
+	}
+
 	var destTableName string
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
@@ -2441,7 +3184,16 @@ func (j *Job) handleRenameTableRecord(commitSeq int64, renameTable *record.Renam
 		renameTable.OldTableName = destTableName
 	}
 
-	err := j.IDest.RenameTable(destTableName, renameTable)
+	destRenameTable := renameTable
+	if !j.Extra.TableRename.IsEmpty() {
+		// rename the dest table with the dest names, but keep the source names in the table name mapping.
+		destRenameTable = &record.RenameTable{}
+		*destRenameTable = *renameTable
+		destRenameTable.OldTableName = destTableName
+		destRenameTable.NewTableName = j.getDestTableName(renameTable.NewTableName)
+	}
+
+	err := j.IDest.RenameTable(destTableName, destRenameTable)
 	if err != nil {
 		return err
 	}
@@ -2455,6 +3207,58 @@ func (j *Job) handleRenameTableRecord(commitSeq int64, renameTable *record.Renam
 	return nil
 }
 
+// A table might start or stop matching the table filter after renaming, handle it and
+// returns whether the rename binlog should be skipped.
+func (j *Job) handleRenameFilteredTable(renameTable *record.RenameTable) (bool, error) {
+	tableId := renameTable.TableId
+	oldTableName := renameTable.OldTableName
+	if oldTableName == "" {
+		oldTableName = j.progress.TableNameMapping[tableId]
+	}
+
+	var wasMatched bool
+	if oldTableName != "" {
+		wasMatched = j.matchTable(oldTableName)
+	} else {
+		_, wasMatched = j.progress.TableMapping[tableId]
+	}
+	isMatched := j.matchTable(renameTable.NewTableName)
+
+	switch {
+	case wasMatched && isMatched:
+		return false, nil
+	case !wasMatched && !isMatched:
+		log.Infof("filter the rename table binlog, table %s (id %d) doesn't match the table filter, new name: %s",
+			oldTableName, tableId, renameTable.NewTableName)
+		return true, nil
+	case !wasMatched && isMatched:
+		log.Infof("table %s (id %d) is renamed to %s and starts matching the table filter, force partial snapshot",
+			oldTableName, tableId, renameTable.NewTableName)
+		replace := false // the dest table is not exists
+		return true, j.newPartialSnapshot(tableId, renameTable.NewTableName, nil, replace)
+	default:
+		destTableName, err := j.getDestNameBySrcId(tableId)
+		if err != nil {
+			return true, err
+		}
+		log.Infof("table %s (id %d) is renamed to %s and stops matching the table filter, drop the dest table %s",
+			oldTableName, tableId, renameTable.NewTableName, destTableName)
+		if err := j.IDest.DropTable(destTableName, false); err != nil {
+			return true, err
+		}
+		j.destMeta.ClearTablesCache()
+		j.forgetTable(tableId)
+		return true, nil
+	}
+}
+
+// Forget the table which is no longer synced.
+func (j *Job) forgetTable(tableId int64) {
+	delete(j.progress.TableNameMapping, tableId)
+	delete(j.progress.TableMapping, tableId)
+	delete(j.progress.TableCommitSeqMap, tableId)
+}
+
 func (j *Job) handleReplaceTable(binlog *festruct.TBinlog) error {
 	log.Infof("handle replace table binlog, prevCommitSeq: %d, commitSeq: %d",
 		j.progress.PrevCommitSeq, j.progress.CommitSeq)
@@ -2475,6 +3279,12 @@ func (j *Job) handleReplaceTableRecord(commitSeq int64, record *record.ReplaceTa
 		return j.newSnapshot(commitSeq)
 	}
 
+	if j.hasTableSelection() {
+		if skip, err := j.handleReplaceFilteredTable(record); err != nil || skip {
+			return err
+		}
+	}
+
 	if _, err := j.getDestTableIdBySrc(record.OriginTableId); err == ErrMaterializedViewTable {
 		log.Warnf("skip replace table for materialized view table %d", record.OriginTableId)
 		return nil
@@ -2511,8 +3321,8 @@ func (j *Job) handleReplaceTableRecord(commitSeq int64, record *record.ReplaceTa
 		}
 	}
 
-	toName := record.OriginTableName
-	fromName := record.NewTableName
+	toName := j.getDestTableName(record.OriginTableName)
+	fromName := j.getDestTableName(record.NewTableName)
 	if err := j.IDest.ReplaceTable(fromName, toName, record.SwapTable); err != nil {
 		return err
 	}
@@ -2534,6 +3344,50 @@ func (j *Job) handleReplaceTableRecord(commitSeq int64, record *record.ReplaceTa
 	return nil
 }
 
+// Replace table is a rename of both tables if swap, handle the tables that start or stop
+// matching the table filter, and returns whether the replace binlog should be skipped.
+//
+// After replacing, the new table has the origin table name, and the origin table has the
+// new table name (swap = true) or is dropped (swap = false).
+func (j *Job) handleReplaceFilteredTable(record *record.ReplaceTableRecord) (bool, error) {
+	originMatched := j.matchTable(record.OriginTableName)
+	newMatched := j.matchTable(record.NewTableName)
+
+	switch {
+	case originMatched && newMatched:
+		return false, nil
+	case !originMatched && !newMatched:
+		log.Infof("filter the replace table binlog, both origin table %s and new table %s don't match the table filter",
+			record.OriginTableName, record.NewTableName)
+		return true, nil
+	case originMatched:
+		// The new table starts matching and replaces the dest table, the origin table
+		// (if swapped) stops matching.
+		log.Infof("replace table %s with the filtered table %s, force partial snapshot, swap: %t",
+			record.OriginTableName, record.NewTableName, record.SwapTable)
+		j.forgetTable(record.OriginTableId)
+		replace := true
+		return true, j.newPartialSnapshot(record.NewTableId, record.OriginTableName, nil, replace)
+	default:
+		// The new table stops matching, and the origin table (if swapped) starts matching.
+		j.forgetTable(record.NewTableId)
+		if record.SwapTable {
+			log.Infof("swap the filtered table %s with table %s, force partial snapshot",
+				record.OriginTableName, record.NewTableName)
+			replace := true
+			return true, j.newPartialSnapshot(record.OriginTableId, record.NewTableName, nil, replace)
+		}
+
+		log.Infof("table %s is replaced by table %s and stops matching the table filter, drop the dest table",
+			record.OriginTableName, record.NewTableName)
+		if err := j.IDest.DropTable(j.getDestTableName(record.NewTableName), false); err != nil {
+			return true, err
+		}
+		j.destMeta.ClearTablesCache()
+		return true, nil
+	}
+}
+
 func (j *Job) handleModifyTableAddOrDropInvertedIndices(binlog *festruct.TBinlog) error {
 	log.Infof("handle modify table add or drop inverted indices binlog, prevCommitSeq: %d, commitSeq: %d",
 		j.progress.PrevCommitSeq, j.progress.CommitSeq)
@@ -2552,6 +3406,12 @@ func (j *Job) handleModifyTableAddOrDropInvertedIndicesRecord(commitSeq int64, r
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(record.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	if record.IsDropInvertedIndex {
 		var destTableName string
 		if j.SyncType == TableSync {
@@ -2570,6 +3430,15 @@ func (j *Job) handleModifyTableAddOrDropInvertedIndicesRecord(commitSeq int64, r
 	var tableName string
 	if j.SyncType == TableSync {
 		tableName = j.Src.Table
+	} else if !j.Extra.TableRename.IsEmpty() {
+		// the dest table name is different from the source
+		if name, err := j.getSrcTableNameById(record.TableId); err != nil {
+			return err
+		} else if name == "" {
+			return xerror.Errorf(xerror.Normal, "src table name not found, table id: %d", record.TableId)
+		} else {
+			tableName = name
+		}
 	} else {
 		if name, err := j.getDestNameBySrcId(record.TableId); err != nil {
 			return xerror.Errorf(xerror.Normal, "get dest table name by src id %d failed, err: %v", record.TableId, err)
@@ -2600,6 +3469,12 @@ func (j *Job) handleIndexChangeJobRecord(commitSeq int64, indexChangeJob *record
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(indexChangeJob.TableId, indexChangeJob.TableName); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	if indexChangeJob.JobState != record.INDEX_CHANGE_JOB_STATE_FINISHED ||
 		indexChangeJob.IsDropOp {
 		log.Debugf("skip index change job binlog, job state: %s, is drop op: %t",
@@ -2611,7 +3486,7 @@ func (j *Job) handleIndexChangeJobRecord(commitSeq int64, indexChangeJob *record
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
 	} else {
-		destTableName = indexChangeJob.TableName
+		destTableName = j.getDestTableName(indexChangeJob.TableName)
 	}
 
 	return j.IDest.BuildIndex(destTableName, indexChangeJob)
@@ -2635,14 +3510,62 @@ func (j *Job) handleAlterViewDefRecord(commitSeq int64, alterView *record.AlterV
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(alterView.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	viewName, err := j.getDestNameBySrcId(alterView.TableId)
 	if err != nil {
 		return err
 	}
 
+	alterView.InlineViewDef = j.renameViewTables(alterView.InlineViewDef)
 	return j.IDest.AlterViewDef(j.Src.Database, viewName, alterView)
 }
 
+// The tables referenced by the view are renamed by the table rename rule too.
+func (j *Job) renameViewTables(viewSql string) string {
+	if j.SyncType != DBSync || j.Extra.TableRename.IsEmpty() {
+		return viewSql
+	}
+	return base.RenameViewTables(viewSql, j.Src.Database, j.getDestTableName)
+}
+
+// The views restored by the snapshot still reference the source table names, recreate them
+// from the source with the renamed tables.
+func (j *Job) recreateRenamedViews() error {
+	if j.SyncType != DBSync || j.Extra.TableRename.IsEmpty() {
+		return nil
+	}
+
+	views, err := j.ISrc.GetAllViews()
+	if err != nil {
+		return err
+	}
+	for _, view := range views {
+		if !j.matchTable(view) {
+			continue
+		}
+		createSql, err := j.ISrc.GetCreateViewSql(view)
+		if err != nil {
+			return err
+		}
+
+		destViewName := j.getDestTableName(view)
+		log.Infof("fullsync recreate the view %s with the renamed tables, dest view: %s", view, destViewName)
+		if err := j.IDest.DropView(destViewName); err != nil {
+			return err
+		}
+		createView := &record.CreateTable{Sql: j.renameViewTables(createSql)}
+		if err := j.IDest.CreateTableOrView(createView, j.Src.Database, destViewName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (j *Job) handleRenamePartition(binlog *festruct.TBinlog) error {
 	log.Infof("handle rename partition binlog, prevCommitSeq: %d, commitSeq: %d",
 		j.progress.PrevCommitSeq, j.progress.CommitSeq)
@@ -2660,6 +3583,12 @@ func (j *Job) handleRenamePartitionRecord(commitSeq int64, renamePartition *reco
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(renamePartition.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	var destTableName string
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
@@ -2681,6 +3610,12 @@ func (j *Job) handleRenamePartitionRecord(commitSeq int64, renamePartition *reco
 		tableName := destTableName
 		if j.isTableSyncWithAlias() {
 			tableName = j.Src.Table
+		} else if j.SyncType == DBSync && !j.Extra.TableRename.IsEmpty() {
+			if name, err := j.getSrcTableNameById(renamePartition.TableId); err != nil {
+				return err
+			} else if name != "" {
+				tableName = name
+			}
 		}
 		return j.newPartialSnapshot(renamePartition.TableId, tableName, nil, replace)
 	}
@@ -2705,6 +3640,12 @@ func (j *Job) handleRenameRollupRecord(commitSeq int64, renameRollup *record.Ren
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(renameRollup.TableId, ""); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	var destTableName string
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
@@ -2726,6 +3667,12 @@ func (j *Job) handleRenameRollupRecord(commitSeq int64, renameRollup *record.Ren
 		tableName := destTableName
 		if j.isTableSyncWithAlias() {
 			tableName = j.Src.Table
+		} else if j.SyncType == DBSync && !j.Extra.TableRename.IsEmpty() {
+			if name, err := j.getSrcTableNameById(renameRollup.TableId); err != nil {
+				return err
+			} else if name != "" {
+				tableName = name
+			}
 		}
 		return j.newPartialSnapshot(renameRollup.TableId, tableName, nil, replace)
 	}
@@ -2751,11 +3698,17 @@ func (j *Job) handleDropRollupRecord(commitSeq int64, dropRollup *record.DropRol
 		return nil
 	}
 
+	if filtered, err := j.isTableFiltered(dropRollup.TableId, dropRollup.TableName); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	var destTableName string
 	if j.SyncType == TableSync {
 		destTableName = j.Dest.Table
 	} else {
-		destTableName = dropRollup.TableName
+		destTableName = j.getDestTableName(dropRollup.TableName)
 	}
 
 	return j.IDest.DropRollup(destTableName, dropRollup.IndexName)
@@ -2779,6 +3732,16 @@ func (j *Job) handleRecoverInfoRecord(commitSeq int64, recoverInfo *record.Recov
 		return nil
 	}
 
+	recoverTableName := recoverInfo.TableName
+	if recoverInfo.IsRecoverTable() && recoverInfo.NewTableName != "" {
+		recoverTableName = recoverInfo.NewTableName
+	}
+	if filtered, err := j.isTableFiltered(recoverInfo.TableId, recoverTableName); err != nil {
+		return err
+	} else if filtered {
+		return nil
+	}
+
 	if recoverInfo.IsRecoverTable() {
 		var tableName string
 		if recoverInfo.NewTableName != "" {
@@ -2900,6 +3863,32 @@ func (j *Job) handleBinlogs(binlogs []*festruct.TBinlog) (error, bool) {
 	log.Infof("handle binlogs, binlogs size: %d", len(binlogs))
 
 	for _, binlog := range binlogs {
+		if binlog.IsSetTimestamp() {
+			atomic.StoreInt64(&j.rawStatus.pendingTimestamp, binlog.GetTimestamp())
+			atomic.StoreInt64(&j.rawStatus.caughtUpAt, 0)
+		}
+		if binlog.GetType() != festruct.TBinlogType_UPSERT || j.isBeyondStopPoint(binlog) {
+			if err := j.flushFilteredTables(); err != nil {
+				return err, false
+			}
+		}
+		if j.isBeyondStopPoint(binlog) {
+			return j.reachStopPoint(), true
+		}
+
+		if j.isBinlogDelayed(binlog) {
+			log.Debugf("hold back binlog %d until the apply delay %ds passed, binlog timestamp: %d",
+				binlog.GetCommitSeq(), j.Extra.ApplyDelaySeconds, binlog.GetTimestamp())
+			return nil, true
+		}
+
+		if held, err := j.holdBinlog(binlog); err != nil {
+			return err, false
+		} else if held {
+			return nil, true
+		}
+		j.updateLagSeconds(lagSeconds(time.Now().UnixMilli(), binlog.GetTimestamp()))
+
 		// Step 1: dispatch handle binlog
 		if err := j.handleBinlog(binlog); err != nil {
 			log.Errorf("handle binlog failed, prevCommitSeq: %d, commitSeq: %d, binlog type: %s, binlog data: %s",
@@ -2932,18 +3921,93 @@ func (j *Job) handleBinlogs(binlogs []*festruct.TBinlog) (error, bool) {
 		}
 
 		// Step 4: update progress to db
+		if binlog.GetType() == festruct.TBinlogType_UPSERT && binlog.IsSetTimestamp() {
+			j.progress.LastUpsertTimestamp = binlog.GetTimestamp()
+		}
 		if !j.progress.IsDone() {
 			j.progress.Done()
 		}
+		atomic.StoreInt64(&j.rawStatus.appliedTimestamp, binlog.GetTimestamp())
+		atomic.StoreInt64(&j.rawStatus.commitSeq, j.progress.CommitSeq)
+	}
+	if err := j.flushFilteredTables(); err != nil {
+		return err, false
 	}
 	return nil, false
 }
 
-func (j *Job) handleBinlog(binlog *festruct.TBinlog) error {
+// Hold the binlog and pause the job if the ddl policy requires approval, returns whether
+// the binlog is held.
+func (j *Job) holdBinlog(binlog *festruct.TBinlog) (bool, error) {
+	if j.Extra.DDLPolicy.IsEmpty() {
+		return false, nil
+	}
+
+	binlogType, data := unwrapBinlog(binlog)
+	if j.Extra.DDLPolicy.Action(binlogType) != DDLHold {
+		return false, nil
+	}
+
+	commitSeq := binlog.GetCommitSeq()
+	if j.Extra.SkipBinlog && j.Extra.SkipBy == SkipBySilence && j.Extra.SkipCommitSeq == commitSeq {
+		// it will be skipped in handleBinlog.
+		j.progress.HeldBinlog = nil
+		return false, nil
+	}
+	if held := j.progress.HeldBinlog; held != nil && held.CommitSeq == commitSeq {
+		switch held.Decision {
+		case BinlogApproved:
+			log.Infof("the held binlog %d is approved by %s, binlog type: %s", commitSeq, held.Operator, binlogType)
+			j.progress.HeldBinlog = nil
+			return false, nil
+		case BinlogRejected:
+			// it will be skipped in handleBinlog.
+			return false, nil
+		}
+	}
+
+	log.Warnf("hold binlog %d by the ddl policy and pause the job, binlog type: %s, binlog data: %s",
+		commitSeq, binlogType, data)
+	j.progress.HeldBinlog = &HeldBinlog{
+		CommitSeq: commitSeq,
+		Type:      binlogType.String(),
+		Data:      data,
+		HeldAt:    time.Now().Unix(),
+	}
+	j.progress.Persist()
+
+	originState := j.State
+	j.State = JobPaused
+	if err := j.persistJob(); err != nil {
+		j.State = originState
+		return false, err
+	}
+	j.updateJobStatus()
+	j.recordEvent(JobEventPaused, JobEventDetail{CommitSeq: commitSeq, Reason: "binlog held by ddl policy"},
+		"job paused, binlog %d of type %s is held by the ddl policy", commitSeq, binlogType)
+	return true, nil
+}
+
+// Whether the binlog is too recent to sync, in the delayed replica mode.
+func (j *Job) isBinlogDelayed(binlog *festruct.TBinlog) bool {
+	if j.Extra.ApplyDelaySeconds <= 0 || !binlog.IsSetTimestamp() {
+		return false
+	}
+	applyAt := binlog.GetTimestamp() + j.Extra.ApplyDelaySeconds*1000
+	return time.Now().UnixMilli() < applyAt
+}
+
+func (j *Job) handleBinlog(binlog *festruct.TBinlog) (err error) {
 	if binlog == nil || !binlog.IsSetCommitSeq() {
 		return xerror.Errorf(xerror.Normal, "invalid binlog: %v", binlog)
 	}
 
+	span := xtrace.Start("HandleBinlog",
+		xtrace.Job(j.Name),
+		xtrace.CommitSeq(binlog.GetCommitSeq()),
+		xtrace.BinlogType(binlog.GetType().String()))
+	defer func() { span.End(err) }()
+
 	if !j.progress.IsDone() {
 		return xerror.Errorf(xerror.Normal, "the progress isn't done, need rollback, commit seq: %d", j.progress.CommitSeq)
 	}
@@ -2953,6 +4017,9 @@ func (j *Job) handleBinlog(binlog *festruct.TBinlog) error {
 	// Step 2: update job progress
 	j.progress.StartHandle(binlog.GetCommitSeq())
 	xmetrics.HandlingBinlog(j.Name, binlog.GetCommitSeq())
+	xmetrics.HandleBinlog(j.Name, binlog.GetType().String(), len(binlog.GetData()))
+	binlogType := binlog.GetType().String()
+	j.rawStatus.handlingBinlogType.Store(&binlogType)
 
 	// Skip binlog conditionally
 	if j.Extra.SkipBinlog && j.Extra.SkipBy == SkipBySilence && j.Extra.SkipCommitSeq == binlog.GetCommitSeq() {
@@ -2961,6 +4028,21 @@ func (j *Job) handleBinlog(binlog *festruct.TBinlog) error {
 		return nil
 	}
 
+	if held := j.progress.HeldBinlog; held != nil && held.CommitSeq == binlog.GetCommitSeq() && held.Decision == BinlogRejected {
+		log.Warnf("skip binlog %d rejected by %s, binlog type: %s, binlog data: %s",
+			binlog.GetCommitSeq(), held.Operator, held.Type, held.Data)
+		j.progress.HeldBinlog = nil
+		return nil
+	}
+
+	if !j.Extra.DDLPolicy.IsEmpty() {
+		if binlogType, _ := unwrapBinlog(binlog); j.Extra.DDLPolicy.Action(binlogType) == DDLIgnore {
+			log.Warnf("ignore binlog %d by the ddl policy, binlog type: %s, binlog data: %s",
+				binlog.GetCommitSeq(), binlogType, binlog.GetData())
+			return nil
+		}
+	}
+
 	if utils.HasJobFailpoint(j.Name, "handle_binlog_failed") {
 		log.Warnf("fail to handle binlog by failpoint, binlog type: %s, binlog data: %s", binlog.GetType(), binlog.GetData())
 		return xerror.Errorf(xerror.Normal, "fail to handle binlog by failpoint")
@@ -3043,6 +4125,10 @@ func (j *Job) incrementalSync() error {
 		return j.recoverIncrementalSync()
 	}
 
+	if j.isStopCommitSeqReached(j.progress.CommitSeq) {
+		return j.reachStopPoint()
+	}
+
 	// Force fullsync unconditionally
 	if j.Extra.SkipBinlog && j.Extra.SkipBy == SkipByFullSync {
 		log.Warnf("skip binlog via fullsync by user, commit seq %d", j.progress.CommitSeq)
@@ -3064,7 +4150,7 @@ func (j *Job) incrementalSync() error {
 		commitSeq := j.progress.CommitSeq
 		log.Debugf("src: %s, commitSeq: %v", src, commitSeq)
 
-		getBinlogResp, err := srcRpc.GetBinlog(src, commitSeq)
+		getBinlogResp, err := j.getBinlog(srcRpc, src, commitSeq)
 		if err != nil {
 			return err
 		}
@@ -3076,6 +4162,10 @@ func (j *Job) incrementalSync() error {
 		case tstatus.TStatusCode_OK:
 		case tstatus.TStatusCode_BINLOG_TOO_OLD_COMMIT_SEQ:
 		case tstatus.TStatusCode_BINLOG_TOO_NEW_COMMIT_SEQ:
+			// all binlogs are synced
+			atomic.StoreInt64(&j.rawStatus.pendingTimestamp, 0)
+			atomic.StoreInt64(&j.rawStatus.caughtUpAt, time.Now().UnixMilli())
+			j.updateLagSeconds(0)
 			return nil
 		case tstatus.TStatusCode_BINLOG_DISABLE:
 			return xerror.Errorf(xerror.Normal, "binlog is disabled")
@@ -3103,6 +4193,114 @@ func (j *Job) incrementalSync() error {
 	}
 }
 
+// Read the binlogs via the fanout group if the job belongs to one.
+func (j *Job) getBinlog(srcRpc rpc.IFeRpc, src *base.Spec, commitSeq int64) (resp *festruct.TGetBinlogResult_, err error) {
+	span := xtrace.Start("GetBinlog", xtrace.Job(j.Name), xtrace.CommitSeq(commitSeq))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(xtrace.Binlogs(len(resp.GetBinlogs())))
+		}
+		span.End(err)
+	}()
+
+	if j.Extra.FanoutGroup == "" {
+		return srcRpc.GetBinlog(src, commitSeq)
+	}
+	return binlogFanouts.get(j.Extra.FanoutGroup).GetBinlog(j.Name, srcRpc, src, commitSeq)
+}
+
+// Create the full sync snapshot, or reuse the one created by the other jobs of the fanout group.
+func (j *Job) createFullSyncSnapshot(prefix string, backupTableList []string) (string, error) {
+	create := func() (string, error) {
+		snapshotName := NewLabelWithTs(prefix)
+		if err := j.ISrc.CreateSnapshot(snapshotName, backupTableList); err != nil {
+			return "", err
+		}
+		return snapshotName, nil
+	}
+
+	if j.Extra.FanoutGroup == "" {
+		return create()
+	}
+	return binlogFanouts.get(j.Extra.FanoutGroup).AcquireSnapshot(backupTableList, create)
+}
+
+func (j *Job) forgetFanoutSnapshot(snapshotName string) {
+	if j.Extra.FanoutGroup != "" {
+		binlogFanouts.get(j.Extra.FanoutGroup).ForgetSnapshot(snapshotName)
+	}
+}
+
+func (j *Job) hasStopPoint() bool {
+	return (j.Extra.StopAtCommitSeq > 0 || j.Extra.StopAtTimestamp > 0) && j.Extra.StopReachedCommitSeq == 0
+}
+
+// Whether the binlogs up to the stop commit seq are synced, or restored by a snapshot.
+func (j *Job) isStopCommitSeqReached(commitSeq int64) bool {
+	return j.hasStopPoint() && j.Extra.StopAtCommitSeq > 0 && commitSeq >= j.Extra.StopAtCommitSeq
+}
+
+// Whether the binlog is beyond the stop point, so it should not be synced. The stop timestamp is
+// only reached once a later binlog is read, it is never reached if the source is idle.
+func (j *Job) isBeyondStopPoint(binlog *festruct.TBinlog) bool {
+	if !j.hasStopPoint() {
+		return false
+	}
+	if j.Extra.StopAtCommitSeq > 0 && binlog.GetCommitSeq() > j.Extra.StopAtCommitSeq {
+		return true
+	}
+	return j.Extra.StopAtTimestamp > 0 && binlog.GetTimestamp() > j.Extra.StopAtTimestamp
+}
+
+// Pause the job since all binlogs up to the stop point are synced.
+func (j *Job) reachStopPoint() error {
+	log.Infof("job %s reaches the stop point, stop at commit seq: %d, timestamp: %d, synced commit seq: %d",
+		j.Name, j.Extra.StopAtCommitSeq, j.Extra.StopAtTimestamp, j.progress.CommitSeq)
+
+	originState := j.State
+	j.State = JobPaused
+	j.Extra.StopReachedCommitSeq = j.progress.CommitSeq
+	if err := j.persistJob(); err != nil {
+		j.State = originState
+		j.Extra.StopReachedCommitSeq = 0
+		return err
+	}
+	j.updateJobStatus()
+	j.recordEvent(JobEventPaused, JobEventDetail{CommitSeq: j.progress.CommitSeq, Reason: "stop point reached"},
+		"job paused at the stop point, synced commit seq %d", j.progress.CommitSeq)
+	return nil
+}
+
+// The tables are restored at their own commit seq, the binlogs of the tables behind are still
+// synced, so the restored commit seq is the minimum of them.
+func (j *Job) getRestoredCommitSeq() int64 {
+	if len(j.progress.TableCommitSeqMap) == 0 {
+		return j.progress.CommitSeq
+	}
+
+	restoredCommitSeq := int64(math.MaxInt64)
+	for _, commitSeq := range j.progress.TableCommitSeqMap {
+		if commitSeq < restoredCommitSeq {
+			restoredCommitSeq = commitSeq
+		}
+	}
+	return restoredCommitSeq
+}
+
+// The snapshot contains all the data when it is created, so the restored tables might be beyond
+// the stop point, pause the job once the restore is persisted. The timestamp of the snapshot is
+// unknown, the stop timestamp is only checked by the binlogs.
+func (j *Job) checkStopPointAfterRestore(restoredCommitSeq int64) error {
+	if !j.isStopCommitSeqReached(restoredCommitSeq) {
+		return nil
+	}
+	if restoredCommitSeq > j.Extra.StopAtCommitSeq {
+		log.Warnf("job %s restores the snapshot at commit seq %d, which is beyond the stop commit seq %d",
+			j.Name, restoredCommitSeq, j.Extra.StopAtCommitSeq)
+	}
+	return j.reachStopPoint()
+}
+
 func (j *Job) recoverJobProgress() error {
 	// parse progress
 	if progress, err := NewJobProgressFromJson(j.Name, j.db); err != nil {
@@ -3189,6 +4387,11 @@ func (j *Job) sync() error {
 			if err := j.persistJob(); err != nil {
 				return err
 			}
+			j.recordEvent(JobEventBinlogSkipped,
+				JobEventDetail{CommitSeq: j.Extra.SkipCommitSeq, Reason: j.Extra.SkipBy},
+				"skip binlog by %s applied", j.Extra.SkipBy)
+			notify.Notify(notify.EventBinlogSkipped, j.Name, "skip binlog by %s applied, commit seq: %d",
+				j.Extra.SkipBy, j.Extra.SkipCommitSeq)
 		}
 	}
 
@@ -3219,11 +4422,30 @@ func (j *Job) handleError(err error) error {
 
 	if xerr.Category() == xerror.Meta {
 		log.Warnf("receive meta category error, make new snapshot, job: %s, err: %v", j.Name, err)
+		j.recordEvent(JobEventMetaErrorFallback,
+			JobEventDetail{CommitSeq: j.progress.CommitSeq, Reason: j.snapshotReason()},
+			"fallback to full sync by meta error: %v", err)
 		_ = j.newSnapshot(j.progress.CommitSeq)
 	}
 	return nil
 }
 
+// Record the error of the sync for the job status.
+func (j *Job) recordError(err error, panicked bool) {
+	jobError := &JobError{
+		Message: err.Error(),
+		Panic:   panicked,
+		Time:    time.Now().Unix(),
+	}
+	var xerr *xerror.XError
+	if errors.As(err, &xerr) {
+		jobError.Category = xerr.Category().Name()
+	}
+	j.rawStatus.lastError.Store(jobError)
+	j.rawStatus.panicked.Store(panicked)
+	atomic.AddInt64(&j.rawStatus.consecutiveFailures, 1)
+}
+
 func (j *Job) run() {
 	ticker := time.NewTicker(SyncDuration)
 	defer ticker.Stop()
@@ -3255,11 +4477,16 @@ func (j *Job) run() {
 
 			err := j.sync()
 			if err == nil {
+				atomic.StoreInt64(&j.rawStatus.consecutiveFailures, 0)
 				break
 			}
 
 			log.Warnf("job sync failed, job: %s, err: %+v", j.Name, err)
 			panicError = j.handleError(err)
+			j.recordError(err, panicError != nil)
+			if panicError != nil {
+				notify.Notify(notify.EventJobPanic, j.Name, "job panic, err: %v", err)
+			}
 		}
 	}
 }
@@ -3270,6 +4497,12 @@ func (j *Job) newSnapshot(commitSeq int64) error {
 	j.progress.PartialSyncData = nil
 	j.progress.TableAliases = nil
 	j.progress.SyncId += 1
+	reason := j.snapshotReason()
+	j.recordEvent(JobEventFullSyncStarted, JobEventDetail{CommitSeq: commitSeq, SyncId: j.progress.SyncId, Reason: reason},
+		"full sync started at commit seq %d", commitSeq)
+	if j.isIncrementalSync() {
+		notify.Notify(notify.EventFullSyncFallback, j.Name, "fallback to full sync at commit seq %d, reason: %s", commitSeq, reason)
+	}
 	switch j.SyncType {
 	case TableSync:
 		j.progress.NextWithPersist(commitSeq, TableFullSync, BeginCreateSnapshot, "")
@@ -3321,6 +4554,9 @@ func (j *Job) newPartialSnapshot(tableId int64, table string, partitions []strin
 		log.Infof("new partial snapshot, commitSeq: %d, table id: %d, table: %s, partitions: %v",
 			commitSeq, tableId, table, partitions)
 	}
+	j.recordEvent(JobEventPartialSyncStarted,
+		JobEventDetail{CommitSeq: commitSeq, SyncId: j.progress.SyncId, Table: table, Reason: j.snapshotReason()},
+		"partial sync of table %s started at commit seq %d, partitions: %v, replace: %t", table, commitSeq, partitions, replace)
 
 	switch j.SyncType {
 	case TableSync:
@@ -3396,10 +4632,30 @@ func (j *Job) desyncDB() error {
 		return err
 	}
 
+	// the sync loop and the update might change the progress and the options.
+	j.lock.Lock()
+
+	// the source names of the synced dest tables.
+	srcTableNames := make(map[string]string)
+	if j.progress != nil {
+		for _, srcTableName := range j.progress.TableNameMapping {
+			srcTableNames[j.getDestTableName(srcTableName)] = srcTableName
+		}
+	}
+
 	tableNames := []string{}
 	for _, tableMeta := range tables {
+		// the tables not selected by this job are synced by other jobs, or not synced at all.
+		if srcTableName, ok := srcTableNames[tableMeta.Name]; ok {
+			if !j.matchTable(srcTableName) {
+				continue
+			}
+		} else if j.hasTableSelection() {
+			continue
+		}
 		tableNames = append(tableNames, tableMeta.Name)
 	}
+	j.lock.Unlock()
 
 	return j.IDest.DesyncTables(tableNames...)
 }
@@ -3415,12 +4671,20 @@ func (j *Job) Desync() error {
 // stop job
 func (j *Job) Stop() {
 	close(j.stop)
+	j.leaveFanout()
 }
 
 // delete job
 func (j *Job) Delete() {
 	j.isDeleted.Store(true)
 	close(j.stop)
+	j.leaveFanout()
+}
+
+func (j *Job) leaveFanout() {
+	if j.Extra.FanoutGroup != "" {
+		binlogFanouts.leave(j.Extra.FanoutGroup, j.Name)
+	}
 }
 
 func (j *Job) maybeDeleted() bool {
@@ -3430,6 +4694,7 @@ func (j *Job) maybeDeleted() bool {
 
 	// job had been deleted
 	log.Infof("job deleted, job: %s, remove in db", j.Name)
+	xmetrics.RemoveJob(j.Name)
 	if err := j.db.RemoveJob(j.Name); err != nil {
 		log.Errorf("remove job failed, job: %s, err: %+v", j.Name, err)
 	}
@@ -3574,24 +4839,136 @@ func (j *Job) changeJobState(state JobState) error {
 func (j *Job) Pause() error {
 	log.Infof("pause job %s", j.Name)
 
-	return j.changeJobState(JobPaused)
+	if err := j.changeJobState(JobPaused); err != nil {
+		return err
+	}
+	j.recordEvent(JobEventPaused, JobEventDetail{}, "job paused")
+	return nil
 }
 
 func (j *Job) Resume() error {
 	log.Infof("resume job %s", j.Name)
 
+	if err := j.changeJobState(JobRunning); err != nil {
+		return err
+	}
+	j.recordEvent(JobEventResumed, JobEventDetail{}, "job resumed")
+	return nil
+}
+
+// DecideHeldBinlog approves or rejects the held binlog, and resumes the job.
+func (j *Job) DecideHeldBinlog(commitSeq int64, decision string, operator string) error {
+	j.lock.Lock()
+	if j.progress == nil || j.progress.HeldBinlog == nil {
+		j.lock.Unlock()
+		return xerror.Errorf(xerror.Normal, "job %s has no held binlog", j.Name)
+	}
+
+	held := j.progress.HeldBinlog
+	if held.CommitSeq != commitSeq {
+		j.lock.Unlock()
+		return xerror.Errorf(xerror.Normal, "the held binlog is %d, not %d", held.CommitSeq, commitSeq)
+	}
+
+	log.Infof("the held binlog %d of job %s is %s by %s, binlog type: %s",
+		commitSeq, j.Name, decision, operator, held.Type)
+	held.Decision = decision
+	held.Operator = operator
+	held.DecidedAt = time.Now().Unix()
+	decided := *held
+	j.progress.BinlogDecisions = append(j.progress.BinlogDecisions, &decided)
+	if len(j.progress.BinlogDecisions) > maxBinlogDecisions {
+		j.progress.BinlogDecisions = j.progress.BinlogDecisions[len(j.progress.BinlogDecisions)-maxBinlogDecisions:]
+	}
+	j.progress.Persist()
+	j.updateJobStatus()
+	j.lock.Unlock()
+
+	eventType := JobEventBinlogApproved
+	if decision == BinlogRejected {
+		eventType = JobEventBinlogRejected
+	}
+	j.recordEvent(eventType, JobEventDetail{CommitSeq: commitSeq, Operator: operator},
+		"the held binlog %d of type %s is %s by %s", commitSeq, held.Type, decision, operator)
+
 	return j.changeJobState(JobRunning)
 }
 
+// PendingBinlog returns the held binlog and the parsed record.
+func (j *Job) PendingBinlog() (*HeldBinlog, any, error) {
+	held := j.rawStatus.heldBinlog.Load()
+	if held == nil {
+		return nil, nil, nil
+	}
+
+	binlogType, err := festruct.TBinlogTypeFromString(held.Type)
+	if err != nil {
+		return nil, nil, xerror.Errorf(xerror.Normal, "invalid binlog type %s", held.Type)
+	}
+	r, err := parseBinlogRecord(binlogType, held.Data)
+	if err != nil {
+		return held, nil, err
+	}
+	return held, r, nil
+}
+
 type RawJobStatus struct {
-	state         int32
-	progressState int32
+	state                int32
+	progressState        int32
+	stopAtCommitSeq      int64
+	stopAtTimestamp      int64
+	stopReachedCommitSeq int64
+	applyDelaySeconds    int64
+	appliedTimestamp     int64 // the source timestamp of the last synced binlog, in ms
+	pendingTimestamp     int64 // the source timestamp of the oldest unapplied binlog seen, in ms
+	caughtUpAt           int64 // the time all binlogs are synced, in ms
+	commitSeq            int64
+	heldBinlog           atomic.Pointer[HeldBinlog]
+	srcSpec              atomic.Pointer[base.Spec] // the copies for the connectivity check
+	destSpec             atomic.Pointer[base.Spec]
+
+	lastError           atomic.Pointer[JobError]
+	consecutiveFailures int64
+	panicked            atomic.Bool
+	handlingBinlogType  atomic.Pointer[string]
+	lagExceeded         atomic.Bool
+
+	createdAt              int64
+	fullSyncStartAt        int64
+	incrementalSyncStartAt int64
+}
+
+// JobError is the error of the last failed sync.
+type JobError struct {
+	Message  string `json:"message"`
+	Category string `json:"category,omitempty"` // the xerror category, eg. rpc, meta
+	Panic    bool   `json:"panic,omitempty"`
+	Time     int64  `json:"time"`
 }
 
 func (j *Job) updateJobStatus() {
 	atomic.StoreInt32(&j.rawStatus.state, int32(j.State))
 	if j.progress != nil {
 		atomic.StoreInt32(&j.rawStatus.progressState, int32(j.progress.SyncState))
+		atomic.StoreInt64(&j.rawStatus.commitSeq, j.progress.CommitSeq)
+		xmetrics.JobState(j.Name, int32(j.State), int32(j.progress.SyncState))
+		atomic.StoreInt64(&j.rawStatus.createdAt, j.progress.CreatedAt)
+		atomic.StoreInt64(&j.rawStatus.fullSyncStartAt, j.progress.FullSyncStartAt)
+		atomic.StoreInt64(&j.rawStatus.incrementalSyncStartAt, j.progress.IncrementalSyncStartAt)
+		// The binlog is handled, or the job has switched to the full sync.
+		if j.progress.IsDone() || !j.isIncrementalSync() {
+			j.rawStatus.handlingBinlogType.Store(nil)
+		}
+	}
+	atomic.StoreInt64(&j.rawStatus.stopAtCommitSeq, j.Extra.StopAtCommitSeq)
+	atomic.StoreInt64(&j.rawStatus.stopAtTimestamp, j.Extra.StopAtTimestamp)
+	atomic.StoreInt64(&j.rawStatus.stopReachedCommitSeq, j.Extra.StopReachedCommitSeq)
+	atomic.StoreInt64(&j.rawStatus.applyDelaySeconds, j.Extra.ApplyDelaySeconds)
+	if j.progress != nil && j.progress.HeldBinlog != nil {
+		heldBinlog := *j.progress.HeldBinlog
+		j.rawStatus.heldBinlog.Store(&heldBinlog)
+	} else {
+		j.rawStatus.heldBinlog.Store(nil)
 	}
 }
 
@@ -3599,23 +4976,120 @@ type JobStatus struct {
 	Name          string `json:"name"`
 	State         string `json:"state"`
 	ProgressState string `json:"progress_state"`
+
+	StopAtCommitSeq      int64 `json:"stop_at_commit_seq,omitempty"`
+	StopAtTimestamp      int64 `json:"stop_at_timestamp,omitempty"`
+	StopReached          bool  `json:"stop_reached,omitempty"`
+	StopReachedCommitSeq int64 `json:"stop_reached_commit_seq,omitempty"`
+
+	// The configured and the actual delay of the delayed replica, in seconds.
+	ApplyDelaySeconds   int64 `json:"apply_delay_seconds,omitempty"`
+	AppliedDelaySeconds int64 `json:"applied_delay_seconds,omitempty"`
+
+	// The binlog held by the ddl policy, waiting for approval.
+	HeldBinlog *HeldBinlog `json:"held_binlog,omitempty"`
+
+	// The job stops syncing once it panics, until the syncer restarts or the job is recreated.
+	LastError           *JobError `json:"last_error,omitempty"`
+	ConsecutiveFailures int64     `json:"consecutive_failures"`
+	Panicked            bool      `json:"panicked"`
+	HandlingBinlogType  string    `json:"handling_binlog_type,omitempty"`
+
+	// The unix timestamps in seconds, zero if the stage isn't reached.
+	CreatedAt              int64 `json:"created_at,omitempty"`
+	FullSyncStartAt        int64 `json:"full_sync_start_at,omitempty"`
+	IncrementalSyncStartAt int64 `json:"incremental_sync_start_at,omitempty"`
 }
 
 func (j *Job) Status() *JobStatus {
 	state := JobState(atomic.LoadInt32(&j.rawStatus.state)).String()
 	progressState := SyncState(atomic.LoadInt32(&j.rawStatus.progressState)).String()
+	stopReachedCommitSeq := atomic.LoadInt64(&j.rawStatus.stopReachedCommitSeq)
+	applyDelaySeconds := atomic.LoadInt64(&j.rawStatus.applyDelaySeconds)
+	var appliedDelaySeconds int64
+	if appliedTimestamp := atomic.LoadInt64(&j.rawStatus.appliedTimestamp); applyDelaySeconds > 0 && appliedTimestamp > 0 {
+		appliedDelaySeconds = (time.Now().UnixMilli() - appliedTimestamp) / 1000
+	}
+
+	var handlingBinlogType string
+	if binlogType := j.rawStatus.handlingBinlogType.Load(); binlogType != nil {
+		handlingBinlogType = *binlogType
+	}
 
 	return &JobStatus{
-		Name:          j.Name,
-		State:         state,
-		ProgressState: progressState,
+		Name:                 j.Name,
+		State:                state,
+		ProgressState:        progressState,
+		StopAtCommitSeq:      atomic.LoadInt64(&j.rawStatus.stopAtCommitSeq),
+		StopAtTimestamp:      atomic.LoadInt64(&j.rawStatus.stopAtTimestamp),
+		StopReached:          stopReachedCommitSeq != 0,
+		StopReachedCommitSeq: stopReachedCommitSeq,
+		ApplyDelaySeconds:    applyDelaySeconds,
+		AppliedDelaySeconds:  appliedDelaySeconds,
+		HeldBinlog:           j.rawStatus.heldBinlog.Load(),
+
+		LastError:           j.rawStatus.lastError.Load(),
+		ConsecutiveFailures: atomic.LoadInt64(&j.rawStatus.consecutiveFailures),
+		Panicked:            j.rawStatus.panicked.Load(),
+		HandlingBinlogType:  handlingBinlogType,
+
+		CreatedAt:              atomic.LoadInt64(&j.rawStatus.createdAt),
+		FullSyncStartAt:        atomic.LoadInt64(&j.rawStatus.fullSyncStartAt),
+		IncrementalSyncStartAt: atomic.LoadInt64(&j.rawStatus.incrementalSyncStartAt),
 	}
 }
 
+// Update the lag gauge, and notify once the lag crosses the threshold.
+func (j *Job) updateLagSeconds(seconds int64) {
+	xmetrics.JobLagSeconds(j.Name, seconds)
+
+	threshold := notify.LagThresholdSeconds()
+	if threshold <= 0 {
+		return
+	}
+	if seconds >= threshold && j.rawStatus.lagExceeded.CompareAndSwap(false, true) {
+		notify.Notify(notify.EventLagExceeded, j.Name, "the lag %ds exceeds the threshold %ds", seconds, threshold)
+	} else if seconds < threshold && j.rawStatus.lagExceeded.CompareAndSwap(true, false) {
+		notify.Notify(notify.EventLagRecovered, j.Name, "the lag %ds is below the threshold %ds", seconds, threshold)
+	}
+}
+
+// Update the lag gauges in commit seqs and seconds, by the commit seq of the last status update,
+// so the lag keeps growing while the job is paused, held or in the full sync. The lag seconds are
+// derived from the binlogs seen by the sync loop, only the lag in commit seqs calls the frontend.
+func (j *Job) updateLagMetrics() {
+	commitSeq := atomic.LoadInt64(&j.rawStatus.commitSeq)
+	src := j.rawStatus.srcSpec.Load()
+	if commitSeq == 0 || src == nil {
+		// the job hasn't finished the first sync yet.
+		return
+	}
+
+	var lag int64
+	if srcRpc, err := j.factory.NewFeRpc(src); err != nil {
+		log.Warnf("update lag metrics of job %s failed: %+v", j.Name, err)
+	} else if resp, err := srcRpc.GetBinlogLag(src, commitSeq); err != nil {
+		log.Warnf("update lag metrics of job %s failed: %+v", j.Name, err)
+	} else {
+		lag = resp.GetLag()
+		xmetrics.JobLagCommitSeqs(j.Name, lag)
+	}
+	j.updateLagSeconds(lagSeconds(time.Now().UnixMilli(), j.oldestUnappliedTimestamp(lag)))
+}
+
 func (j *Job) UpdateHostMapping(srcHostMaps, destHostMaps map[string]string) error {
 	j.lock.Lock()
 	defer j.lock.Unlock()
 
+	// The be rpcs are cached by the backends with the mapped hosts.
+	var staleBackends []*base.Backend
+	if len(srcHostMaps) > 0 {
+		staleBackends = append(staleBackends, mappedBackends(j.srcMeta)...)
+	}
+	if len(destHostMaps) > 0 {
+		staleBackends = append(staleBackends, mappedBackends(j.destMeta)...)
+	}
+
 	oldSrcHostMapping := j.Src.HostMapping
 	if j.Src.HostMapping == nil {
 		j.Src.HostMapping = make(map[string]string)
@@ -3645,6 +5119,9 @@ func (j *Job) UpdateHostMapping(srcHostMaps, destHostMaps map[string]string) err
 		j.Dest.HostMapping = oldDestHostMapping
 		return err
 	}
+	for _, backend := range staleBackends {
+		j.factory.RemoveBeRpc(backend)
+	}
 
 	log.Debugf("update job %s src host mapping %+v, dest host mapping: %+v", j.Name, srcHostMaps, destHostMaps)
 	return nil
@@ -3667,6 +5144,26 @@ func (j *Job) SkipBinlog(skipCommitSeq int64, skipBy string) error {
 	return nil
 }
 
+// StopAt pauses the job after the binlogs up to the commit seq or the source timestamp (in ms)
+// are synced. The stop point is cleared if both are zero.
+func (j *Job) StopAt(commitSeq, timestamp int64) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	savedExtra := j.Extra
+	j.Extra.StopAtCommitSeq = commitSeq
+	j.Extra.StopAtTimestamp = timestamp
+	j.Extra.StopReachedCommitSeq = 0
+	if err := j.persistJob(); err != nil {
+		j.Extra = savedExtra
+		return err
+	}
+	j.updateJobStatus()
+
+	log.Infof("stop job %s at commit seq %d, timestamp %d", j.Name, commitSeq, timestamp)
+	return nil
+}
+
 func isTxnCommitted(status *tstatus.TStatus) bool {
 	return isStatusContainsAny(status, "is already COMMITTED")
 }
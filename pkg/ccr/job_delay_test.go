@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"testing"
+	"time"
+
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+)
+
+func TestIsBinlogDelayed(t *testing.T) {
+	now := time.Now().UnixMilli()
+	withTimestamp := func(ts int64) *festruct.TBinlog {
+		binlog := festruct.NewTBinlog()
+		binlog.SetTimestamp(&ts)
+		return binlog
+	}
+
+	tests := []struct {
+		name         string
+		delaySeconds int64
+		binlog       *festruct.TBinlog
+		delayed      bool
+	}{
+		{"no delay", 0, withTimestamp(now), false},
+		{"no timestamp", 60, festruct.NewTBinlog(), false},
+		{"recent binlog", 60, withTimestamp(now - 10*1000), true},
+		{"future binlog", 60, withTimestamp(now + 10*1000), true},
+		{"old binlog", 60, withTimestamp(now - 120*1000), false},
+		{"exactly delayed", 60, withTimestamp(now - 61*1000), false},
+	}
+	for _, test := range tests {
+		j := &Job{Extra: JobExtra{ApplyDelaySeconds: test.delaySeconds}}
+		if delayed := j.isBinlogDelayed(test.binlog); delayed != test.delayed {
+			t.Errorf("%s: expect delayed %t, got %t", test.name, test.delayed, delayed)
+		}
+	}
+}
+
+func TestApplyDelayOnlyInTableSync(t *testing.T) {
+	j := &Job{SyncType: DBSync, Extra: JobExtra{ApplyDelaySeconds: 60}}
+	if err := j.validExtra(); err == nil {
+		t.Errorf("apply delay of db sync should be rejected")
+	}
+
+	j.SyncType = TableSync
+	j.Src.Table = "src"
+	if err := j.validExtra(); err != nil {
+		t.Errorf("apply delay of table sync should be valid, err: %v", err)
+	}
+}
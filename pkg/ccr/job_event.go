@@ -0,0 +1,172 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/storage"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	log "github.com/sirupsen/logrus"
+)
+
+type JobEventType string
+
+const (
+	JobEventCreated            JobEventType = "created"
+	JobEventDeleted            JobEventType = "deleted"
+	JobEventPaused             JobEventType = "paused"
+	JobEventResumed            JobEventType = "resumed"
+	JobEventFullSyncStarted    JobEventType = "full_sync_started"
+	JobEventPartialSyncStarted JobEventType = "partial_sync_started"
+	JobEventSnapshotCreated    JobEventType = "snapshot_created"
+	JobEventRestoreFinished    JobEventType = "restore_finished"
+	JobEventMetaErrorFallback  JobEventType = "meta_error_fallback"
+	JobEventBinlogSkipped      JobEventType = "binlog_skipped"
+	JobEventBinlogApproved     JobEventType = "binlog_approved"
+	JobEventBinlogRejected     JobEventType = "binlog_rejected"
+	JobEventUpdated            JobEventType = "updated"
+)
+
+var jobEventTypes = []JobEventType{
+	JobEventCreated, JobEventDeleted, JobEventPaused, JobEventResumed,
+	JobEventFullSyncStarted, JobEventPartialSyncStarted, JobEventSnapshotCreated, JobEventRestoreFinished,
+	JobEventMetaErrorFallback, JobEventBinlogSkipped, JobEventBinlogApproved, JobEventBinlogRejected, JobEventUpdated,
+}
+
+const JOB_EVENT_PURGE_DURATION = time.Hour
+
+var jobEventRetention time.Duration
+
+func init() {
+	flag.DurationVar(&jobEventRetention, "job_event_retention", 30*24*time.Hour,
+		"The retention of the job events, 0 means keep forever")
+}
+
+func ParseJobEventType(eventType string) (JobEventType, error) {
+	for _, t := range jobEventTypes {
+		if string(t) == eventType {
+			return t, nil
+		}
+	}
+	return "", xerror.Errorf(xerror.Normal, "unknown job event type: %s", eventType)
+}
+
+// JobEventDetail is the typed detail of the job event, stored as json.
+type JobEventDetail struct {
+	CommitSeq int64  `json:"commit_seq,omitempty"`
+	SyncId    int64  `json:"sync_id,omitempty"`
+	Table     string `json:"table,omitempty"`
+	Snapshot  string `json:"snapshot,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Operator  string `json:"operator,omitempty"`
+}
+
+type JobEvent struct {
+	Id      int64        `json:"id"`
+	Name    string       `json:"name"`
+	Type    JobEventType `json:"type"`
+	Time    int64        `json:"time"` // unix time in milliseconds
+	Message string       `json:"message"`
+	JobEventDetail
+}
+
+func newJobEventFromStorage(event *storage.JobEvent) *JobEvent {
+	jobEvent := &JobEvent{
+		Id:      event.Id,
+		Name:    event.JobName,
+		Type:    JobEventType(event.EventType),
+		Time:    event.Timestamp,
+		Message: event.Message,
+	}
+	if event.Detail != "" {
+		if err := json.Unmarshal([]byte(event.Detail), &jobEvent.JobEventDetail); err != nil {
+			log.Warnf("unmarshal detail of job event %d failed, detail: %s, err: %v", event.Id, event.Detail, err)
+		}
+	}
+	return jobEvent
+}
+
+// Append the event of the job, the failure is logged and ignored, the events should never block the sync.
+func recordJobEvent(db storage.DB, jobName string, eventType JobEventType, detail JobEventDetail, format string, args ...any) {
+	detailBytes, err := json.Marshal(detail)
+	if err != nil {
+		log.Warnf("marshal detail of job %s event %s failed: %v", jobName, eventType, err)
+		return
+	}
+
+	event := &storage.JobEvent{
+		JobName:   jobName,
+		EventType: string(eventType),
+		Timestamp: time.Now().UnixMilli(),
+		Message:   fmt.Sprintf(format, args...),
+		Detail:    string(detailBytes),
+	}
+	if err := db.AddJobEvent(event); err != nil {
+		log.Warnf("record job %s event %s failed: %+v", jobName, eventType, err)
+	}
+}
+
+func (j *Job) recordEvent(eventType JobEventType, detail JobEventDetail, format string, args ...any) {
+	recordJobEvent(j.db, j.Name, eventType, detail, format, args...)
+}
+
+// The reason of the new snapshot, by the binlog being handled or the skip binlog option.
+func (j *Job) snapshotReason() string {
+	if j.Extra.SkipBinlog && j.Extra.SkipBy == SkipByFullSync {
+		return "skip binlog by fullsync"
+	}
+	if binlogType := j.rawStatus.handlingBinlogType.Load(); binlogType != nil {
+		return fmt.Sprintf("binlog %s", *binlogType)
+	}
+	return ""
+}
+
+// Get the events of the job, the latest first.
+func GetJobEvents(db storage.DB, jobName string, filter *storage.JobEventFilter) ([]*JobEvent, error) {
+	for _, eventType := range filter.EventTypes {
+		if _, err := ParseJobEventType(eventType); err != nil {
+			return nil, err
+		}
+	}
+
+	events, err := db.GetJobEvents(jobName, filter)
+	if err != nil {
+		return nil, err
+	}
+	jobEvents := make([]*JobEvent, 0, len(events))
+	for _, event := range events {
+		jobEvents = append(jobEvents, newJobEventFromStorage(event))
+	}
+	return jobEvents, nil
+}
+
+func (jm *JobManager) purgeJobEvents() {
+	if jobEventRetention <= 0 {
+		return
+	}
+
+	expired := time.Now().Add(-jobEventRetention).UnixMilli()
+	if num, err := jm.db.RemoveJobEventsBefore(expired); err != nil {
+		log.Warnf("purge job events failed: %+v", err)
+	} else if num > 0 {
+		log.Infof("purge %d job events before %d", num, expired)
+	}
+}
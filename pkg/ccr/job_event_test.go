@@ -0,0 +1,67 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/storage"
+	"github.com/selectdb/ccr_syncer/pkg/test_util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDecideHeldBinlogEvent(t *testing.T) {
+	for _, decision := range []string{BinlogApproved, BinlogRejected} {
+		ctrl := gomock.NewController(t)
+		db := test_util.NewMockDB(ctrl)
+		j := &Job{
+			Name:  "held",
+			State: JobPaused,
+			db:    db,
+			progress: &JobProgress{JobName: "held", db: db,
+				HeldBinlog: &HeldBinlog{CommitSeq: 100, Type: "DROP_TABLE"}},
+		}
+
+		var event *storage.JobEvent
+		db.EXPECT().UpdateProgress(j.Name, gomock.Any()).Return(nil)
+		db.EXPECT().UpdateJob(j.Name, gomock.Any()).Return(nil)
+		db.EXPECT().AddJobEvent(gomock.Any()).DoAndReturn(func(e *storage.JobEvent) error {
+			event = e
+			return nil
+		})
+		if err := j.DecideHeldBinlog(100, decision, "dba"); err != nil {
+			t.Fatalf("%s the held binlog failed: %v", decision, err)
+		}
+
+		expectType := JobEventBinlogApproved
+		if decision == BinlogRejected {
+			expectType = JobEventBinlogRejected
+		}
+		var detail JobEventDetail
+		if err := json.Unmarshal([]byte(event.Detail), &detail); err != nil {
+			t.Fatalf("unmarshal the event detail failed: %v", err)
+		}
+		if event.EventType != string(expectType) || detail.CommitSeq != 100 || detail.Operator != "dba" {
+			t.Errorf("unexpected %s event: %+v", decision, event)
+		}
+		if j.State != JobRunning {
+			t.Errorf("the job should be resumed after the binlog is %s", decision)
+		}
+		ctrl.Finish()
+	}
+}
@@ -0,0 +1,107 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+)
+
+func TestBidirectionalRequiresWriteTables(t *testing.T) {
+	j := &Job{SyncType: DBSync, Extra: JobExtra{Bidirectional: true}}
+	if err := j.validExtra(); err == nil {
+		t.Errorf("bidirectional without write tables should be rejected")
+	}
+
+	j.Extra.WriteTables = &TableOwnership{Prefixes: []string{"tenant_a_"}}
+	if err := j.validExtra(); err != nil {
+		t.Errorf("bidirectional with write tables should be valid, err: %v", err)
+	}
+}
+
+func TestBidirectionalLabel(t *testing.T) {
+	j := &Job{SyncType: DBSync, Extra: JobExtra{Bidirectional: true}}
+	if label := j.newLabel(10); !strings.HasPrefix(label, BidirectionalLabelPrefix) || !IsBidirectionalLabel(label) {
+		t.Errorf("expect the bidirectional label, but got %s", label)
+	}
+
+	j.Extra.Bidirectional = false
+	if label := j.newLabel(10); IsBidirectionalLabel(label) {
+		t.Errorf("expect the normal label, but got %s", label)
+	}
+}
+
+func TestHandleUpsertSkipBidirectionalLabel(t *testing.T) {
+	// The metas are not set, the upsert must be skipped before reading the tables.
+	j := &Job{
+		SyncType: DBSync,
+		Extra:    JobExtra{Bidirectional: true},
+		progress: &JobProgress{SubSyncState: Done},
+	}
+	data := `{"commitSeq": 10, "label": "ccrb-1a2b:DBSync:1:2:9", "tableRecords": {"100": {}}}`
+	if err := j.handleUpsert(&festruct.TBinlog{Data: &data}); err != nil {
+		t.Errorf("expect the upsert of the opposite job is skipped, but got %v", err)
+	}
+}
+
+type desyncSpecer struct {
+	base.Specer
+	tables []string
+}
+
+func (s *desyncSpecer) DesyncTables(tables ...string) error {
+	s.tables = tables
+	return nil
+}
+
+type desyncMetaer struct {
+	Metaer
+	tables map[int64]*TableMeta
+}
+
+func (m *desyncMetaer) GetTables() (map[int64]*TableMeta, error) {
+	return m.tables, nil
+}
+
+func TestDesyncDBSelectedTables(t *testing.T) {
+	specer := &desyncSpecer{}
+	j := &Job{
+		SyncType: DBSync,
+		Extra: JobExtra{
+			TableFilter: &TableFilter{ExcludeTables: []string{"logs"}},
+			TableRename: &TableRenameRule{Prefix: "east_"},
+			OwnedTables: &TableOwnership{Prefixes: []string{"east_"}},
+		},
+		IDest: specer,
+		destMeta: &desyncMetaer{tables: map[int64]*TableMeta{
+			11: {Name: "east_orders"},
+			12: {Name: "east_logs"},
+			13: {Name: "west_orders"},
+		}},
+		progress: &JobProgress{TableNameMapping: map[int64]string{1: "orders", 2: "logs"}},
+	}
+
+	if err := j.desyncDB(); err != nil {
+		t.Fatalf("desync db failed: %v", err)
+	}
+	if len(specer.tables) != 1 || specer.tables[0] != "east_orders" {
+		t.Errorf("expect only east_orders is desynced, but got %v", specer.tables)
+	}
+}
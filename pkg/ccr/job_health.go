@@ -0,0 +1,124 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+const connectivityCheckTimeout = 3 * time.Second
+
+// JobConnectivity is the connectivity of the src and dest frontends of a job.
+type JobConnectivity struct {
+	Name      string `json:"name"`
+	Src       bool   `json:"src"`
+	SrcError  string `json:"src_error,omitempty"`
+	Dest      bool   `json:"dest"`
+	DestError string `json:"dest_error,omitempty"`
+	// The unix time of the first failure of persisting the progress, zero if it is persisted.
+	PersistFailingSince int64 `json:"persist_failing_since,omitempty"`
+}
+
+func pingSpec(spec *base.Spec) error {
+	db, err := spec.Connect()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectivityCheckTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		return xerror.Wrapf(err, xerror.FE, "ping %s:%s failed", spec.Host, spec.Port)
+	}
+	return nil
+}
+
+// Copy the specs for the connectivity check, it must be called after the specs are changed. The
+// specs are not read directly, since they might be updated by Job.Update concurrently, and the job
+// lock is held during the whole sync step.
+func (j *Job) storeConnectivitySpecs() {
+	src, dest := j.Src, j.Dest
+	j.rawStatus.srcSpec.Store(&src)
+	j.rawStatus.destSpec.Store(&dest)
+}
+
+func (j *Job) checkConnectivity() *JobConnectivity {
+	connectivity := &JobConnectivity{
+		Name:                j.Name,
+		PersistFailingSince: persistFailingSince(j.Name),
+	}
+
+	src, dest := j.rawStatus.srcSpec.Load(), j.rawStatus.destSpec.Load()
+	if src == nil || dest == nil {
+		connectivity.SrcError = "the job is not initialized"
+		connectivity.DestError = connectivity.SrcError
+		return connectivity
+	}
+	if err := pingSpec(src); err != nil {
+		connectivity.SrcError = err.Error()
+	} else {
+		connectivity.Src = true
+	}
+	if err := pingSpec(dest); err != nil {
+		connectivity.DestError = err.Error()
+	} else {
+		connectivity.Dest = true
+	}
+	return connectivity
+}
+
+// Check the connectivity of the src and dest frontends of all jobs, in parallel.
+func (jm *JobManager) CheckConnectivity() []*JobConnectivity {
+	jm.lock.RLock()
+	jobs := make([]*Job, 0, len(jm.jobs))
+	for _, job := range jm.jobs {
+		jobs = append(jobs, job)
+	}
+	jm.lock.RUnlock()
+
+	results := make([]*JobConnectivity, len(jobs))
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		i, job := i, job
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = job.checkConnectivity()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// The names of the jobs failing to persist the progress since before the time.
+func PersistFailingJobs(before time.Time) []string {
+	var jobs []string
+	persistFailures.Range(func(key, value any) bool {
+		if value.(int64) <= before.Unix() {
+			jobs = append(jobs, key.(string))
+		}
+		return true
+	})
+	sort.Strings(jobs)
+	return jobs
+}
@@ -0,0 +1,42 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPersistFailingJobs(t *testing.T) {
+	now := time.Now()
+	persistFailures.Store("job_b", now.Add(-10*time.Minute).Unix())
+	persistFailures.Store("job_a", now.Add(-6*time.Minute).Unix())
+	persistFailures.Store("job_c", now.Unix())
+	defer func() {
+		for _, name := range []string{"job_a", "job_b", "job_c"} {
+			persistFailures.Delete(name)
+		}
+	}()
+
+	if jobs := PersistFailingJobs(now.Add(-5 * time.Minute)); !reflect.DeepEqual(jobs, []string{"job_a", "job_b"}) {
+		t.Errorf("unexpected persist failing jobs: %v", jobs)
+	}
+	if jobs := PersistFailingJobs(now.Add(-time.Hour)); len(jobs) != 0 {
+		t.Errorf("unexpected persist failing jobs: %v", jobs)
+	}
+}
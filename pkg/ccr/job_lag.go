@@ -0,0 +1,145 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+// JobLag is the lag of the job, both in the commit seqs and in the wall-clock time. The
+// timestamps are the source timestamps of the binlogs, in milliseconds.
+type JobLag struct {
+	Name string `json:"name"`
+	// The number of the unapplied binlogs.
+	Lag int64 `json:"lag"`
+	// The age of the oldest unapplied binlog, zero if all binlogs are applied.
+	LagSeconds               int64 `json:"lag_seconds"`
+	OldestUnappliedTimestamp int64 `json:"oldest_unapplied_timestamp,omitempty"`
+	// The age of the last applied upsert, the staleness of the dest data.
+	LastUpsertTimestamp int64 `json:"last_upsert_timestamp,omitempty"`
+	LastUpsertSeconds   int64 `json:"last_upsert_seconds,omitempty"`
+
+	// The lag of each table, only for the db sync.
+	Tables []*TableLag `json:"tables,omitempty"`
+
+	// The error of getting the lag, only in the batch result.
+	Error string `json:"error,omitempty"`
+}
+
+// TableLag is the lag of a table. The binlogs are read by the database, so the oldest
+// unapplied binlog might belong to another table, the lag is an upper bound.
+type TableLag struct {
+	Table                    string `json:"table"`
+	CommitSeq                int64  `json:"commit_seq"`
+	LagSeconds               int64  `json:"lag_seconds"`
+	OldestUnappliedTimestamp int64  `json:"oldest_unapplied_timestamp,omitempty"`
+}
+
+// Get the lag of the job, by the persisted job and progress. Only the lag in commit seqs calls
+// the frontend, the timestamps are derived from the binlogs seen by the sync loop of the job.
+func (jm *JobManager) GetJobLag(job *Job, progress *JobProgress) (*JobLag, error) {
+	jm.lock.RLock()
+	running, ok := jm.jobs[job.Name]
+	jm.lock.RUnlock()
+	if !ok {
+		return nil, xerror.Errorf(xerror.Normal, "job not exist: %s", job.Name)
+	}
+
+	return getJobLag(jm.factory, job, progress, running.oldestUnappliedTimestamp)
+}
+
+func getJobLag(factory *Factory, job *Job, progress *JobProgress, oldestUnapplied func(lag int64) int64) (*JobLag, error) {
+	src := &job.Src
+	feRpc, err := factory.NewFeRpc(src)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := feRpc.GetBinlogLag(src, progress.CommitSeq)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	lag := &JobLag{
+		Name:                     job.Name,
+		Lag:                      resp.GetLag(),
+		OldestUnappliedTimestamp: oldestUnapplied(resp.GetLag()),
+		LastUpsertTimestamp:      progress.LastUpsertTimestamp,
+	}
+	lag.LagSeconds = lagSeconds(now, lag.OldestUnappliedTimestamp)
+	if lag.LastUpsertTimestamp > 0 {
+		lag.LastUpsertSeconds = lagSeconds(now, lag.LastUpsertTimestamp)
+	}
+
+	if job.SyncType != DBSync {
+		return lag, nil
+	}
+
+	// The tables in TableCommitSeqMap are ahead of the job, during the DBTablesIncrementalSync.
+	tableIds := make(map[int64]bool)
+	for tableId := range progress.TableNameMapping {
+		tableIds[tableId] = true
+	}
+	for tableId := range progress.TableCommitSeqMap {
+		tableIds[tableId] = true
+	}
+	for tableId := range tableIds {
+		commitSeq := progress.CommitSeq
+		if tableCommitSeq, ok := progress.TableCommitSeqMap[tableId]; ok && tableCommitSeq > commitSeq {
+			commitSeq = tableCommitSeq
+		}
+		table, ok := progress.TableNameMapping[tableId]
+		if !ok {
+			table = strconv.FormatInt(tableId, 10)
+		}
+
+		lag.Tables = append(lag.Tables, &TableLag{
+			Table:                    table,
+			CommitSeq:                commitSeq,
+			LagSeconds:               lag.LagSeconds,
+			OldestUnappliedTimestamp: lag.OldestUnappliedTimestamp,
+		})
+	}
+	sort.Slice(lag.Tables, func(i, k int) bool { return lag.Tables[i].Table < lag.Tables[k].Table })
+	return lag, nil
+}
+
+// Get the source timestamp of the oldest unapplied binlog, zero if there is no one. Once all binlogs
+// are synced, the later binlogs are produced after that time, so it is the upper bound of their
+// timestamps if the lag in commit seqs is not zero.
+func (j *Job) oldestUnappliedTimestamp(lag int64) int64 {
+	if timestamp := atomic.LoadInt64(&j.rawStatus.pendingTimestamp); timestamp > 0 {
+		return timestamp
+	}
+	if lag > 0 {
+		return atomic.LoadInt64(&j.rawStatus.caughtUpAt)
+	}
+	return 0
+}
+
+func lagSeconds(now, timestamp int64) int64 {
+	if timestamp <= 0 || now <= timestamp {
+		return 0
+	}
+	return (now - timestamp) / 1000
+}
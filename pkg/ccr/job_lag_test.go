@@ -0,0 +1,98 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/rpc"
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+)
+
+// lagFeRpc returns the lag in commit seqs, the other methods are not implemented.
+type lagFeRpc struct {
+	rpc.IFeRpc
+	lag int64
+}
+
+func (r *lagFeRpc) GetBinlogLag(_ *base.Spec, _ int64) (*festruct.TGetBinlogLagResult_, error) {
+	return &festruct.TGetBinlogLagResult_{Lag: &r.lag}, nil
+}
+
+type lagRpcFactory struct {
+	rpc.IRpcFactory
+	feRpc rpc.IFeRpc
+}
+
+func (f *lagRpcFactory) NewFeRpc(_ *base.Spec) (rpc.IFeRpc, error) {
+	return f.feRpc, nil
+}
+
+func TestGetJobLag(t *testing.T) {
+	now := time.Now().UnixMilli()
+	feRpc := &lagFeRpc{lag: 5}
+	running := &Job{}
+	running.rawStatus.pendingTimestamp = now - 60_000
+
+	job := &Job{Name: "ccr_test", SyncType: DBSync}
+	progress := &JobProgress{
+		CommitSeq:           100,
+		TableNameMapping:    map[int64]string{1: "tbl_a", 2: "tbl_b"},
+		TableCommitSeqMap:   map[int64]int64{2: 120},
+		LastUpsertTimestamp: now - 90_000,
+	}
+	factory := NewFactory(&lagRpcFactory{feRpc: feRpc}, nil, nil, nil)
+	jobLag, err := getJobLag(factory, job, progress, running.oldestUnappliedTimestamp)
+	if err != nil {
+		t.Fatalf("get job lag failed: %v", err)
+	}
+
+	if jobLag.Lag != 5 || jobLag.LagSeconds < 60 || jobLag.LastUpsertSeconds < 90 {
+		t.Errorf("unexpected lag: %+v", jobLag)
+	}
+	if len(jobLag.Tables) != 2 {
+		t.Fatalf("unexpected tables: %v", jobLag.Tables)
+	}
+	if tbl := jobLag.Tables[0]; tbl.Table != "tbl_a" || tbl.CommitSeq != 100 || tbl.LagSeconds < 60 {
+		t.Errorf("unexpected lag of tbl_a: %+v", tbl)
+	}
+	if tbl := jobLag.Tables[1]; tbl.Table != "tbl_b" || tbl.CommitSeq != 120 || tbl.LagSeconds < 60 {
+		t.Errorf("unexpected lag of tbl_b: %+v", tbl)
+	}
+}
+
+func TestOldestUnappliedTimestamp(t *testing.T) {
+	job := &Job{}
+	if timestamp := job.oldestUnappliedTimestamp(5); timestamp != 0 {
+		t.Errorf("expect no timestamp before the first sync, but got %d", timestamp)
+	}
+
+	job.rawStatus.caughtUpAt = 1000
+	if timestamp := job.oldestUnappliedTimestamp(0); timestamp != 0 {
+		t.Errorf("expect no timestamp if all binlogs are synced, but got %d", timestamp)
+	}
+	if timestamp := job.oldestUnappliedTimestamp(5); timestamp != 1000 {
+		t.Errorf("expect the caught up time as the upper bound, but got %d", timestamp)
+	}
+
+	job.rawStatus.pendingTimestamp = 2000
+	if timestamp := job.oldestUnappliedTimestamp(5); timestamp != 2000 {
+		t.Errorf("expect the pending timestamp, but got %d", timestamp)
+	}
+}
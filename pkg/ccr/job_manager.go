@@ -20,7 +20,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
 	"github.com/selectdb/ccr_syncer/pkg/storage"
 	"github.com/selectdb/ccr_syncer/pkg/xerror"
 	"github.com/selectdb/ccr_syncer/pkg/xmetrics"
@@ -66,12 +68,17 @@ func (jm *JobManager) AddJob(job *Job) error {
 		return xerror.XWrapf(errJobExist, "job: %s", job.Name)
 	}
 
-	// Step 2: check job first run, mostly for dest/src fe db/table info
+	// Step 2: check the dest tables and the fanout group with other jobs
+	if err := jm.checkJobConflict(job); err != nil {
+		return err
+	}
+
+	// Step 3: check job first run, mostly for dest/src fe db/table info
 	if err := job.FirstRun(); err != nil {
 		return err
 	}
 
-	// Step 3: add job info to db
+	// Step 4: add job info to db
 	data, err := json.Marshal(job)
 	if err != nil {
 		return xerror.Wrap(err, xerror.Normal, "marshal job error")
@@ -79,17 +86,98 @@ func (jm *JobManager) AddJob(job *Job) error {
 	if err := jm.db.AddJob(job.Name, string(data), jm.hostInfo); err != nil {
 		return err
 	}
+	job.recordEvent(JobEventCreated, JobEventDetail{}, "job created by syncer %s, sync type: %s", jm.hostInfo, job.SyncType)
 
-	// Step 4: run job
+	// Step 5: run job
 	jm.jobs[job.Name] = job
 	jm.runJob(job)
 
-	// Step 5: add metrics
+	// Step 6: add metrics
 	xmetrics.AddNewJob(job.Name)
+	xmetrics.SetJobNum(len(jm.jobs))
 
 	return nil
 }
 
+// Several jobs are able to sync into the same dest database, only if they own disjoint dest tables.
+// And the jobs in the same fanout group must sync the same source database or table, the opposite
+// bidirectional jobs must write disjoint tables.
+//
+// The jobs of all syncers are read from the db, since the job might be run by other syncers.
+func (jm *JobManager) checkJobConflict(job *Job) error {
+	jobInfos, err := jm.db.GetAllJobInfos()
+	if err != nil {
+		return err
+	}
+
+	for jobName, jobInfo := range jobInfos {
+		if jobName == job.Name {
+			continue
+		}
+
+		// A broken job should not block creating the other jobs.
+		var other Job
+		if err := json.Unmarshal([]byte(jobInfo), &other); err != nil {
+			log.Warnf("skip checking the conflict with job %s, unmarshal failed: %+v", jobName, err)
+			continue
+		}
+		if job.Extra.FanoutGroup != "" && job.Extra.FanoutGroup == other.Extra.FanoutGroup {
+			if job.SyncType != other.SyncType || !isSameDatabase(&job.Src, &other.Src) || job.Src.Table != other.Src.Table {
+				return xerror.Errorf(xerror.Normal, "the src of fanout group %s is not same as job %s",
+					job.Extra.FanoutGroup, jobName)
+			}
+		}
+		if isSameDatabase(&job.Src, &other.Dest) && isSameDatabase(&job.Dest, &other.Src) {
+			if !job.Extra.Bidirectional || !other.Extra.Bidirectional {
+				return xerror.Errorf(xerror.Normal, "job %s replicates in the opposite direction, both jobs must be bidirectional",
+					jobName)
+			}
+			if conflict := job.Extra.WriteTables.Conflict(other.Extra.WriteTables); conflict != "" {
+				return xerror.Errorf(xerror.Normal, "the write tables conflict with the opposite job %s: %s",
+					jobName, conflict)
+			}
+		}
+		if !isSameDatabase(&job.Dest, &other.Dest) {
+			continue
+		}
+		if conflict := job.destOwnership().Conflict(other.destOwnership()); conflict != "" {
+			return xerror.Errorf(xerror.Normal, "dest database %s conflicts with job %s: %s",
+				job.Dest.Database, jobName, conflict)
+		}
+	}
+	return nil
+}
+
+func isSameDatabase(a, b *base.Spec) bool {
+	if a.Database != b.Database {
+		return false
+	}
+
+	endpoints := make(map[string]struct{})
+	endpoints[fmt.Sprintf("%s:%s", a.Host, a.Port)] = struct{}{}
+	for _, frontend := range a.Frontends {
+		endpoints[fmt.Sprintf("%s:%s", frontend.Host, frontend.Port)] = struct{}{}
+	}
+
+	if _, ok := endpoints[fmt.Sprintf("%s:%s", b.Host, b.Port)]; ok {
+		return true
+	}
+	for _, frontend := range b.Frontends {
+		if _, ok := endpoints[fmt.Sprintf("%s:%s", frontend.Host, frontend.Port)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (jm *JobManager) hasJob(jobName string) bool {
+	jm.lock.RLock()
+	defer jm.lock.RUnlock()
+
+	_, ok := jm.jobs[jobName]
+	return ok
+}
+
 func (jm *JobManager) Recover(jobNames []string) error {
 	log.Info("job manager recover")
 
@@ -117,6 +205,7 @@ func (jm *JobManager) Recover(jobNames []string) error {
 		jm.jobs[job.Name] = job
 		jm.runJob(job)
 	}
+	xmetrics.SetJobNum(len(jm.jobs))
 	return nil
 }
 
@@ -137,6 +226,9 @@ func (jm *JobManager) RemoveJob(name string) error {
 	job.Delete()
 	if err := jm.db.RemoveJob(name); err == nil {
 		delete(jm.jobs, name)
+		xmetrics.SetJobNum(len(jm.jobs))
+		xmetrics.RemoveJob(name)
+		job.recordEvent(JobEventDeleted, JobEventDetail{}, "job deleted")
 		log.Infof("job [%s] has been successfully deleted, but it needs to wait until an isochronous point before it will completely STOP", name)
 		return nil
 	} else {
@@ -153,8 +245,17 @@ func (jm *JobManager) Start() error {
 	}
 	jm.lock.RUnlock()
 
-	<-jm.stop
-	return nil
+	jm.purgeJobEvents()
+	ticker := time.NewTicker(JOB_EVENT_PURGE_DURATION)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-jm.stop:
+			return nil
+		case <-ticker.C:
+			jm.purgeJobEvents()
+		}
+	}
 }
 
 // stop job manager
@@ -210,6 +311,29 @@ func (jm *JobManager) Resume(jobName string) error {
 	})
 }
 
+func (jm *JobManager) StopAt(jobName string, commitSeq, timestamp int64) error {
+	return jm.dealJob(jobName, func(job *Job) error {
+		return job.StopAt(commitSeq, timestamp)
+	})
+}
+
+func (jm *JobManager) DecideHeldBinlog(jobName string, commitSeq int64, decision string, operator string) error {
+	return jm.dealJob(jobName, func(job *Job) error {
+		return job.DecideHeldBinlog(commitSeq, decision, operator)
+	})
+}
+
+func (jm *JobManager) GetPendingBinlog(jobName string) (*HeldBinlog, any, error) {
+	jm.lock.RLock()
+	defer jm.lock.RUnlock()
+
+	if job, ok := jm.jobs[jobName]; ok {
+		return job.PendingBinlog()
+	} else {
+		return nil, nil, xerror.Errorf(xerror.Normal, "job not exist: %s", jobName)
+	}
+}
+
 func (jm *JobManager) GetJobStatus(jobName string) (*JobStatus, error) {
 	jm.lock.RLock()
 	defer jm.lock.RUnlock()
@@ -243,6 +367,20 @@ func (jm *JobManager) ListJobs() []*JobStatus {
 	return jobs
 }
 
+// Update the lag metrics of all jobs, it calls the src frontends, so it should be called periodically.
+func (jm *JobManager) UpdateLagMetrics() {
+	jm.lock.RLock()
+	jobs := make([]*Job, 0, len(jm.jobs))
+	for _, job := range jm.jobs {
+		jobs = append(jobs, job)
+	}
+	jm.lock.RUnlock()
+
+	for _, job := range jobs {
+		job.updateLagMetrics()
+	}
+}
+
 func (jm *JobManager) UpdateHostMapping(jobName string, srcHostMapping, destHostMapping map[string]string) error {
 	jm.lock.Lock()
 	defer jm.lock.Unlock()
@@ -254,6 +392,17 @@ func (jm *JobManager) UpdateHostMapping(jobName string, srcHostMapping, destHost
 	}
 }
 
+func (jm *JobManager) UpdateJob(jobName string, update *JobUpdate) error {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+
+	if job, ok := jm.jobs[jobName]; ok {
+		return job.Update(update)
+	} else {
+		return xerror.Errorf(xerror.Normal, "job not exist: %s", jobName)
+	}
+}
+
 func (jm *JobManager) SkipBinlog(jobName string, skipCommitSeq int64, skipBy string) error {
 	jm.lock.Lock()
 	defer jm.lock.Unlock()
@@ -19,6 +19,7 @@ package ccr
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/selectdb/ccr_syncer/pkg/storage"
@@ -151,6 +152,27 @@ func (s SubSyncState) String() string {
 	}
 }
 
+const (
+	BinlogApproved = "approved"
+	BinlogRejected = "rejected"
+
+	// The max number of the binlog decisions kept in the progress.
+	maxBinlogDecisions = 32
+)
+
+// The binlog held by the ddl policy, waiting for approval.
+type HeldBinlog struct {
+	CommitSeq int64  `json:"commit_seq"`
+	Type      string `json:"type"`
+	Data      string `json:"data"` // the data of the binlog wrapped by the barrier, if any
+	HeldAt    int64  `json:"held_at"`
+
+	// The decision of the held binlog, approved or rejected.
+	Decision  string `json:"decision,omitempty"`
+	Operator  string `json:"operator,omitempty"`
+	DecidedAt int64  `json:"decided_at,omitempty"`
+}
+
 type JobPartialSyncData struct {
 	TableId      int64    `json:"table_id"`
 	Table        string   `json:"table"`
@@ -188,12 +210,24 @@ type JobProgress struct {
 	// The shadow indexes of the pending schema changes
 	ShadowIndexes map[int64]int64 `json:"shadow_index_map,omitempty"`
 
+	// The row filtered source tables => the partitions changed by the applied upserts, waiting to be
+	// reloaded, the nil partitions means the whole table.
+	FilteredTablesToReload map[string][]string `json:"filtered_tables_to_reload,omitempty"`
+
+	// The binlog held by the ddl policy, the job is paused until it is approved or rejected.
+	HeldBinlog *HeldBinlog `json:"held_binlog,omitempty"`
+	// The recent decisions of the held binlogs.
+	BinlogDecisions []*HeldBinlog `json:"binlog_decisions,omitempty"`
+
 	// Some fields to save the unix epoch time of the key timepoint.
 	CreatedAt              int64 `json:"created_at,omitempty"`
 	FullSyncStartAt        int64 `json:"full_sync_start_at,omitempty"`
 	PartialSyncStartAt     int64 `json:"partial_sync_start_at,omitempty"`
 	IncrementalSyncStartAt int64 `json:"incremental_sync_start_at,omitempty"`
 	IngestBinlogAt         int64 `json:"ingest_binlog_at,omitempty"`
+
+	// The source timestamp of the last applied upsert binlog, in ms.
+	LastUpsertTimestamp int64 `json:"last_upsert_timestamp,omitempty"`
 }
 
 func (j *JobProgress) String() string {
@@ -329,6 +363,12 @@ func (j *JobProgress) CommitNextSubWithPersist(commitSeq int64, subSyncState Sub
 //
 // The PrevCommitSeq is set to commitSeq, if the sub sync state is done.
 func (j *JobProgress) NextWithPersist(commitSeq int64, syncState SyncState, subSyncState SubSyncState, persistData string) {
+	isFullSync := j.SyncState == TableFullSync || j.SyncState == DBFullSync
+	if isFullSync && subSyncState == Done && j.FullSyncStartAt > 0 &&
+		(syncState == TableIncrementalSync || syncState == DBTablesIncrementalSync || syncState == DBIncrementalSync) {
+		xmetrics.ObserveFullSync(j.JobName, time.Since(time.Unix(j.FullSyncStartAt, 0)))
+	}
+
 	if subSyncState == BeginCreateSnapshot && (syncState == TableFullSync || syncState == DBFullSync) {
 		j.FullSyncStartAt = time.Now().Unix()
 		j.IncrementalSyncStartAt = 0
@@ -380,6 +420,17 @@ func (j *JobProgress) Rollback() {
 	j.Persist()
 }
 
+// The jobs failing to persist the progress, job name -> the unix time of the first failure. The
+// job is blocked until the progress is persisted.
+var persistFailures sync.Map
+
+func persistFailingSince(jobName string) int64 {
+	if since, ok := persistFailures.Load(jobName); ok {
+		return since.(int64)
+	}
+	return 0
+}
+
 // write progress to db, busy loop until success
 // TODO: add timeout check
 func (j *JobProgress) Persist() {
@@ -399,12 +450,14 @@ func (j *JobProgress) Persist() {
 		err = j.db.UpdateProgress(j.JobName, string(jsonBytes))
 		if err != nil {
 			log.Errorf("update job progress failed, error: %+v", err)
+			persistFailures.LoadOrStore(j.JobName, time.Now().Unix())
 			time.Sleep(UPDATE_JOB_PROGRESS_DURATION)
 			continue
 		}
 
 		break
 	}
+	persistFailures.Delete(j.JobName)
 
 	log.Tracef("update job progress done, state: %s, subState: %s, commitSeq: %d, prevCommitSeq: %d",
 		j.SyncState, j.SubSyncState, j.CommitSeq, j.PrevCommitSeq)
@@ -0,0 +1,40 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+func TestJobStatusError(t *testing.T) {
+	job := &Job{Name: "ccr_test"}
+	job.recordError(xerror.Errorf(xerror.RPC, "connection refused"), false)
+	job.recordError(xerror.Panicf(xerror.Meta, "unknown table"), true)
+
+	status := job.Status()
+	if status.ConsecutiveFailures != 2 || !status.Panicked {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if status.LastError == nil || status.LastError.Category != "meta" || !status.LastError.Panic {
+		t.Errorf("unexpected last error: %+v", status.LastError)
+	}
+	if status.LastError.Message != "[meta] unknown table" {
+		t.Errorf("unexpected message: %s", status.LastError.Message)
+	}
+}
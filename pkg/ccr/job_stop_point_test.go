@@ -0,0 +1,111 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"testing"
+
+	festruct "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/frontendservice"
+	"github.com/selectdb/ccr_syncer/pkg/storage"
+	"github.com/selectdb/ccr_syncer/pkg/test_util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIsBeyondStopPoint(t *testing.T) {
+	newBinlog := func(commitSeq, ts int64) *festruct.TBinlog {
+		binlog := festruct.NewTBinlog()
+		binlog.SetCommitSeq(&commitSeq)
+		binlog.SetTimestamp(&ts)
+		return binlog
+	}
+
+	tests := []struct {
+		extra  JobExtra
+		binlog *festruct.TBinlog
+		beyond bool
+	}{
+		{JobExtra{}, newBinlog(1000, 1000), false},
+		{JobExtra{StopAtCommitSeq: 100}, newBinlog(100, 1000), false},
+		{JobExtra{StopAtCommitSeq: 100}, newBinlog(101, 1000), true},
+		{JobExtra{StopAtTimestamp: 1000}, newBinlog(101, 1000), false},
+		{JobExtra{StopAtTimestamp: 1000}, newBinlog(101, 1001), true},
+		{JobExtra{StopAtCommitSeq: 200, StopAtTimestamp: 1000}, newBinlog(101, 1001), true},
+		// the stop point is reached, the job is resumed
+		{JobExtra{StopAtCommitSeq: 100, StopReachedCommitSeq: 100}, newBinlog(101, 1000), false},
+	}
+	for i, test := range tests {
+		j := &Job{Extra: test.extra}
+		if beyond := j.isBeyondStopPoint(test.binlog); beyond != test.beyond {
+			t.Errorf("test %d: expect beyond %t, got %t", i, test.beyond, beyond)
+		}
+	}
+}
+
+func TestCheckStopPointAfterRestore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := test_util.NewMockDB(ctrl)
+	j := &Job{
+		Name:     "stop_point",
+		State:    JobRunning,
+		Extra:    JobExtra{StopAtCommitSeq: 100},
+		db:       db,
+		progress: &JobProgress{CommitSeq: 90},
+	}
+
+	// the restored snapshot is before the stop point
+	if err := j.checkStopPointAfterRestore(90); err != nil {
+		t.Fatalf("check stop point failed: %v", err)
+	}
+	if j.State != JobRunning {
+		t.Fatalf("the job should not be paused before the stop point")
+	}
+
+	db.EXPECT().UpdateJob(j.Name, gomock.Any()).Return(nil)
+	db.EXPECT().AddJobEvent(gomock.Any()).DoAndReturn(func(event *storage.JobEvent) error {
+		if event.EventType != string(JobEventPaused) {
+			t.Errorf("expect the paused event, got %s", event.EventType)
+		}
+		return nil
+	})
+	j.progress.CommitSeq = 120
+	if err := j.checkStopPointAfterRestore(120); err != nil {
+		t.Fatalf("check stop point failed: %v", err)
+	}
+	if j.State != JobPaused || j.Extra.StopReachedCommitSeq != 120 {
+		t.Errorf("expect the job paused at 120, state: %s, reached: %d", j.State, j.Extra.StopReachedCommitSeq)
+	}
+
+	// the stop point is reached, the later restores are not checked
+	if err := j.checkStopPointAfterRestore(200); err != nil {
+		t.Fatalf("check stop point failed: %v", err)
+	}
+
+	// the tables are restored at different commit seqs, the job is paused only if all of them
+	// reach the stop point.
+	j.State = JobRunning
+	j.Extra.StopReachedCommitSeq = 0
+	j.progress.TableCommitSeqMap = map[int64]int64{1: 90, 2: 120}
+	if restored := j.getRestoredCommitSeq(); restored != 90 {
+		t.Fatalf("expect the restored commit seq 90, but got %d", restored)
+	}
+	if err := j.checkStopPointAfterRestore(j.getRestoredCommitSeq()); err != nil {
+		t.Fatalf("check stop point failed: %v", err)
+	}
+	if j.State != JobRunning {
+		t.Errorf("the job should not be paused since table 1 is behind the stop point")
+	}
+}
@@ -0,0 +1,247 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"fmt"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SpecUpdate is the mutable fields of the spec, the absent fields are unchanged.
+type SpecUpdate struct {
+	// The master frontend, eg. the frontend is replaced.
+	Host       string `json:"host,omitempty"`
+	Port       string `json:"port,omitempty"`
+	ThriftPort string `json:"thrift_port,omitempty"`
+
+	Frontends []base.Frontend `json:"frontends,omitempty"`
+
+	User string `json:"user,omitempty"`
+	// The password, or a reference to it, eg. `env:DORIS_PASSWORD`.
+	Password *string `json:"password,omitempty"`
+
+	// Replace the host mapping, an empty map removes all mappings.
+	HostMapping map[string]string `json:"host_mapping,omitempty"`
+}
+
+func (u *SpecUpdate) IsEmpty() bool {
+	return u == nil || (u.Host == "" && u.Port == "" && u.ThriftPort == "" && len(u.Frontends) == 0 &&
+		u.User == "" && u.Password == nil && u.HostMapping == nil)
+}
+
+func (u *SpecUpdate) String() string {
+	if u == nil {
+		return "SpecUpdate{}"
+	}
+	return fmt.Sprintf("SpecUpdate{Host: %s, Port: %s, ThriftPort: %s, Frontends: %v, User: %s, Password: %t, HostMapping: %v}",
+		u.Host, u.Port, u.ThriftPort, u.Frontends, u.User, u.Password != nil, u.HostMapping)
+}
+
+func (u *SpecUpdate) apply(spec *base.Spec) error {
+	if u.Host != "" {
+		spec.Host = u.Host
+	}
+	if u.Port != "" {
+		spec.Port = u.Port
+	}
+	if u.ThriftPort != "" {
+		spec.ThriftPort = u.ThriftPort
+	}
+	if len(u.Frontends) > 0 {
+		spec.Frontends = u.Frontends
+	}
+	if u.User != "" {
+		spec.User = u.User
+	}
+	if u.Password != nil {
+		if *u.Password == base.RedactedSecret {
+			return xerror.New(xerror.Normal, "the password is redacted, supply the password or a reference to it")
+		}
+		spec.Password = *u.Password
+		if err := spec.ResolvePassword(); err != nil {
+			return err
+		}
+	}
+	if u.HostMapping != nil {
+		spec.HostMapping = u.HostMapping
+	}
+	return nil
+}
+
+// JobOptions is the mutable options of the job, the absent options are unchanged. The options
+// deciding the synced tables or rows, eg. table filter and row filter, are not mutable since the
+// dest would be inconsistent.
+type JobOptions struct {
+	ReuseBinlogLabel  *bool  `json:"reuse_binlog_label,omitempty"`
+	ApplyDelaySeconds *int64 `json:"apply_delay_seconds,omitempty"`
+	// An empty policy removes it.
+	DDLPolicy *DDLPolicy `json:"ddl_policy,omitempty"`
+}
+
+func (o *JobOptions) IsEmpty() bool {
+	return o == nil || (o.ReuseBinlogLabel == nil && o.ApplyDelaySeconds == nil && o.DDLPolicy == nil)
+}
+
+func (o *JobOptions) apply(extra *JobExtra) {
+	if o.ReuseBinlogLabel != nil {
+		extra.ReuseBinlogLabel = *o.ReuseBinlogLabel
+	}
+	if o.ApplyDelaySeconds != nil {
+		extra.ApplyDelaySeconds = *o.ApplyDelaySeconds
+	}
+	if o.DDLPolicy != nil {
+		extra.DDLPolicy = o.DDLPolicy
+		if o.DDLPolicy.IsEmpty() {
+			extra.DDLPolicy = nil
+		}
+	}
+}
+
+// JobUpdate updates the job in place, without a full sync.
+type JobUpdate struct {
+	Src     *SpecUpdate `json:"src,omitempty"`
+	Dest    *SpecUpdate `json:"dest,omitempty"`
+	Options *JobOptions `json:"options,omitempty"`
+}
+
+func (u *JobUpdate) IsEmpty() bool {
+	return u == nil || (u.Src.IsEmpty() && u.Dest.IsEmpty() && u.Options.IsEmpty())
+}
+
+func (u *JobUpdate) String() string {
+	return fmt.Sprintf("JobUpdate{Src: %s, Dest: %s, Options: %+v}", u.Src, u.Dest, u.Options)
+}
+
+// Update the specs and the options of the job. The updated specs are checked against the
+// clusters, they must reach the same databases.
+func (j *Job) Update(update *JobUpdate) error {
+	if update.IsEmpty() {
+		return xerror.New(xerror.Normal, "nothing to update")
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	src, dest, extra := j.Src, j.Dest, j.Extra
+	if !update.Src.IsEmpty() {
+		if err := update.Src.apply(&src); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "update src failed")
+		}
+		if err := j.checkSpecUpdate(&j.Src, &src); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "src is invalid")
+		}
+	}
+	if !update.Dest.IsEmpty() {
+		if err := update.Dest.apply(&dest); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "update dest failed")
+		}
+		if err := j.checkSpecUpdate(&j.Dest, &dest); err != nil {
+			return xerror.Wrap(err, xerror.Normal, "dest is invalid")
+		}
+	}
+	if !update.Options.IsEmpty() {
+		update.Options.apply(&extra)
+	}
+
+	// The be rpcs are cached by the backends with the mapped hosts, collect them before the
+	// host mappings are updated.
+	var staleBackends []*base.Backend
+	if !update.Src.IsEmpty() && isHostMappingChanged(j.Src.HostMapping, src.HostMapping) {
+		staleBackends = append(staleBackends, mappedBackends(j.srcMeta)...)
+	}
+	if !update.Dest.IsEmpty() && isHostMappingChanged(j.Dest.HostMapping, dest.HostMapping) {
+		staleBackends = append(staleBackends, mappedBackends(j.destMeta)...)
+	}
+
+	// The specs are updated in place, since the specers, the metas and the rpcs refer to them.
+	savedSrc, savedDest, savedExtra := j.Src, j.Dest, j.Extra
+	j.Src, j.Dest, j.Extra = src, dest, extra
+	if err := j.validExtra(); err != nil {
+		j.Src, j.Dest, j.Extra = savedSrc, savedDest, savedExtra
+		return err
+	}
+	if err := j.persistJob(); err != nil {
+		j.Src, j.Dest, j.Extra = savedSrc, savedDest, savedExtra
+		return err
+	}
+
+	// Drop the cached frontends and backends, the backends of the updated host mapping get
+	// their own rpcs.
+	for _, backend := range staleBackends {
+		j.factory.RemoveBeRpc(backend)
+	}
+	if !update.Src.IsEmpty() {
+		j.factory.RemoveFeRpc(&j.Src)
+		j.srcMeta = j.factory.NewMeta(&j.Src)
+	}
+	if !update.Dest.IsEmpty() {
+		j.factory.RemoveFeRpc(&j.Dest)
+		j.destMeta = j.factory.NewMeta(&j.Dest)
+	}
+	j.storeConnectivitySpecs()
+
+	log.Infof("job %s is updated, %s", j.Name, update)
+	j.recordEvent(JobEventUpdated, JobEventDetail{}, "job updated, %s", update)
+	return nil
+}
+
+// The updated spec must be able to connect the cluster, and reach the same database.
+func (j *Job) checkSpecUpdate(old, updated *base.Spec) error {
+	if err := updated.Valid(); err != nil {
+		return err
+	}
+
+	// The rpcs are cached by the spec, release the one of the local copy.
+	defer j.factory.RemoveFeRpc(updated)
+
+	dbId, err := j.factory.NewMeta(updated).GetDbId()
+	if err != nil {
+		return err
+	}
+	if old.DbId != 0 && dbId != old.DbId {
+		return xerror.Errorf(xerror.Normal, "the id of database %s is %d, but the job is syncing %d, is it another cluster?",
+			updated.Database, dbId, old.DbId)
+	}
+	return nil
+}
+
+func isHostMappingChanged(old, updated map[string]string) bool {
+	if len(old) != len(updated) {
+		return true
+	}
+	for private, public := range old {
+		if updatedPublic, ok := updated[private]; !ok || updatedPublic != public {
+			return true
+		}
+	}
+	return false
+}
+
+// The backends with the hosts of the current host mapping, the rpcs of them are not evicted if
+// the backends can't be read, eg. the cluster is unreachable.
+func mappedBackends(meta Metaer) []*base.Backend {
+	backends, err := meta.GetBackends()
+	if err != nil {
+		log.Warnf("get the backends of the old host mapping failed, their rpcs are kept: %+v", err)
+		return nil
+	}
+	return backends
+}
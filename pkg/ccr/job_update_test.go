@@ -0,0 +1,173 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/test_util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSpecUpdateApply(t *testing.T) {
+	spec := base.Spec{
+		Frontend:    base.Frontend{Host: "10.0.0.1", Port: "9030", ThriftPort: "9020"},
+		User:        "root",
+		Database:    "ccr",
+		HostMapping: map[string]string{"172.16.0.1": "10.0.0.1"},
+	}
+
+	password := "new_password"
+	update := &SpecUpdate{
+		Host:        "10.0.0.2",
+		Frontends:   []base.Frontend{{Host: "10.0.0.2", Port: "9030", ThriftPort: "9020"}},
+		Password:    &password,
+		HostMapping: map[string]string{},
+	}
+	if err := update.apply(&spec); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if spec.Host != "10.0.0.2" || spec.Port != "9030" || spec.User != "root" || len(spec.Frontends) != 1 {
+		t.Errorf("unexpected spec: %s", spec)
+	}
+	if spec.GetPassword() != password || len(spec.HostMapping) != 0 {
+		t.Errorf("unexpected password or host mapping: %v", spec.HostMapping)
+	}
+
+	redacted := base.RedactedSecret
+	if err := (&SpecUpdate{Password: &redacted}).apply(&spec); err == nil {
+		t.Errorf("expect error for the redacted password")
+	}
+}
+
+func TestJobOptionsApply(t *testing.T) {
+	extra := JobExtra{
+		ApplyDelaySeconds: 60,
+		DDLPolicy:         &DDLPolicy{Default: DDLHold},
+	}
+
+	delay := int64(0)
+	options := &JobOptions{ApplyDelaySeconds: &delay, DDLPolicy: &DDLPolicy{}}
+	options.apply(&extra)
+	if extra.ApplyDelaySeconds != 0 || extra.DDLPolicy != nil {
+		t.Errorf("unexpected extra: %+v", extra)
+	}
+
+	if !(&JobUpdate{Src: &SpecUpdate{}, Options: &JobOptions{}}).IsEmpty() {
+		t.Errorf("expect the update is empty")
+	}
+}
+
+func newUpdateTestSpec(host string) base.Spec {
+	return base.Spec{
+		Frontend: base.Frontend{Host: host, Port: "9030", ThriftPort: "9020"},
+		User:     "root",
+		Database: "db",
+		Table:    "tbl",
+		DbId:     1,
+	}
+}
+
+func TestJobUpdateRollback(t *testing.T) {
+	delay := int64(60)
+	update := &JobUpdate{Options: &JobOptions{ApplyDelaySeconds: &delay}}
+
+	// The apply delay is invalid for the db sync.
+	j := &Job{Name: "ccr_test", SyncType: DBSync, Src: newUpdateTestSpec("10.0.0.1")}
+	j.Src.Table = ""
+	if err := j.Update(update); err == nil {
+		t.Errorf("expect the invalid options are rejected")
+	}
+	if j.Extra.ApplyDelaySeconds != 0 {
+		t.Errorf("expect the options are rolled back, but got %+v", j.Extra)
+	}
+
+	ctrl := gomock.NewController(t)
+	db := test_util.NewMockDB(ctrl)
+	db.EXPECT().UpdateJob("ccr_test", gomock.Any()).Return(errors.New("db is closed"))
+	j = &Job{Name: "ccr_test", SyncType: TableSync, Src: newUpdateTestSpec("10.0.0.1"), db: db}
+	if err := j.Update(update); err == nil {
+		t.Errorf("expect the update failed if the job is not persisted")
+	}
+	if j.Extra.ApplyDelaySeconds != 0 {
+		t.Errorf("expect the options are rolled back, but got %+v", j.Extra)
+	}
+}
+
+func TestJobUpdateCheckDbId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	rpcFactory := NewMockIRpcFactory(ctrl)
+	metaFactory := NewMockMetaerFactory(ctrl)
+	meta := NewMockMetaer(ctrl)
+	metaFactory.EXPECT().NewMeta(gomock.Any()).Return(meta)
+	meta.EXPECT().GetDbId().Return(int64(2), nil)
+	rpcFactory.EXPECT().RemoveFeRpc(gomock.Any())
+
+	j := &Job{
+		Name:     "ccr_test",
+		SyncType: TableSync,
+		Src:      newUpdateTestSpec("10.0.0.1"),
+		factory:  NewFactory(rpcFactory, metaFactory, nil, nil),
+	}
+	err := j.Update(&JobUpdate{Src: &SpecUpdate{Host: "10.0.0.2"}})
+	if err == nil || !strings.Contains(err.Error(), "another cluster") {
+		t.Errorf("expect the database id mismatch, but got %v", err)
+	}
+	if j.Src.Host != "10.0.0.1" {
+		t.Errorf("expect the src is unchanged, but got %s", j.Src.Host)
+	}
+}
+
+func TestJobUpdateEvictBeRpcs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := test_util.NewMockDB(ctrl)
+	db.EXPECT().UpdateJob("ccr_test", gomock.Any()).Return(nil)
+	db.EXPECT().AddJobEvent(gomock.Any()).Return(nil).AnyTimes()
+
+	backend := &base.Backend{Id: 1, Host: "10.0.1.1", BePort: 9060}
+	oldMeta := NewMockMetaer(ctrl)
+	oldMeta.EXPECT().GetBackends().Return([]*base.Backend{backend}, nil)
+	newMeta := NewMockMetaer(ctrl)
+	newMeta.EXPECT().GetDbId().Return(int64(1), nil)
+	metaFactory := NewMockMetaerFactory(ctrl)
+	metaFactory.EXPECT().NewMeta(gomock.Any()).Return(newMeta).Times(2)
+	rpcFactory := NewMockIRpcFactory(ctrl)
+	rpcFactory.EXPECT().RemoveFeRpc(gomock.Any()).AnyTimes()
+	rpcFactory.EXPECT().RemoveBeRpc(&base.Backend{Id: 1, Host: "10.0.1.1", BePort: 9060})
+
+	dest := newUpdateTestSpec("10.0.0.1")
+	dest.HostMapping = map[string]string{"172.16.0.1": "10.0.1.1"}
+	j := &Job{
+		Name:     "ccr_test",
+		SyncType: TableSync,
+		Src:      newUpdateTestSpec("10.0.0.1"),
+		Dest:     dest,
+		db:       db,
+		factory:  NewFactory(rpcFactory, metaFactory, nil, nil),
+		destMeta: oldMeta,
+	}
+	update := &JobUpdate{Dest: &SpecUpdate{HostMapping: map[string]string{"172.16.0.1": "10.0.1.2"}}}
+	if err := j.Update(update); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if j.Dest.HostMapping["172.16.0.1"] != "10.0.1.2" {
+		t.Errorf("unexpected host mapping: %v", j.Dest.HostMapping)
+	}
+}
@@ -18,6 +18,7 @@ package ccr
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -31,6 +32,14 @@ func NewPartialSnapshotLabelPrefix(ccrName string, syncId int64) string {
 	return fmt.Sprintf("ccrp_%s_%d", ccrName, syncId)
 }
 
+// The labels of the txns committed by a bidirectional job, the binlogs with such labels are skipped
+// by the opposite job.
+const BidirectionalLabelPrefix = "ccrb-"
+
+func IsBidirectionalLabel(label string) bool {
+	return strings.HasPrefix(label, BidirectionalLabelPrefix)
+}
+
 func NewLabelWithTs(prefix string) string {
 	return fmt.Sprintf("%s_%d", prefix, time.Now().Unix())
 }
@@ -28,6 +28,7 @@ import (
 	tstatus "github.com/selectdb/ccr_syncer/pkg/rpc/kitex_gen/status"
 	utils "github.com/selectdb/ccr_syncer/pkg/utils"
 	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	"github.com/selectdb/ccr_syncer/pkg/xtrace"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/btree"
@@ -236,7 +237,10 @@ func (m *Meta) GetTableId(tableName string) (int64, error) {
 	}
 }
 
-func (m *Meta) UpdatePartitions(tableId int64) error {
+func (m *Meta) UpdatePartitions(tableId int64) (err error) {
+	span := xtrace.Start("UpdatePartitions", xtrace.TableId(tableId))
+	defer func() { span.End(err) }()
+
 	// Step 1: get dbId
 	dbId, err := m.GetDbId()
 	if err != nil {
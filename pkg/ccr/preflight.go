@@ -0,0 +1,310 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const preflightDialTimeout = 3 * time.Second
+
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+	CheckSkip CheckStatus = "skip" // the check depends on a failed one
+)
+
+type PreflightCheck struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message,omitempty"`
+}
+
+// PreflightReport is the result of all checks before creating a job, the job could be created
+// only if no check fails.
+type PreflightReport struct {
+	Passed bool              `json:"passed"`
+	Checks []*PreflightCheck `json:"checks"`
+}
+
+func NewFailedPreflightReport(name string, err error) *PreflightReport {
+	report := &PreflightReport{}
+	report.addErr(name, err)
+	return report
+}
+
+func (r *PreflightReport) add(name string, status CheckStatus, format string, args ...any) {
+	message := format
+	if len(args) > 0 {
+		message = fmt.Sprintf(format, args...)
+	}
+	r.Checks = append(r.Checks, &PreflightCheck{Name: name, Status: status, Message: message})
+	if status == CheckFail {
+		r.Passed = false
+	}
+}
+
+func (r *PreflightReport) addErr(name string, err error) {
+	r.add(name, CheckFail, "%s", err.Error())
+}
+
+func (r *PreflightReport) countOf(status CheckStatus) int {
+	count := 0
+	for _, check := range r.Checks {
+		if check.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *PreflightReport) String() string {
+	return fmt.Sprintf("PreflightReport{Passed: %t, Pass: %d, Warn: %d, Fail: %d, Skip: %d}", r.Passed,
+		r.countOf(CheckPass), r.countOf(CheckWarn), r.countOf(CheckFail), r.countOf(CheckSkip))
+}
+
+// Preflight runs all checks of AddJob and FirstRun, and more, without changing anything. Unlike
+// AddJob, it doesn't stop at the first failure.
+func (jm *JobManager) Preflight(j *Job) *PreflightReport {
+	report := &PreflightReport{Passed: true}
+
+	jm.preflightJob(report, j)
+	srcOk := j.preflightCluster(report, "src", &j.Src, j.srcMeta, j.ISrc)
+	destOk := j.preflightCluster(report, "dest", &j.Dest, j.destMeta, j.IDest)
+
+	if srcOk {
+		j.preflightSrc(report)
+	} else {
+		report.add("src_database", CheckSkip, "src is unreachable")
+	}
+	if destOk {
+		j.preflightDest(report)
+	} else {
+		report.add("dest_database", CheckSkip, "dest is unreachable")
+	}
+
+	log.Infof("preflight job %s, %s", j.Name, report)
+	return report
+}
+
+// Check the job name and the conflicts with the other jobs, same as AddJob.
+func (jm *JobManager) preflightJob(report *PreflightReport, j *Job) {
+	if jm.hasJob(j.Name) {
+		report.addErr("job_name", xerror.XWrapf(errJobExist, "job: %s", j.Name))
+	} else {
+		report.add("job_name", CheckPass, "")
+	}
+
+	if err := jm.checkJobConflict(j); err != nil {
+		report.addErr("job_conflict", err)
+	} else {
+		report.add("job_conflict", CheckPass, "")
+	}
+}
+
+// Check the connection, the privilege, the binlog feature and the backends of the cluster,
+// returns false if the cluster is unreachable.
+func (j *Job) preflightCluster(report *PreflightReport, side string, spec *base.Spec, meta Metaer, specer base.Specer) bool {
+	frontends, err := meta.GetFrontends()
+	if err != nil {
+		report.addErr(side+"_connect", err)
+		return false
+	}
+	report.add(side+"_connect", CheckPass, "%d frontends", len(frontends))
+
+	if hasAdmin, err := specer.HasAdminPrivilege(); err != nil {
+		report.add(side+"_privilege", CheckWarn, "check the privilege of user %s failed: %v", spec.User, err)
+	} else if !hasAdmin {
+		report.add(side+"_privilege", CheckFail, "user %s has no admin privilege, which is required by backup and restore", spec.User)
+	} else {
+		report.add(side+"_privilege", CheckPass, "")
+	}
+
+	if err := meta.CheckBinlogFeature(); err != nil {
+		report.addErr(side+"_binlog_feature", err)
+	} else {
+		report.add(side+"_binlog_feature", CheckPass, "")
+	}
+
+	backends, err := meta.GetBackends()
+	if err != nil {
+		report.addErr(side+"_backends", err)
+		return true
+	}
+	// The syncer calls the rpcs of the dest backends, and the dest backends download the
+	// binlogs from the http ports of the src backends.
+	var unreachable []string
+	for _, backend := range backends {
+		port := backend.BePort
+		if side == "src" {
+			port = backend.HttpPort
+		}
+		addr := net.JoinHostPort(backend.Host, strconv.Itoa(int(port)))
+		if conn, err := net.DialTimeout("tcp", addr, preflightDialTimeout); err != nil {
+			unreachable = append(unreachable, addr)
+		} else {
+			conn.Close()
+		}
+	}
+	if len(unreachable) > 0 {
+		report.add(side+"_backends", CheckWarn, "backends %s are unreachable from the syncer, "+
+			"consider adding the host mapping if they are behind NAT", strings.Join(unreachable, ", "))
+	} else {
+		report.add(side+"_backends", CheckPass, "%d backends", len(backends))
+	}
+	return true
+}
+
+func (j *Job) preflightSrc(report *PreflightReport) {
+	if exists, err := j.ISrc.CheckDatabaseExists(); err != nil {
+		report.addErr("src_database", err)
+		return
+	} else if !exists {
+		report.add("src_database", CheckFail, "src database %s not exists", j.Src.Database)
+		return
+	}
+	report.add("src_database", CheckPass, "")
+
+	if j.SyncType == DBSync {
+		j.preflightSrcDatabase(report)
+	} else {
+		j.preflightSrcTable(report)
+	}
+	j.preflightRowFilter(report)
+}
+
+func (j *Job) preflightDest(report *PreflightReport) {
+	if exists, err := j.IDest.CheckDatabaseExists(); err != nil {
+		report.addErr("dest_database", err)
+		return
+	} else if !exists {
+		report.add("dest_database", CheckWarn, "dest database %s not exists, it will be created", j.Dest.Database)
+		return
+	}
+	report.add("dest_database", CheckPass, "")
+
+	if j.SyncType != TableSync {
+		return
+	}
+	if exists, err := j.IDest.CheckTableExists(); err != nil {
+		report.addErr("dest_table", err)
+	} else if exists && !j.Extra.allowTableExists {
+		report.add("dest_table", CheckFail, "dest table %s.%s already exists", j.Dest.Database, j.Dest.Table)
+	} else if exists {
+		report.add("dest_table", CheckWarn, "dest table %s.%s already exists, it will be overwritten by the full sync",
+			j.Dest.Database, j.Dest.Table)
+	} else {
+		report.add("dest_table", CheckPass, "")
+	}
+}
+
+func (j *Job) preflightSrcDatabase(report *PreflightReport) {
+	if enable, err := j.ISrc.IsDatabaseEnableBinlog(); err != nil {
+		report.addErr("src_database_binlog", err)
+	} else if !enable {
+		report.add("src_database_binlog", CheckFail, "src database %s not enable binlog", j.Src.Database)
+	} else {
+		report.add("src_database_binlog", CheckPass, "")
+	}
+
+	tables, err := j.ISrc.GetAllTables()
+	if err != nil {
+		report.addErr("src_table_property", err)
+		return
+	}
+	var invalidTables []string
+	for _, table := range tables {
+		if !j.Extra.TableFilter.IsEmpty() && !j.Extra.TableFilter.Match(table) {
+			continue
+		}
+		spec := j.Src
+		spec.Table = table
+		if invalidProperty, err := spec.CheckTablePropertyValid(); err != nil {
+			report.addErr("src_table_property", err)
+			return
+		} else if len(invalidProperty) != 0 {
+			invalidTables = append(invalidTables, fmt.Sprintf("%s (%s)", table, strings.Join(invalidProperty, ", ")))
+		}
+	}
+	if len(invalidTables) > 0 {
+		report.add("src_table_property", CheckWarn, "tables without the required properties: %s",
+			strings.Join(invalidTables, "; "))
+	} else {
+		report.add("src_table_property", CheckPass, "%d tables", len(tables))
+	}
+}
+
+func (j *Job) preflightSrcTable(report *PreflightReport) {
+	if exists, err := j.ISrc.CheckTableExists(); err != nil {
+		report.addErr("src_table", err)
+		return
+	} else if !exists {
+		report.add("src_table", CheckFail, "src table %s.%s not exists", j.Src.Database, j.Src.Table)
+		return
+	}
+	report.add("src_table", CheckPass, "")
+
+	if invalidProperty, err := j.ISrc.CheckTablePropertyValid(); err != nil {
+		report.addErr("src_table_property", err)
+	} else if len(invalidProperty) != 0 {
+		report.add("src_table_property", CheckFail, "src table %s.%s only support property: %s",
+			j.Src.Database, j.Src.Table, strings.Join(invalidProperty, ", "))
+	} else {
+		report.add("src_table_property", CheckPass, "")
+	}
+}
+
+// The filtered tables are loaded wholesale by the full sync, reject the tables that are too large.
+func (j *Job) preflightRowFilter(report *PreflightReport) {
+	if j.Extra.RowFilter.IsEmpty() {
+		return
+	}
+
+	var tooLarge []string
+	for table := range j.Extra.RowFilter.Predicates {
+		if exists, err := j.ISrc.CheckTableExistsByName(table); err != nil {
+			report.addErr("src_row_filter", err)
+			return
+		} else if !exists {
+			continue
+		}
+		if size, err := j.ISrc.GetTableDataSize(table); err != nil {
+			report.addErr("src_row_filter", err)
+			return
+		} else if rowFilterMaxTableSize > 0 && size > rowFilterMaxTableSize {
+			tooLarge = append(tooLarge, fmt.Sprintf("%s (%d bytes)", table, size))
+		}
+	}
+	if len(tooLarge) > 0 {
+		report.add("src_row_filter", CheckFail, "the filtered tables %s are larger than %d bytes, see row_filter_max_table_size",
+			strings.Join(tooLarge, ", "), rowFilterMaxTableSize)
+	} else {
+		report.add("src_row_filter", CheckPass, "")
+	}
+}
@@ -0,0 +1,93 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+	"github.com/selectdb/ccr_syncer/pkg/test_util"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPreflightReport(t *testing.T) {
+	report := &PreflightReport{Passed: true}
+	report.add("src_connect", CheckPass, "%d frontends", 3)
+	report.add("dest_database", CheckWarn, "dest database %s not exists, it will be created", "ccr")
+	if !report.Passed {
+		t.Errorf("expect the report passed with warnings: %s", report)
+	}
+	if report.Checks[0].Message != "3 frontends" {
+		t.Errorf("unexpected message: %s", report.Checks[0].Message)
+	}
+
+	report.add("dest_table", CheckFail, "dest table exists")
+	if report.Passed || report.countOf(CheckFail) != 1 {
+		t.Errorf("expect the report failed: %s", report)
+	}
+
+	report = NewFailedPreflightReport("config", errors.New("name is invalid"))
+	if report.Passed || len(report.Checks) != 1 || report.Checks[0].Status != CheckFail {
+		t.Errorf("unexpected report: %s", report)
+	}
+}
+
+func TestPreflightJob(t *testing.T) {
+	newJob := func(name, destTable string) *Job {
+		return &Job{
+			Name:     name,
+			SyncType: TableSync,
+			Src:      base.Spec{Frontend: base.Frontend{Host: "src", Port: "9030"}, Database: "db", Table: "tbl"},
+			Dest:     base.Spec{Frontend: base.Frontend{Host: "dest", Port: "9030"}, Database: "db", Table: destTable},
+		}
+	}
+	other, err := json.Marshal(newJob("other", "tbl"))
+	if err != nil {
+		t.Fatalf("marshal job failed: %v", err)
+	}
+
+	ctrl := gomock.NewController(t)
+	db := test_util.NewMockDB(ctrl)
+	// The broken job is skipped.
+	jobInfos := map[string]string{"other": string(other), "broken": "{"}
+	db.EXPECT().GetAllJobInfos().Return(jobInfos, nil).AnyTimes()
+	jm := NewJobManager(db, nil, "127.0.0.1:9190")
+	jm.jobs["running"] = newJob("running", "tbl_running")
+
+	tests := []struct {
+		job      *Job
+		name     CheckStatus
+		conflict CheckStatus
+	}{
+		{newJob("new", "tbl_new"), CheckPass, CheckPass},
+		{newJob("running", "tbl_new"), CheckFail, CheckPass},
+		{newJob("new", "tbl"), CheckPass, CheckFail},
+	}
+	for i, test := range tests {
+		report := &PreflightReport{Passed: true}
+		jm.preflightJob(report, test.job)
+		if len(report.Checks) != 2 || report.Checks[0].Name != "job_name" || report.Checks[1].Name != "job_conflict" {
+			t.Fatalf("test %d: unexpected checks: %+v", i, report.Checks)
+		}
+		if report.Checks[0].Status != test.name || report.Checks[1].Status != test.conflict {
+			t.Errorf("test %d: expect job_name %s and job_conflict %s, got %s and %s: %s", i,
+				test.name, test.conflict, report.Checks[0].Status, report.Checks[1].Status, report.Checks[1].Message)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/selectdb/ccr_syncer/pkg/utils"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+var rowFilterMaxTableSize int64
+
+func init() {
+	flag.Int64Var(&rowFilterMaxTableSize, "row_filter_max_table_size", 1<<30,
+		"the max data size in bytes of a table filtered by the row filter, 0 means no limit")
+}
+
+// RowFilter only syncs the rows matched by the predicate of the table.
+//
+// The binlogs of the filtered tables are not ingested, since the rowsets are copied wholesale.
+// Instead, the partitions changed by the upserts are reloaded with the matched rows, which are
+// read from the source cluster via a catalog created in the dest cluster, eg. a doris jdbc catalog.
+//
+// The catalog reads the current rows of the source, not the rows at the commit seq of the binlog,
+// and the full sync loads the whole filtered table, so the size of the filtered tables is limited
+// by row_filter_max_table_size.
+type RowFilter struct {
+	// The catalog in the dest cluster, to read the source database.
+	Catalog string `json:"catalog,omitempty"`
+	// The source table name => the conditions, which are ANDed together.
+	Predicates map[string][]*RowCondition `json:"predicates,omitempty"`
+}
+
+// RowCondition compares a column with the values, eg. `region in ('EU', 'UK')`.
+type RowCondition struct {
+	Column string   `json:"column"`
+	Op     string   `json:"op"`
+	Values []string `json:"values"`
+}
+
+var rowConditionOps = map[string]bool{
+	"=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+	"in": true, "not in": true,
+}
+
+func (c *RowCondition) String() string {
+	values := make([]string, 0, len(c.Values))
+	for _, value := range c.Values {
+		values = append(values, fmt.Sprintf("'%s'", utils.EscapeStringValue(value)))
+	}
+
+	op := strings.ToLower(strings.TrimSpace(c.Op))
+	if op == "in" || op == "not in" {
+		return fmt.Sprintf("%s %s (%s)", utils.FormatKeywordName(c.Column), strings.ToUpper(op), strings.Join(values, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", utils.FormatKeywordName(c.Column), op, strings.Join(values, ""))
+}
+
+func (c *RowCondition) Valid() error {
+	if c == nil {
+		return xerror.New(xerror.Normal, "the condition is empty")
+	}
+	if strings.TrimSpace(c.Column) == "" || strings.Contains(c.Column, "`") {
+		return xerror.Errorf(xerror.Normal, "invalid column name %q", c.Column)
+	}
+
+	op := strings.ToLower(strings.TrimSpace(c.Op))
+	if !rowConditionOps[op] {
+		return xerror.Errorf(xerror.Normal, "unsupported op %q of column %s", c.Op, c.Column)
+	}
+	if op == "in" || op == "not in" {
+		if len(c.Values) == 0 {
+			return xerror.Errorf(xerror.Normal, "the values of column %s are empty", c.Column)
+		}
+	} else if len(c.Values) != 1 {
+		return xerror.Errorf(xerror.Normal, "op %s of column %s requires exactly one value", op, c.Column)
+	}
+	return nil
+}
+
+func (f *RowFilter) String() string {
+	if f == nil {
+		return "RowFilter{}"
+	}
+	predicates := make(map[string]string, len(f.Predicates))
+	for table := range f.Predicates {
+		predicates[table], _ = f.Predicate(table)
+	}
+	return fmt.Sprintf("RowFilter{Catalog: %s, Predicates: %v}", f.Catalog, predicates)
+}
+
+func (f *RowFilter) IsEmpty() bool {
+	return f == nil || len(f.Predicates) == 0
+}
+
+func (f *RowFilter) Valid() error {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	if f.Catalog == "" {
+		return xerror.New(xerror.Normal, "the catalog of the row filter is required")
+	}
+	for table, conditions := range f.Predicates {
+		if table == "" {
+			return xerror.New(xerror.Normal, "the table of the row filter is empty")
+		}
+		if len(conditions) == 0 {
+			return xerror.Errorf(xerror.Normal, "the conditions of table %s are empty", table)
+		}
+		for _, condition := range conditions {
+			if err := condition.Valid(); err != nil {
+				return xerror.Wrapf(err, xerror.Normal, "the conditions of table %s are invalid", table)
+			}
+		}
+	}
+	return nil
+}
+
+// Predicate returns the predicate of the source table, and whether the table is filtered.
+func (f *RowFilter) Predicate(table string) (string, bool) {
+	if f.IsEmpty() {
+		return "", false
+	}
+	conditions, ok := f.Predicates[table]
+	if !ok {
+		return "", false
+	}
+
+	predicates := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		predicates = append(predicates, condition.String())
+	}
+	return strings.Join(predicates, " AND "), true
+}
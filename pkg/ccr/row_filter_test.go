@@ -0,0 +1,63 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr_test
+
+import (
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr"
+)
+
+func TestRowFilterValid(t *testing.T) {
+	newFilter := func(catalog string, conditions ...*ccr.RowCondition) *ccr.RowFilter {
+		return &ccr.RowFilter{Catalog: catalog, Predicates: map[string][]*ccr.RowCondition{"orders": conditions}}
+	}
+
+	type TestCase struct {
+		filter *ccr.RowFilter
+		valid  bool
+	}
+	tests := []TestCase{
+		{filter: nil, valid: true},
+		{filter: newFilter("src", &ccr.RowCondition{Column: "region", Op: "=", Values: []string{"EU"}}), valid: true},
+		{filter: newFilter("", &ccr.RowCondition{Column: "region", Op: "=", Values: []string{"EU"}}), valid: false},
+		{filter: newFilter("src"), valid: false},
+		{filter: newFilter("src", nil), valid: false},
+		{filter: newFilter("src", &ccr.RowCondition{Column: " ", Op: "=", Values: []string{"EU"}}), valid: false},
+		{filter: newFilter("src", &ccr.RowCondition{Column: "a`b", Op: "=", Values: []string{"EU"}}), valid: false},
+		{filter: newFilter("src", &ccr.RowCondition{Column: "region", Op: "like", Values: []string{"EU"}}), valid: false},
+		{filter: newFilter("src", &ccr.RowCondition{Column: "region", Op: "=", Values: []string{"EU", "UK"}}), valid: false},
+		{filter: newFilter("src", &ccr.RowCondition{Column: "region", Op: "in"}), valid: false},
+		{filter: newFilter("src", &ccr.RowCondition{Column: "region", Op: "NOT IN", Values: []string{"EU", "UK"}}), valid: true},
+	}
+	for i, test := range tests {
+		if err := test.filter.Valid(); (err == nil) != test.valid {
+			t.Errorf("test %d failed, filter: %s, expect valid %t, but got err %v", i, test.filter, test.valid, err)
+		}
+	}
+
+	filter := newFilter("src",
+		&ccr.RowCondition{Column: "region", Op: "in", Values: []string{"EU", "it's"}},
+		&ccr.RowCondition{Column: "id", Op: ">=", Values: []string{"1"}})
+	expect := "`region` IN ('EU', 'it\\'s') AND `id` >= '1'"
+	if predicate, ok := filter.Predicate("orders"); !ok || predicate != expect {
+		t.Errorf("unexpected predicate of orders: %s, %t", predicate, ok)
+	}
+	if _, ok := filter.Predicate("users"); ok {
+		t.Errorf("users should not be filtered")
+	}
+}
@@ -84,3 +84,27 @@ func (mr *MockIRpcFactoryMockRecorder) NewFeRpc(spec any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewFeRpc", reflect.TypeOf((*MockIRpcFactory)(nil).NewFeRpc), spec)
 }
+
+// RemoveBeRpc mocks base method.
+func (m *MockIRpcFactory) RemoveBeRpc(be *base.Backend) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveBeRpc", be)
+}
+
+// RemoveBeRpc indicates an expected call of RemoveBeRpc.
+func (mr *MockIRpcFactoryMockRecorder) RemoveBeRpc(be any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBeRpc", reflect.TypeOf((*MockIRpcFactory)(nil).RemoveBeRpc), be)
+}
+
+// RemoveFeRpc mocks base method.
+func (m *MockIRpcFactory) RemoveFeRpc(spec *base.Spec) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveFeRpc", spec)
+}
+
+// RemoveFeRpc indicates an expected call of RemoveFeRpc.
+func (mr *MockIRpcFactoryMockRecorder) RemoveFeRpc(spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFeRpc", reflect.TypeOf((*MockIRpcFactory)(nil).RemoveFeRpc), spec)
+}
@@ -0,0 +1,119 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TableFilter narrows the tables synced by a db sync job.
+//
+// A table is synced if it matches any of the include rules (or no include rule is
+// specified), and it doesn't match any of the exclude rules. The regexps must match
+// the whole table name, eg. `tmp_.*` matches `tmp_orders` but not `orders_tmp_1`.
+type TableFilter struct {
+	IncludeTables  []string `json:"include_tables,omitempty"`
+	IncludeRegexps []string `json:"include_regexps,omitempty"`
+	ExcludeTables  []string `json:"exclude_tables,omitempty"`
+	ExcludeRegexps []string `json:"exclude_regexps,omitempty"`
+
+	once           sync.Once        `json:"-"`
+	err            error            `json:"-"`
+	includeRegexps []*regexp.Regexp `json:"-"`
+	excludeRegexps []*regexp.Regexp `json:"-"`
+}
+
+func (f *TableFilter) String() string {
+	if f == nil {
+		return "TableFilter{}"
+	}
+	return fmt.Sprintf("TableFilter{IncludeTables: %v, IncludeRegexps: %v, ExcludeTables: %v, ExcludeRegexps: %v}",
+		f.IncludeTables, f.IncludeRegexps, f.ExcludeTables, f.ExcludeRegexps)
+}
+
+// IsEmpty returns true if no rules are specified, all tables are matched.
+func (f *TableFilter) IsEmpty() bool {
+	return f == nil || (len(f.IncludeTables) == 0 && len(f.IncludeRegexps) == 0 &&
+		len(f.ExcludeTables) == 0 && len(f.ExcludeRegexps) == 0)
+}
+
+func (f *TableFilter) Valid() error {
+	if f == nil {
+		return nil
+	}
+	return f.compile()
+}
+
+func (f *TableFilter) compile() error {
+	f.once.Do(func() {
+		if f.includeRegexps, f.err = compileTableRegexps(f.IncludeRegexps); f.err != nil {
+			return
+		}
+		f.excludeRegexps, f.err = compileTableRegexps(f.ExcludeRegexps)
+	})
+	return f.err
+}
+
+// Match returns true if the table should be synced.
+func (f *TableFilter) Match(table string) bool {
+	if f.IsEmpty() {
+		return true
+	}
+
+	if err := f.compile(); err != nil {
+		log.Warnf("the table filter is invalid, treat table %s as not matched, err: %+v", table, err)
+		return false
+	}
+
+	included := len(f.IncludeTables) == 0 && len(f.includeRegexps) == 0
+	if !included {
+		included = matchTable(table, f.IncludeTables, f.includeRegexps)
+	}
+	return included && !matchTable(table, f.ExcludeTables, f.excludeRegexps)
+}
+
+func matchTable(table string, names []string, regexps []*regexp.Regexp) bool {
+	for _, name := range names {
+		if name == table {
+			return true
+		}
+	}
+	for _, re := range regexps {
+		if re.MatchString(table) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileTableRegexps(patterns []string) ([]*regexp.Regexp, error) {
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, xerror.Wrapf(err, xerror.Normal, "invalid table filter regexp: %s", pattern)
+		}
+		regexps = append(regexps, re)
+	}
+	return regexps, nil
+}
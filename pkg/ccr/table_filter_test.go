@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr"
+)
+
+func TestTableFilterMatch(t *testing.T) {
+	type TestCase struct {
+		filter *ccr.TableFilter
+		table  string
+		expect bool
+	}
+	tests := []TestCase{
+		{filter: nil, table: "orders", expect: true},
+		{filter: &ccr.TableFilter{}, table: "orders", expect: true},
+		{filter: &ccr.TableFilter{IncludeTables: []string{"orders"}}, table: "orders", expect: true},
+		{filter: &ccr.TableFilter{IncludeTables: []string{"orders"}}, table: "users", expect: false},
+		{filter: &ccr.TableFilter{IncludeRegexps: []string{"ods_.*"}}, table: "ods_orders", expect: true},
+		{filter: &ccr.TableFilter{IncludeRegexps: []string{"ods_.*"}}, table: "dw_ods_orders", expect: false},
+		{filter: &ccr.TableFilter{ExcludeTables: []string{"scratch"}}, table: "scratch", expect: false},
+		{filter: &ccr.TableFilter{ExcludeTables: []string{"scratch"}}, table: "orders", expect: true},
+		{filter: &ccr.TableFilter{ExcludeRegexps: []string{"tmp_.*|.*_staging"}}, table: "orders_staging", expect: false},
+		{filter: &ccr.TableFilter{ExcludeRegexps: []string{"tmp_.*|.*_staging"}}, table: "orders_tmp_1", expect: true},
+		{
+			filter: &ccr.TableFilter{IncludeRegexps: []string{"orders.*"}, ExcludeTables: []string{"orders_staging"}},
+			table:  "orders_staging",
+			expect: false,
+		},
+		{
+			filter: &ccr.TableFilter{IncludeTables: []string{"users"}, IncludeRegexps: []string{"orders.*"}},
+			table:  "users",
+			expect: true,
+		},
+	}
+	for i, test := range tests {
+		if got := test.filter.Match(test.table); got != test.expect {
+			t.Errorf("test %d failed, filter: %s, table: %s, expect %t, but got %t",
+				i, test.filter, test.table, test.expect, got)
+		}
+	}
+}
+
+func TestTableFilterValid(t *testing.T) {
+	filter := &ccr.TableFilter{ExcludeRegexps: []string{"tmp_("}}
+	if err := filter.Valid(); err == nil {
+		t.Errorf("expect invalid regexp error, filter: %s", filter)
+	}
+	if filter.Match("orders") {
+		t.Errorf("expect invalid filter not match any table")
+	}
+
+	var nilFilter *ccr.TableFilter
+	if err := nilFilter.Valid(); err != nil {
+		t.Errorf("expect nil filter is valid, but got %v", err)
+	}
+}
+
+func TestTableFilterUnmarshalJSON(t *testing.T) {
+	data := `{"include_regexps": ["ods_.*"], "exclude_tables": ["ods_scratch"]}`
+	filter := &ccr.TableFilter{}
+	if err := json.Unmarshal([]byte(data), filter); err != nil {
+		t.Fatalf("unmarshal table filter failed: %v", err)
+	}
+	if !filter.Match("ods_orders") || filter.Match("ods_scratch") || filter.Match("orders") {
+		t.Errorf("unexpected match result, filter: %s", filter)
+	}
+}
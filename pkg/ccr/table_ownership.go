@@ -0,0 +1,114 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+// TableOwnership declares the dest tables owned by a db sync job, so that several jobs are
+// able to sync into the same dest database. A job only creates, drops and restores the tables
+// it owns.
+//
+// It is also used to declare the source tables written in the direction of a bidirectional job.
+//
+// An empty ownership means the job owns the whole dest database.
+type TableOwnership struct {
+	// The exact dest table names
+	Tables []string `json:"tables,omitempty"`
+	// The prefixes of the dest table names
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+func (o *TableOwnership) String() string {
+	if o.IsEmpty() {
+		return "TableOwnership{all}"
+	}
+	return fmt.Sprintf("TableOwnership{Tables: %v, Prefixes: %v}", o.Tables, o.Prefixes)
+}
+
+// IsEmpty returns true if the whole dest database is owned.
+func (o *TableOwnership) IsEmpty() bool {
+	return o == nil || (len(o.Tables) == 0 && len(o.Prefixes) == 0)
+}
+
+func (o *TableOwnership) Valid() error {
+	if o == nil {
+		return nil
+	}
+
+	for _, table := range o.Tables {
+		if strings.TrimSpace(table) == "" {
+			return xerror.New(xerror.Normal, "the owned table name is empty")
+		}
+	}
+	for _, prefix := range o.Prefixes {
+		if strings.TrimSpace(prefix) == "" {
+			return xerror.New(xerror.Normal, "the owned table prefix is empty")
+		}
+	}
+	return nil
+}
+
+// Owns returns true if the dest table is owned.
+func (o *TableOwnership) Owns(destTable string) bool {
+	if o.IsEmpty() {
+		return true
+	}
+
+	for _, table := range o.Tables {
+		if table == destTable {
+			return true
+		}
+	}
+	for _, prefix := range o.Prefixes {
+		if strings.HasPrefix(destTable, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conflict returns the description of the first conflict if the two ownerships overlap,
+// or empty string if not.
+func (o *TableOwnership) Conflict(other *TableOwnership) string {
+	if o.IsEmpty() || other.IsEmpty() {
+		return "the whole database is owned"
+	}
+
+	for _, table := range o.Tables {
+		if other.Owns(table) {
+			return fmt.Sprintf("table %s is owned by both", table)
+		}
+	}
+	for _, table := range other.Tables {
+		if o.Owns(table) {
+			return fmt.Sprintf("table %s is owned by both", table)
+		}
+	}
+	for _, prefix := range o.Prefixes {
+		for _, otherPrefix := range other.Prefixes {
+			if strings.HasPrefix(prefix, otherPrefix) || strings.HasPrefix(otherPrefix, prefix) {
+				return fmt.Sprintf("table prefix %s overlaps with %s", prefix, otherPrefix)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr_test
+
+import (
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr"
+)
+
+func TestTableOwnershipOwns(t *testing.T) {
+	type TestCase struct {
+		ownership *ccr.TableOwnership
+		table     string
+		expect    bool
+	}
+	tests := []TestCase{
+		{ownership: nil, table: "orders", expect: true},
+		{ownership: &ccr.TableOwnership{Tables: []string{"orders"}}, table: "orders", expect: true},
+		{ownership: &ccr.TableOwnership{Tables: []string{"orders"}}, table: "users", expect: false},
+		{ownership: &ccr.TableOwnership{Prefixes: []string{"east_"}}, table: "east_orders", expect: true},
+		{ownership: &ccr.TableOwnership{Prefixes: []string{"east_"}}, table: "west_orders", expect: false},
+	}
+	for i, test := range tests {
+		if got := test.ownership.Owns(test.table); got != test.expect {
+			t.Errorf("test %d failed, ownership: %s, table: %s, expect %t, but got %t",
+				i, test.ownership, test.table, test.expect, got)
+		}
+	}
+}
+
+func TestTableOwnershipConflict(t *testing.T) {
+	type TestCase struct {
+		a, b     *ccr.TableOwnership
+		conflict bool
+	}
+	tests := []TestCase{
+		{a: nil, b: nil, conflict: true},
+		{a: nil, b: &ccr.TableOwnership{Tables: []string{"orders"}}, conflict: true},
+		{a: &ccr.TableOwnership{Tables: []string{"orders"}}, b: &ccr.TableOwnership{Tables: []string{"users"}}, conflict: false},
+		{a: &ccr.TableOwnership{Tables: []string{"orders"}}, b: &ccr.TableOwnership{Tables: []string{"orders"}}, conflict: true},
+		{a: &ccr.TableOwnership{Prefixes: []string{"east_"}}, b: &ccr.TableOwnership{Prefixes: []string{"west_"}}, conflict: false},
+		{a: &ccr.TableOwnership{Prefixes: []string{"east_"}}, b: &ccr.TableOwnership{Prefixes: []string{"east_us_"}}, conflict: true},
+		{a: &ccr.TableOwnership{Prefixes: []string{"east_"}}, b: &ccr.TableOwnership{Tables: []string{"east_orders"}}, conflict: true},
+		{a: &ccr.TableOwnership{Tables: []string{"west_orders"}}, b: &ccr.TableOwnership{Prefixes: []string{"east_"}}, conflict: false},
+	}
+	for i, test := range tests {
+		if got := test.a.Conflict(test.b) != ""; got != test.conflict {
+			t.Errorf("test %d failed, a: %s, b: %s, expect conflict %t, but got %t",
+				i, test.a, test.b, test.conflict, got)
+		}
+	}
+}
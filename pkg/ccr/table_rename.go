@@ -0,0 +1,91 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+)
+
+// TableRenameRule maps the source table name to the dest table name, for db sync only.
+//
+// The explicit mapping in Tables is preferred, otherwise the dest name is Prefix + name + Suffix,
+// eg. `orders` -> `dr_orders` with prefix `dr_`.
+type TableRenameRule struct {
+	Tables map[string]string `json:"tables,omitempty"`
+	Prefix string            `json:"prefix,omitempty"`
+	Suffix string            `json:"suffix,omitempty"`
+}
+
+func (r *TableRenameRule) String() string {
+	if r == nil {
+		return "TableRenameRule{}"
+	}
+	return fmt.Sprintf("TableRenameRule{Tables: %v, Prefix: %s, Suffix: %s}", r.Tables, r.Prefix, r.Suffix)
+}
+
+// IsEmpty returns true if the dest table names are the same as the source.
+func (r *TableRenameRule) IsEmpty() bool {
+	return r == nil || (len(r.Tables) == 0 && r.Prefix == "" && r.Suffix == "")
+}
+
+func (r *TableRenameRule) Valid() error {
+	if r == nil {
+		return nil
+	}
+
+	destNames := make(map[string]string)
+	for src, dest := range r.Tables {
+		if strings.TrimSpace(src) == "" || strings.TrimSpace(dest) == "" {
+			return xerror.Errorf(xerror.Normal, "table rename has empty name, src: '%s', dest: '%s'", src, dest)
+		}
+		if other, ok := destNames[dest]; ok {
+			return xerror.Errorf(xerror.Normal, "table %s and %s are renamed to the same dest table %s", other, src, dest)
+		}
+		destNames[dest] = src
+	}
+
+	// The dest name of the explicit mapping might be derived from another source table by the
+	// prefix and suffix, eg. `a` -> `dr_b` and `b` -> `dr_b` with prefix `dr_`.
+	if r.Prefix == "" && r.Suffix == "" {
+		return nil
+	}
+	for src, dest := range r.Tables {
+		if !strings.HasPrefix(dest, r.Prefix) || !strings.HasSuffix(dest, r.Suffix) ||
+			len(dest) <= len(r.Prefix)+len(r.Suffix) {
+			continue
+		}
+		other := dest[len(r.Prefix) : len(dest)-len(r.Suffix)]
+		if _, ok := r.Tables[other]; !ok && other != src {
+			return xerror.Errorf(xerror.Normal, "table %s and %s are renamed to the same dest table %s", src, other, dest)
+		}
+	}
+	return nil
+}
+
+// DestName returns the dest table name of the source table.
+func (r *TableRenameRule) DestName(table string) string {
+	if r.IsEmpty() || table == "" {
+		return table
+	}
+	if dest, ok := r.Tables[table]; ok {
+		return dest
+	}
+	return r.Prefix + table + r.Suffix
+}
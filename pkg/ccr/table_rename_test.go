@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package ccr_test
+
+import (
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr"
+)
+
+func TestTableRenameRuleDestName(t *testing.T) {
+	type TestCase struct {
+		rule          *ccr.TableRenameRule
+		table, expect string
+	}
+	tests := []TestCase{
+		{rule: nil, table: "orders", expect: "orders"},
+		{rule: &ccr.TableRenameRule{}, table: "orders", expect: "orders"},
+		{rule: &ccr.TableRenameRule{Prefix: "dr_"}, table: "orders", expect: "dr_orders"},
+		{rule: &ccr.TableRenameRule{Suffix: "_bak"}, table: "orders", expect: "orders_bak"},
+		{rule: &ccr.TableRenameRule{Prefix: "dr_", Suffix: "_bak"}, table: "orders", expect: "dr_orders_bak"},
+		{
+			rule:   &ccr.TableRenameRule{Tables: map[string]string{"orders": "all_orders"}, Prefix: "dr_"},
+			table:  "orders",
+			expect: "all_orders",
+		},
+		{
+			rule:   &ccr.TableRenameRule{Tables: map[string]string{"orders": "all_orders"}, Prefix: "dr_"},
+			table:  "users",
+			expect: "dr_users",
+		},
+		{rule: &ccr.TableRenameRule{Prefix: "dr_"}, table: "", expect: ""},
+	}
+	for i, test := range tests {
+		if got := test.rule.DestName(test.table); got != test.expect {
+			t.Errorf("test %d failed, rule: %s, expect %s, but got %s", i, test.rule, test.expect, got)
+		}
+	}
+}
+
+func TestTableRenameRuleValid(t *testing.T) {
+	rule := &ccr.TableRenameRule{Tables: map[string]string{"a": "c", "b": "c"}}
+	if err := rule.Valid(); err == nil {
+		t.Errorf("expect conflict error, rule: %s", rule)
+	}
+
+	rule = &ccr.TableRenameRule{Tables: map[string]string{"a": ""}}
+	if err := rule.Valid(); err == nil {
+		t.Errorf("expect empty name error, rule: %s", rule)
+	}
+
+	rule = &ccr.TableRenameRule{Tables: map[string]string{"a": "b", "b": "a"}, Prefix: "dr_"}
+	if err := rule.Valid(); err != nil {
+		t.Errorf("expect valid, rule: %s, err: %v", rule, err)
+	}
+
+	for _, rule := range []*ccr.TableRenameRule{
+		{Tables: map[string]string{"a": "dr_b"}, Prefix: "dr_"},
+		{Tables: map[string]string{"a": "b_bak"}, Suffix: "_bak"},
+	} {
+		if err := rule.Valid(); err == nil {
+			t.Errorf("expect conflict with the derived name, rule: %s", rule)
+		}
+	}
+
+	for _, rule := range []*ccr.TableRenameRule{
+		{Tables: map[string]string{"a": "dr_b", "b": "c"}, Prefix: "dr_"},
+		{Tables: map[string]string{"a": "dr_a"}, Prefix: "dr_"},
+		{Tables: map[string]string{"a": "dr_"}, Prefix: "dr_"},
+		{Tables: map[string]string{"a": "b"}},
+	} {
+		if err := rule.Valid(); err != nil {
+			t.Errorf("expect valid, rule: %s, err: %v", rule, err)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package notify
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	log "github.com/sirupsen/logrus"
+)
+
+var notifyConfigFile string
+
+func init() {
+	flag.StringVar(&notifyConfigFile, "notify_config", "",
+		"the json file of the webhook notifications, no notification is sent if it is empty")
+}
+
+type Event string
+
+const (
+	EventJobPanic         Event = "job_panic"
+	EventFullSyncFallback Event = "full_sync_fallback"
+	EventLagExceeded      Event = "lag_exceeded"
+	EventLagRecovered     Event = "lag_recovered"
+	EventBinlogSkipped    Event = "binlog_skipped"
+	EventSyncerDead       Event = "syncer_dead"
+	EventJobRebalanced    Event = "job_rebalanced"
+)
+
+var events = []Event{
+	EventJobPanic, EventFullSyncFallback, EventLagExceeded, EventLagRecovered,
+	EventBinlogSkipped, EventSyncerDead, EventJobRebalanced,
+}
+
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+)
+
+func (e Event) severity() Severity {
+	switch e {
+	case EventJobPanic, EventSyncerDead:
+		return SeverityCritical
+	case EventLagRecovered:
+		return SeverityInfo
+	default:
+		return SeverityWarning
+	}
+}
+
+const (
+	defaultDedupSeconds       = 600
+	defaultRateLimitPerMinute = 20
+	defaultRetries            = 3
+	webhookQueueSize          = 128
+)
+
+type WebhookConfig struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+	// The payload format, json (default), slack or pagerduty.
+	Format string `json:"format,omitempty"`
+	// The routing key (integration key) of the pagerduty events api v2.
+	RoutingKey string `json:"routing_key,omitempty"`
+	// Only send the events, all events if it is empty.
+	Events  []Event           `json:"events,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+func (c *WebhookConfig) accept(event Event) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type Config struct {
+	Webhooks []WebhookConfig `json:"webhooks"`
+	// Notify if the lag of a job exceeds the seconds, 0 to disable the lag notifications.
+	LagThresholdSeconds int64 `json:"lag_threshold_seconds,omitempty"`
+	// The same event of the same job is sent at most once in the interval.
+	DedupSeconds int64 `json:"dedup_seconds,omitempty"`
+	// Each webhook sends at most the number of notifications in a minute, the others are dropped.
+	RateLimitPerMinute int `json:"rate_limit_per_minute,omitempty"`
+	// The retry times if the webhook fails, with exponential backoff.
+	Retries int `json:"retries,omitempty"`
+}
+
+func (c *Config) Valid() error {
+	if len(c.Webhooks) == 0 {
+		return xerror.Errorf(xerror.Normal, "no webhook")
+	}
+	for i := range c.Webhooks {
+		webhook := &c.Webhooks[i]
+		if webhook.Url == "" {
+			return xerror.Errorf(xerror.Normal, "the url of webhook %d is empty", i)
+		}
+		if webhook.Name == "" {
+			webhook.Name = webhook.Url
+		}
+		switch webhook.Format {
+		case "":
+			webhook.Format = FormatJson
+		case FormatJson, FormatSlack:
+		case FormatPagerDuty:
+			if webhook.RoutingKey == "" {
+				return xerror.Errorf(xerror.Normal, "the routing key of pagerduty webhook %s is empty", webhook.Name)
+			}
+		default:
+			return xerror.Errorf(xerror.Normal, "unknown format %s of webhook %s", webhook.Format, webhook.Name)
+		}
+		for _, event := range webhook.Events {
+			if !isValidEvent(event) {
+				return xerror.Errorf(xerror.Normal, "unknown event %s of webhook %s", event, webhook.Name)
+			}
+		}
+	}
+
+	if c.LagThresholdSeconds < 0 || c.DedupSeconds < 0 || c.RateLimitPerMinute < 0 || c.Retries < 0 {
+		return xerror.Errorf(xerror.Normal, "the lag threshold, dedup seconds, rate limit and retries should not be negative")
+	}
+	if c.DedupSeconds == 0 {
+		c.DedupSeconds = defaultDedupSeconds
+	}
+	if c.RateLimitPerMinute == 0 {
+		c.RateLimitPerMinute = defaultRateLimitPerMinute
+	}
+	if c.Retries == 0 {
+		c.Retries = defaultRetries
+	}
+	return nil
+}
+
+func isValidEvent(event Event) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.Normal, "read notify config %s failed", path)
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, xerror.Wrapf(err, xerror.Normal, "parse notify config %s failed", path)
+	}
+	if err := config.Valid(); err != nil {
+		return nil, xerror.Wrapf(err, xerror.Normal, "notify config %s is invalid", path)
+	}
+	return &config, nil
+}
+
+type Notification struct {
+	Event    Event    `json:"event"`
+	Severity Severity `json:"severity"`
+	Job      string   `json:"job,omitempty"`
+	Host     string   `json:"host"` // the syncer sending the notification
+	Message  string   `json:"message"`
+	Time     int64    `json:"time"` // unix time in milliseconds
+}
+
+// The notifications of the same key are deduplicated.
+func (n *Notification) dedupKey() string {
+	if n.Job == "" {
+		return fmt.Sprintf("%s/%s", n.Event, n.Message)
+	}
+	return fmt.Sprintf("%s/%s", n.Event, n.Job)
+}
+
+// The lag exceeded and lag recovered notifications open and resolve the same incident.
+var pairedEvents = map[Event]Event{
+	EventLagExceeded:  EventLagRecovered,
+	EventLagRecovered: EventLagExceeded,
+}
+
+// The lag recovered notification resolves the incident of the lag exceeded notification.
+func (n *Notification) incidentKey() string {
+	event := n.Event
+	if event == EventLagRecovered {
+		event = EventLagExceeded
+	}
+	subject := n.Job
+	if subject == "" {
+		subject = n.Message
+	}
+	return fmt.Sprintf("ccr_syncer/%s/%s", event, subject)
+}
+
+type Notifier struct {
+	config   *Config
+	hostInfo string
+	webhooks []*webhook
+
+	lock     sync.Mutex
+	lastSent map[string]time.Time
+}
+
+func NewNotifier(config *Config, hostInfo string) *Notifier {
+	n := &Notifier{
+		config:   config,
+		hostInfo: hostInfo,
+		lastSent: make(map[string]time.Time),
+	}
+	for i := range config.Webhooks {
+		n.webhooks = append(n.webhooks, newWebhook(&config.Webhooks[i], config))
+	}
+	return n
+}
+
+func (n *Notifier) Start() {
+	for _, w := range n.webhooks {
+		go w.run()
+	}
+}
+
+// Returns false if the notification is deduplicated.
+func (n *Notifier) dedup(notification *Notification, now time.Time) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	key := notification.dedupKey()
+	if last, ok := n.lastSent[key]; ok && now.Sub(last) < time.Duration(n.config.DedupSeconds)*time.Second {
+		return false
+	}
+	n.lastSent[key] = now
+
+	// The incident state is flipped, so the next notification of the paired event must be sent,
+	// eg. the lag exceeds again after it is recovered.
+	if paired, ok := pairedEvents[notification.Event]; ok {
+		pairedNotification := *notification
+		pairedNotification.Event = paired
+		delete(n.lastSent, pairedNotification.dedupKey())
+	}
+
+	// drop the expired keys
+	for k, last := range n.lastSent {
+		if now.Sub(last) >= time.Duration(n.config.DedupSeconds)*time.Second {
+			delete(n.lastSent, k)
+		}
+	}
+	return true
+}
+
+// Send the notification to the webhooks asynchronously, it never blocks the caller.
+func (n *Notifier) Notify(event Event, job string, message string) {
+	now := time.Now()
+	notification := &Notification{
+		Event:    event,
+		Severity: event.severity(),
+		Job:      job,
+		Host:     n.hostInfo,
+		Message:  message,
+		Time:     now.UnixMilli(),
+	}
+	if !n.dedup(notification, now) {
+		log.Debugf("notification %s of job %s is deduplicated", event, job)
+		return
+	}
+
+	for _, w := range n.webhooks {
+		if !w.config.accept(event) {
+			continue
+		}
+		select {
+		case w.queue <- notification:
+		default:
+			log.Warnf("the queue of webhook %s is full, drop notification %s of job %s", w.config.Name, event, job)
+		}
+	}
+}
+
+var defaultNotifier atomic.Pointer[Notifier]
+
+// Load the notify config and start the notifier, do nothing if the config is not set.
+func Init(hostInfo string) error {
+	if notifyConfigFile == "" {
+		return nil
+	}
+
+	config, err := loadConfig(notifyConfigFile)
+	if err != nil {
+		return err
+	}
+	notifier := NewNotifier(config, hostInfo)
+	notifier.Start()
+	defaultNotifier.Store(notifier)
+	log.Infof("notify is enabled, %d webhooks, lag threshold: %ds", len(config.Webhooks), config.LagThresholdSeconds)
+	return nil
+}
+
+func Notify(event Event, job string, format string, args ...any) {
+	if notifier := defaultNotifier.Load(); notifier != nil {
+		notifier.Notify(event, job, fmt.Sprintf(format, args...))
+	}
+}
+
+// The lag threshold in seconds, 0 if the lag notifications are disabled.
+func LagThresholdSeconds() int64 {
+	if notifier := defaultNotifier.Load(); notifier != nil {
+		return notifier.config.LagThresholdSeconds
+	}
+	return 0
+}
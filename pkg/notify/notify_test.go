@@ -0,0 +1,172 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeReceiver struct {
+	lock     sync.Mutex
+	bodies   []map[string]any
+	failures int // respond 500 to the first failures requests
+}
+
+func (f *fakeReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.failures > 0 {
+		f.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.bodies = append(f.bodies, body)
+}
+
+func (f *fakeReceiver) received() []map[string]any {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]map[string]any(nil), f.bodies...)
+}
+
+func waitReceived(t *testing.T, receiver *fakeReceiver, expect int) []map[string]any {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if bodies := receiver.received(); len(bodies) >= expect {
+			return bodies
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expect %d notifications, but got %d", expect, len(receiver.received()))
+	return nil
+}
+
+func TestConfigValid(t *testing.T) {
+	tests := []struct {
+		config Config
+		valid  bool
+	}{
+		{config: Config{}, valid: false},
+		{config: Config{Webhooks: []WebhookConfig{{Url: "http://localhost"}}}, valid: true},
+		{config: Config{Webhooks: []WebhookConfig{{Url: "http://localhost", Format: "teams"}}}, valid: false},
+		{config: Config{Webhooks: []WebhookConfig{{Url: "http://localhost", Format: FormatPagerDuty}}}, valid: false},
+		{config: Config{Webhooks: []WebhookConfig{{Url: "http://localhost", Events: []Event{"unknown"}}}}, valid: false},
+	}
+	for i, test := range tests {
+		if err := test.config.Valid(); (err == nil) != test.valid {
+			t.Errorf("test %d expect valid %t, but got err: %v", i, test.valid, err)
+		}
+	}
+}
+
+func TestNotifierDedupAndRetry(t *testing.T) {
+	retryBackoff = time.Millisecond
+	receiver := &fakeReceiver{failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	config := &Config{Webhooks: []WebhookConfig{{Url: server.URL}}}
+	if err := config.Valid(); err != nil {
+		t.Fatalf("config invalid: %v", err)
+	}
+	notifier := NewNotifier(config, "127.0.0.1:9190")
+	notifier.Start()
+
+	notifier.Notify(EventJobPanic, "job1", "panic")
+	notifier.Notify(EventJobPanic, "job1", "panic again") // deduplicated
+	notifier.Notify(EventJobPanic, "job2", "panic")
+
+	bodies := waitReceived(t, receiver, 2)
+	time.Sleep(50 * time.Millisecond)
+	if bodies = receiver.received(); len(bodies) != 2 {
+		t.Fatalf("expect 2 notifications, but got %d", len(bodies))
+	}
+	if bodies[0]["job"] != "job1" || bodies[0]["severity"] != string(SeverityCritical) || bodies[1]["job"] != "job2" {
+		t.Errorf("unexpected notifications: %v", bodies)
+	}
+}
+
+func TestNotifierDedupPairedEvents(t *testing.T) {
+	notifier := NewNotifier(&Config{DedupSeconds: 600}, "127.0.0.1:9190")
+	now := time.Now()
+	tests := []struct {
+		event Event
+		sent  bool
+	}{
+		{EventLagExceeded, true},
+		{EventLagExceeded, false},
+		{EventLagRecovered, true},
+		{EventLagRecovered, false},
+		{EventLagExceeded, true},
+		{EventLagRecovered, true},
+	}
+	for i, test := range tests {
+		notification := &Notification{Event: test.event, Job: "job1", Message: "lag"}
+		if sent := notifier.dedup(notification, now.Add(time.Duration(i)*time.Second)); sent != test.sent {
+			t.Errorf("notification %d %s: expect sent %t, got %t", i, test.event, test.sent, sent)
+		}
+	}
+}
+
+func TestWebhookRateLimit(t *testing.T) {
+	w := newWebhook(&WebhookConfig{Url: "http://localhost"}, &Config{RateLimitPerMinute: 2})
+	now := time.Now()
+	if !w.allow(now) || !w.allow(now.Add(time.Second)) {
+		t.Fatalf("expect the first 2 notifications allowed")
+	}
+	if w.allow(now.Add(2 * time.Second)) {
+		t.Errorf("expect the 3rd notification in a minute is rate limited")
+	}
+	if !w.allow(now.Add(time.Minute)) {
+		t.Errorf("expect the notification allowed after a minute")
+	}
+}
+
+func TestPagerDutyPayload(t *testing.T) {
+	w := newWebhook(&WebhookConfig{Url: "http://localhost", Format: FormatPagerDuty, RoutingKey: "key"}, &Config{})
+	exceeded := &Notification{Event: EventLagExceeded, Severity: EventLagExceeded.severity(), Job: "job1", Time: 1700000000000}
+	recovered := &Notification{Event: EventLagRecovered, Severity: EventLagRecovered.severity(), Job: "job1", Time: 1700000000000}
+
+	var trigger, resolve map[string]any
+	for _, item := range []struct {
+		n    *Notification
+		dest *map[string]any
+	}{{exceeded, &trigger}, {recovered, &resolve}} {
+		body, err := w.payload(item.n)
+		if err != nil {
+			t.Fatalf("payload failed: %v", err)
+		}
+		if err := json.Unmarshal(body, item.dest); err != nil {
+			t.Fatalf("unmarshal payload failed: %v", err)
+		}
+	}
+
+	if trigger["event_action"] != "trigger" || resolve["event_action"] != "resolve" {
+		t.Errorf("unexpected actions: %v, %v", trigger["event_action"], resolve["event_action"])
+	}
+	if trigger["dedup_key"] != resolve["dedup_key"] {
+		t.Errorf("expect the same dedup key, but got %v and %v", trigger["dedup_key"], resolve["dedup_key"])
+	}
+}
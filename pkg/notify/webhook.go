@@ -0,0 +1,194 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	"github.com/selectdb/ccr_syncer/pkg/xmetrics"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	FormatJson      = "json"
+	FormatSlack     = "slack"
+	FormatPagerDuty = "pagerduty"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// The backoff of the first retry, doubled in each retry.
+var retryBackoff = time.Second
+
+type webhook struct {
+	config             *WebhookConfig
+	retries            int
+	rateLimitPerMinute int
+	client             *http.Client
+	queue              chan *Notification
+
+	// the send time of the notifications in the last minute, for the rate limiting.
+	sent []time.Time
+}
+
+func newWebhook(config *WebhookConfig, notifyConfig *Config) *webhook {
+	return &webhook{
+		config:             config,
+		retries:            notifyConfig.Retries,
+		rateLimitPerMinute: notifyConfig.RateLimitPerMinute,
+		client:             &http.Client{Timeout: webhookTimeout},
+		queue:              make(chan *Notification, webhookQueueSize),
+	}
+}
+
+func (w *webhook) run() {
+	for notification := range w.queue {
+		if !w.allow(time.Now()) {
+			log.Warnf("webhook %s is rate limited, drop notification %s of job %s",
+				w.config.Name, notification.Event, notification.Job)
+			xmetrics.Notification(string(notification.Event), "rate_limited")
+			continue
+		}
+
+		if err := w.sendWithRetry(notification); err != nil {
+			log.Warnf("send notification %s of job %s to webhook %s failed: %+v",
+				notification.Event, notification.Job, w.config.Name, err)
+			xmetrics.Notification(string(notification.Event), "failed")
+		} else {
+			xmetrics.Notification(string(notification.Event), "sent")
+		}
+	}
+}
+
+// Sliding window rate limiting, returns false if the webhook has sent too many notifications in the last minute.
+func (w *webhook) allow(now time.Time) bool {
+	i := 0
+	for i < len(w.sent) && now.Sub(w.sent[i]) >= time.Minute {
+		i++
+	}
+	w.sent = w.sent[i:]
+	if len(w.sent) >= w.rateLimitPerMinute {
+		return false
+	}
+	w.sent = append(w.sent, now)
+	return true
+}
+
+func (w *webhook) sendWithRetry(notification *Notification) error {
+	body, err := w.payload(notification)
+	if err != nil {
+		return err
+	}
+
+	backoff := retryBackoff
+	for i := 0; ; i++ {
+		retryable, err := w.send(body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || i >= w.retries {
+			return err
+		}
+		log.Infof("send notification to webhook %s failed, retry after %s: %v", w.config.Name, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// Returns whether the error is retryable, the client errors except 429 are not retried.
+func (w *webhook) send(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.config.Url, bytes.NewReader(body))
+	if err != nil {
+		return false, xerror.Wrapf(err, xerror.Normal, "new request of webhook %s failed", w.config.Name)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, xerror.Wrapf(err, xerror.Normal, "post webhook %s failed", w.config.Name)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = xerror.Errorf(xerror.Normal, "webhook %s responds %d: %s", w.config.Name, resp.StatusCode, respBody)
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, err
+}
+
+func (w *webhook) payload(n *Notification) ([]byte, error) {
+	var payload any
+	switch w.config.Format {
+	case FormatSlack:
+		payload = map[string]string{"text": n.summary()}
+	case FormatPagerDuty:
+		payload = pagerDutyEvent(w.config.RoutingKey, n)
+	default:
+		payload = n
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.Normal, "marshal notification %s failed", n.Event)
+	}
+	return body, nil
+}
+
+func (n *Notification) summary() string {
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "[%s] ccr syncer %s %s", strings.ToUpper(string(n.Severity)), n.Host, n.Event)
+	if n.Job != "" {
+		fmt.Fprintf(&summary, ", job %s", n.Job)
+	}
+	fmt.Fprintf(&summary, ": %s", n.Message)
+	return summary.String()
+}
+
+// The event of the pagerduty events api v2, the lag recovered notification resolves the incident.
+func pagerDutyEvent(routingKey string, n *Notification) map[string]any {
+	action := "trigger"
+	if n.Event == EventLagRecovered {
+		action = "resolve"
+	}
+	return map[string]any{
+		"routing_key":  routingKey,
+		"event_action": action,
+		"dedup_key":    n.incidentKey(),
+		"payload": map[string]any{
+			"summary":        n.summary(),
+			"source":         n.Host,
+			"severity":       string(n.Severity),
+			"component":      "ccr_syncer",
+			"group":          n.Job,
+			"class":          string(n.Event),
+			"timestamp":      time.UnixMilli(n.Time).UTC().Format(time.RFC3339),
+			"custom_details": n,
+		},
+	}
+}
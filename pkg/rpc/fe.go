@@ -495,8 +495,9 @@ type Request interface {
 // set auth info from spec
 func setAuthInfo[T Request](request T, spec *base.Spec) {
 	// set auth info
+	password := spec.GetPassword()
 	request.SetUser(&spec.User)
-	request.SetPasswd(&spec.Password)
+	request.SetPasswd(&password)
 	request.SetDb(&spec.Database)
 }
 
@@ -822,10 +823,11 @@ func (rpc *singleFeClient) GetMasterToken(spec *base.Spec) (*festruct.TGetMaster
 	log.Debugf("Call GetMasterToken, addr: %s, spec: %s", rpc.Address(), spec)
 
 	client := rpc.client
+	password := spec.GetPassword()
 	req := &festruct.TGetMasterTokenRequest{
 		Cluster:  &spec.Cluster,
 		User:     &spec.User,
-		Password: &spec.Password,
+		Password: &password,
 	}
 
 	log.Debugf("GetMasterToken user: %s", *req.User)
@@ -843,9 +845,10 @@ func (rpc *singleFeClient) getMeta(spec *base.Spec, reqTables []*festruct.TGetMe
 	reqDb.Id = &spec.DbId
 	reqDb.SetTables(reqTables)
 
+	password := spec.GetPassword()
 	req := &festruct.TGetMetaRequest{
 		User:   &spec.User,
-		Passwd: &spec.Password,
+		Passwd: &password,
 		Db:     reqDb,
 	}
 
@@ -880,10 +883,11 @@ func (rpc *singleFeClient) GetBackends(spec *base.Spec) (*festruct.TGetBackendMe
 	log.Debugf("GetBackends, addr: %s, spec: %s", rpc.Address(), spec)
 
 	client := rpc.client
+	password := spec.GetPassword()
 	req := &festruct.TGetBackendMetaRequest{
 		Cluster: &spec.Cluster,
 		User:    &spec.User,
-		Passwd:  &spec.Password,
+		Passwd:  &password,
 	}
 
 	if resp, err := client.GetBackendMeta(context.Background(), req); err != nil {
@@ -30,6 +30,10 @@ import (
 type IRpcFactory interface {
 	NewFeRpc(spec *base.Spec) (IFeRpc, error)
 	NewBeRpc(be *base.Backend) (IBeRpc, error)
+	// Remove the cached fe rpc of the spec, it is created again with the updated spec.
+	RemoveFeRpc(spec *base.Spec)
+	// Remove the cached be rpc of the backend, eg. the host of the backend is remapped.
+	RemoveBeRpc(be *base.Backend)
 }
 
 type RpcFactory struct {
@@ -71,6 +75,12 @@ func (rf *RpcFactory) NewFeRpc(spec *base.Spec) (IFeRpc, error) {
 	return feRpc, nil
 }
 
+func (rf *RpcFactory) RemoveFeRpc(spec *base.Spec) {
+	rf.feRpcsLock.Lock()
+	defer rf.feRpcsLock.Unlock()
+	delete(rf.feRpcs, spec)
+}
+
 func (rf *RpcFactory) NewBeRpc(be *base.Backend) (IBeRpc, error) {
 	rf.beRpcsLock.Lock()
 	if beRpc, ok := rf.beRpcs[*be]; ok {
@@ -96,3 +106,9 @@ func (rf *RpcFactory) NewBeRpc(be *base.Backend) (IBeRpc, error) {
 	rf.beRpcs[*be] = beRpc
 	return beRpc, nil
 }
+
+func (rf *RpcFactory) RemoveBeRpc(be *base.Backend) {
+	rf.beRpcsLock.Lock()
+	defer rf.beRpcsLock.Unlock()
+	delete(rf.beRpcs, *be)
+}
@@ -0,0 +1,657 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	apiV2Prefix = "/api/v2"
+
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// The error codes of the v2 api, the errors returned by the job manager are coded by the
+// xerror category, eg. RPC_ERROR.
+const (
+	CodeInvalidArgument  = "INVALID_ARGUMENT"
+	CodeJobNotFound      = "JOB_NOT_FOUND"
+	CodeNotFound         = "NOT_FOUND"
+	CodeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	CodeInternalError    = "INTERNAL_ERROR"
+)
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiErrorResult struct {
+	Error apiError `json:"error"`
+}
+
+// The http status and the code of the error.
+func errorStatus(err error) (int, string) {
+	var xerr *xerror.XError
+	if !errors.As(err, &xerr) {
+		return http.StatusInternalServerError, CodeInternalError
+	}
+
+	code := strings.ToUpper(xerr.Category().Name()) + "_ERROR"
+	switch xerr.Category() {
+	case xerror.Normal:
+		return http.StatusBadRequest, code
+	case xerror.RPC, xerror.FE, xerror.BE, xerror.Meta:
+		return http.StatusBadGateway, code
+	default:
+		return http.StatusInternalServerError, code
+	}
+}
+
+func writeApiJson(w http.ResponseWriter, status int, data any) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body, _ = json.Marshal(apiErrorResult{Error: apiError{Code: CodeInternalError, Message: err.Error()}})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func writeApiError(w http.ResponseWriter, status int, code string, message string) {
+	writeApiJson(w, status, apiErrorResult{Error: apiError{Code: code, Message: message}})
+}
+
+func writeApiErr(w http.ResponseWriter, err error) {
+	status, code := errorStatus(err)
+	writeApiError(w, status, code, err.Error())
+}
+
+// apiRoute is a route of the v2 api, the openapi document is generated from the routes.
+type apiRoute struct {
+	Method  string
+	Path    string // relative to /api/v2, the `{name}` segment is the job name
+	Role    Role
+	Summary string
+	Query   []apiQueryParam // the query parameters
+	Request any             // the request body, nil if no body
+	Status  int             // the status of success
+	Result  any             // the response body of success, nil if no body
+
+	handle  func(w http.ResponseWriter, r *http.Request, name string)
+	handler http.Handler
+}
+
+type apiQueryParam struct {
+	Name string
+	Type string // the openapi type, eg. integer
+}
+
+func (route *apiRoute) match(path string) (string, bool) {
+	patternSegments := strings.Split(strings.Trim(route.Path, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(segments) {
+		return "", false
+	}
+
+	var name string
+	for i, segment := range patternSegments {
+		if segment == "{name}" && segments[i] != "" {
+			name = segments[i]
+		} else if segment != segments[i] {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+type jobSummary struct {
+	Name     string `json:"name"`
+	SyncType string `json:"sync_type"`
+	State    string `json:"state"`
+	SrcDb    string `json:"src_database"`
+	SrcTable string `json:"src_table,omitempty"`
+	DestDb   string `json:"dest_database"`
+	DestTbl  string `json:"dest_table,omitempty"`
+}
+
+type listJobsResult struct {
+	Jobs       []jobSummary `json:"jobs"`
+	Total      int          `json:"total"`
+	NextOffset int          `json:"next_offset,omitempty"` // 0 if there is no more jobs
+	// The jobs of the page which can't be unmarshaled, they are skipped in jobs.
+	InvalidJobs []invalidJob `json:"invalid_jobs,omitempty"`
+}
+
+type invalidJob struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+type jobsLagResult struct {
+	Jobs []*ccr.JobLag `json:"jobs"`
+}
+
+type jobEventsResult struct {
+	Events []*ccr.JobEvent `json:"events"`
+}
+
+type pendingBinlogResult struct {
+	PendingBinlog *ccr.HeldBinlog `json:"pending_binlog,omitempty"`
+	Record        any             `json:"record,omitempty"`
+}
+
+type stopAtRequest struct {
+	CommitSeq int64 `json:"commit_seq"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+type skipBinlogRequest struct {
+	SkipCommitSeq int64  `json:"skip_commit_seq"`
+	SkipBy        string `json:"skip_by"`
+}
+
+type decideBinlogRequest struct {
+	CommitSeq int64  `json:"commit_seq"`
+	Operator  string `json:"operator"`
+}
+
+type hostMappingRequest struct {
+	SrcHostMapping  map[string]string `json:"src_host_mapping"`
+	DestHostMapping map[string]string `json:"dest_host_mapping"`
+}
+
+func (s *HttpService) apiRoutes() []*apiRoute {
+	action := func(name string, do func(jobName string) error) func(http.ResponseWriter, *http.Request, string) {
+		return func(w http.ResponseWriter, r *http.Request, jobName string) {
+			if s.locateJob(jobName, w, r) {
+				return
+			}
+			if err := do(jobName); err != nil {
+				log.Warnf("%s job %s failed: %+v", name, jobName, err)
+				writeApiErr(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+
+	return []*apiRoute{
+		{Method: http.MethodGet, Path: "/jobs", Role: RoleViewer, Summary: "List the jobs, paginated by offset and limit",
+			Query: []apiQueryParam{{"offset", "integer"}, {"limit", "integer"}}, Status: http.StatusOK, Result: listJobsResult{}, handle: s.apiListJobs},
+		{Method: http.MethodPost, Path: "/jobs", Role: RoleAdmin, Summary: "Create a job",
+			Request: CreateCcrRequest{}, Status: http.StatusCreated, Result: jobSummary{}, handle: s.apiCreateJob},
+		{Method: http.MethodGet, Path: "/lag", Role: RoleViewer, Summary: "Get the lag of all jobs of this syncer",
+			Status: http.StatusOK, Result: jobsLagResult{}, handle: s.apiListJobsLag},
+		{Method: http.MethodPost, Path: "/preflight", Role: RoleAdmin,
+			Summary: "Run the preflight checks of a job without creating it, same as creating with dry_run",
+			Request: CreateCcrRequest{}, Status: http.StatusOK, Result: ccr.PreflightReport{}, handle: s.apiPreflight},
+		{Method: http.MethodGet, Path: "/jobs/{name}", Role: RoleViewer, Summary: "Get the job, the passwords are redacted",
+			Status: http.StatusOK, Result: ccr.Job{}, handle: s.apiGetJob},
+		{Method: http.MethodPatch, Path: "/jobs/{name}", Role: RoleAdmin,
+			Summary: "Update the credentials, the frontends, the host mapping and the options of the job in place",
+			Request: ccr.JobUpdate{}, Status: http.StatusNoContent, handle: s.apiUpdateJob},
+		{Method: http.MethodDelete, Path: "/jobs/{name}", Role: RoleAdmin, Summary: "Delete the job",
+			Status: http.StatusNoContent, handle: action("delete", s.jobManager.RemoveJob)},
+		{Method: http.MethodGet, Path: "/jobs/{name}/status", Role: RoleViewer, Summary: "Get the status of the job",
+			Status: http.StatusOK, Result: ccr.JobStatus{}, handle: s.apiGetJobStatus},
+		{Method: http.MethodGet, Path: "/jobs/{name}/progress", Role: RoleViewer, Summary: "Get the progress of the job",
+			Status: http.StatusOK, Result: ccr.JobProgress{}, handle: s.apiGetJobProgress},
+		{Method: http.MethodGet, Path: "/jobs/{name}/lag", Role: RoleViewer,
+			Summary: "Get the lag of the job in commit seqs and seconds, per table for the db sync",
+			Status:  http.StatusOK, Result: ccr.JobLag{}, handle: s.apiGetJobLag},
+		{Method: http.MethodGet, Path: "/jobs/{name}/events", Role: RoleViewer,
+			Summary: "Get the events of the job, filtered by type, since and until (unix time in ms), the latest first",
+			Query:   []apiQueryParam{{"type", "string"}, {"since", "integer"}, {"until", "integer"}, {"limit", "integer"}},
+			Status:  http.StatusOK, Result: jobEventsResult{}, handle: s.apiGetJobEvents},
+		{Method: http.MethodGet, Path: "/jobs/{name}/pending_binlog", Role: RoleViewer,
+			Summary: "Get the binlog held by the ddl policy", Status: http.StatusOK, Result: pendingBinlogResult{},
+			handle: s.apiGetPendingBinlog},
+		{Method: http.MethodPost, Path: "/jobs/{name}/pause", Role: RoleOperator, Summary: "Pause the job",
+			Status: http.StatusNoContent, handle: action("pause", s.jobManager.Pause)},
+		{Method: http.MethodPost, Path: "/jobs/{name}/resume", Role: RoleOperator, Summary: "Resume the job",
+			Status: http.StatusNoContent, handle: action("resume", s.jobManager.Resume)},
+		{Method: http.MethodPost, Path: "/jobs/{name}/stop_at", Role: RoleOperator,
+			Summary: "Pause the job at the commit seq or the timestamp", Request: stopAtRequest{},
+			Status: http.StatusNoContent, handle: s.apiStopAt},
+		{Method: http.MethodPost, Path: "/jobs/{name}/approve_binlog", Role: RoleOperator,
+			Summary: "Approve the held binlog", Request: decideBinlogRequest{}, Status: http.StatusNoContent,
+			handle: s.apiDecideBinlog(ccr.BinlogApproved)},
+		{Method: http.MethodPost, Path: "/jobs/{name}/reject_binlog", Role: RoleOperator,
+			Summary: "Reject the held binlog", Request: decideBinlogRequest{}, Status: http.StatusNoContent,
+			handle: s.apiDecideBinlog(ccr.BinlogRejected)},
+		{Method: http.MethodPost, Path: "/jobs/{name}/desync", Role: RoleAdmin, Summary: "Desync the dest tables",
+			Status: http.StatusNoContent, handle: action("desync", s.jobManager.Desync)},
+		{Method: http.MethodPost, Path: "/jobs/{name}/force_fullsync", Role: RoleAdmin, Summary: "Force a full sync",
+			Status: http.StatusNoContent, handle: action("force fullsync", func(jobName string) error {
+				return s.jobManager.SkipBinlog(jobName, 0, ccr.SkipByFullSync)
+			})},
+		{Method: http.MethodPost, Path: "/jobs/{name}/skip_binlog", Role: RoleAdmin, Summary: "Skip the binlogs",
+			Request: skipBinlogRequest{}, Status: http.StatusNoContent, handle: s.apiSkipBinlog},
+		{Method: http.MethodPut, Path: "/jobs/{name}/host_mapping", Role: RoleAdmin,
+			Summary: "Update the host mapping of src and dest", Request: hostMappingRequest{},
+			Status: http.StatusNoContent, handle: s.apiUpdateHostMapping},
+		{Method: http.MethodGet, Path: "/openapi.json", Role: RoleViewer, Summary: "Get the openapi document",
+			Status: http.StatusOK, handle: s.apiOpenAPI},
+	}
+}
+
+func (s *HttpService) registerApiV2() {
+	s.apiV2Routes = s.apiRoutes()
+	for _, route := range s.apiV2Routes {
+		route := route
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name, _ := route.match(strings.TrimPrefix(r.URL.Path, apiV2Prefix))
+			route.handle(w, r, name)
+		})
+		route.handler = s.authorize(apiV2Prefix+route.Path, route.Role, handler)
+	}
+	s.mux.HandleFunc(apiV2Prefix+"/", s.apiV2Handler)
+}
+
+func (s *HttpService) apiV2Handler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, apiV2Prefix)
+
+	var allowed []string
+	for _, route := range s.apiV2Routes {
+		if _, ok := route.match(path); !ok {
+			continue
+		}
+		if route.Method == r.Method {
+			route.handler.ServeHTTP(w, r)
+			return
+		}
+		allowed = append(allowed, route.Method)
+	}
+
+	if len(allowed) == 0 {
+		writeApiError(w, http.StatusNotFound, CodeNotFound, fmt.Sprintf("no such api %s", r.URL.Path))
+		return
+	}
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeApiError(w, http.StatusMethodNotAllowed, CodeMethodNotAllowed,
+		fmt.Sprintf("method %s is not allowed, allowed: %s", r.Method, strings.Join(allowed, ", ")))
+}
+
+// Write the error if the job is not exist, or redirect (forward if authenticated) the request to
+// the syncer owning the job. Returns true if the request is handled.
+func (s *HttpService) locateJob(name string, w http.ResponseWriter, r *http.Request) bool {
+	if exist, err := s.db.IsJobExist(name); err != nil {
+		writeApiErr(w, err)
+		return true
+	} else if !exist {
+		writeApiError(w, http.StatusNotFound, CodeJobNotFound, fmt.Sprintf("job %s not exist", name))
+		return true
+	}
+
+	belongHost, err := s.db.GetJobBelong(name)
+	if err != nil {
+		writeApiErr(w, err)
+		return true
+	} else if belongHost == s.hostInfo {
+		return false
+	}
+
+	if s.auth != nil {
+		s.auth.forward(belongHost, w, r)
+		return true
+	}
+
+	// 307 keeps the method and the body.
+	redirectUrl := fmt.Sprintf("%s://%s%s", httpScheme(), belongHost, r.RequestURI)
+	log.Infof("job %s is located in syncer %s, redirect to %s", name, belongHost, redirectUrl)
+	http.Redirect(w, r, redirectUrl, http.StatusTemporaryRedirect)
+	return true
+}
+
+func decodeApiRequest(w http.ResponseWriter, r *http.Request, request any) bool {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func parseListParam(r *http.Request, key string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, xerror.Errorf(xerror.Normal, "invalid %s: %s", key, value)
+	}
+	return n, nil
+}
+
+func (s *HttpService) apiListJobs(w http.ResponseWriter, r *http.Request, _ string) {
+	offset, err := parseListParam(r, "offset", 0)
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+		return
+	}
+	limit, err := parseListParam(r, "limit", defaultListLimit)
+	if err != nil || limit == 0 || limit > maxListLimit {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument,
+			fmt.Sprintf("invalid limit %s, it should be in [1, %d]", r.URL.Query().Get("limit"), maxListLimit))
+		return
+	}
+
+	jobInfos, err := s.db.GetAllJobInfos()
+	if err != nil {
+		writeApiErr(w, err)
+		return
+	}
+	names := make([]string, 0, len(jobInfos))
+	for name := range jobInfos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := listJobsResult{Jobs: []jobSummary{}, Total: len(names)}
+	for i := offset; i < len(names) && i < offset+limit; i++ {
+		var job ccr.Job
+		if err := json.Unmarshal([]byte(jobInfos[names[i]]), &job); err != nil {
+			log.Warnf("list jobs, unmarshal job %s failed: %+v", names[i], err)
+			result.InvalidJobs = append(result.InvalidJobs, invalidJob{Name: names[i], Error: err.Error()})
+			continue
+		}
+		result.Jobs = append(result.Jobs, newJobSummary(&job))
+	}
+	if offset+limit < len(names) {
+		result.NextOffset = offset + limit
+	}
+	writeApiJson(w, http.StatusOK, result)
+}
+
+func newJobSummary(job *ccr.Job) jobSummary {
+	return jobSummary{
+		Name:     job.Name,
+		SyncType: job.SyncType.String(),
+		State:    job.State.String(),
+		SrcDb:    job.Src.Database,
+		SrcTable: job.Src.Table,
+		DestDb:   job.Dest.Database,
+		DestTbl:  job.Dest.Table,
+	}
+}
+
+func (s *HttpService) apiCreateJob(w http.ResponseWriter, r *http.Request, _ string) {
+	var request CreateCcrRequest
+	if !decodeApiRequest(w, r, &request) {
+		return
+	}
+	if request.Name == "" {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, "name is empty")
+		return
+	}
+	if request.DryRun {
+		writeApiJson(w, http.StatusOK, preflightCcr(&request, s.db, s.jobManager))
+		return
+	}
+
+	if err := createCcr(&request, s.db, s.jobManager); err != nil {
+		log.Warnf("create ccr failed: %+v", err)
+		writeApiErr(w, err)
+		return
+	}
+
+	job, err := s.getJobDetail(request.Name)
+	if err != nil {
+		writeApiErr(w, err)
+		return
+	}
+	writeApiJson(w, http.StatusCreated, newJobSummary(job))
+}
+
+func (s *HttpService) apiPreflight(w http.ResponseWriter, r *http.Request, _ string) {
+	var request CreateCcrRequest
+	if !decodeApiRequest(w, r, &request) {
+		return
+	}
+	if request.Name == "" {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, "name is empty")
+		return
+	}
+	writeApiJson(w, http.StatusOK, preflightCcr(&request, s.db, s.jobManager))
+}
+
+func (s *HttpService) apiListJobsLag(w http.ResponseWriter, r *http.Request, _ string) {
+	writeApiJson(w, http.StatusOK, jobsLagResult{Jobs: s.getJobsLag()})
+}
+
+func (s *HttpService) apiGetJobEvents(w http.ResponseWriter, r *http.Request, name string) {
+	query := r.URL.Query()
+	request := &JobEventsRequest{Name: name}
+	if types := query.Get("type"); types != "" {
+		request.Types = strings.Split(types, ",")
+	}
+
+	for _, param := range []struct {
+		key   string
+		value *int64
+	}{{"since", &request.Since}, {"until", &request.Until}} {
+		value, err := parseListParam(r, param.key, 0)
+		if err != nil {
+			writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+			return
+		}
+		*param.value = int64(value)
+	}
+	limit, err := parseListParam(r, "limit", defaultListLimit)
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+		return
+	}
+	request.Limit = limit
+
+	if events, err := s.getJobEvents(request); err != nil {
+		writeApiErr(w, err)
+	} else {
+		writeApiJson(w, http.StatusOK, jobEventsResult{Events: events})
+	}
+}
+
+func (s *HttpService) apiGetJob(w http.ResponseWriter, r *http.Request, name string) {
+	if s.locateJob(name, w, r) {
+		return
+	}
+	if job, err := s.getJobDetail(name); err != nil {
+		writeApiErr(w, err)
+	} else {
+		writeApiJson(w, http.StatusOK, job)
+	}
+}
+
+func (s *HttpService) apiGetJobStatus(w http.ResponseWriter, r *http.Request, name string) {
+	if s.locateJob(name, w, r) {
+		return
+	}
+	if status, err := s.jobManager.GetJobStatus(name); err != nil {
+		writeApiErr(w, err)
+	} else {
+		writeApiJson(w, http.StatusOK, status)
+	}
+}
+
+func (s *HttpService) apiGetJobProgress(w http.ResponseWriter, r *http.Request, name string) {
+	if s.locateJob(name, w, r) {
+		return
+	}
+	if progress, err := s.getJobProgress(name); err != nil {
+		writeApiErr(w, err)
+	} else {
+		progress.PersistData = ""
+		writeApiJson(w, http.StatusOK, progress)
+	}
+}
+
+func (s *HttpService) apiGetJobLag(w http.ResponseWriter, r *http.Request, name string) {
+	if s.locateJob(name, w, r) {
+		return
+	}
+	if lag, err := s.getJobLag(name); err != nil {
+		writeApiErr(w, err)
+	} else {
+		writeApiJson(w, http.StatusOK, lag)
+	}
+}
+
+func (s *HttpService) apiGetPendingBinlog(w http.ResponseWriter, r *http.Request, name string) {
+	if s.locateJob(name, w, r) {
+		return
+	}
+	if pendingBinlog, record, err := s.jobManager.GetPendingBinlog(name); err != nil {
+		writeApiErr(w, err)
+	} else {
+		writeApiJson(w, http.StatusOK, pendingBinlogResult{PendingBinlog: pendingBinlog, Record: record})
+	}
+}
+
+func (s *HttpService) apiStopAt(w http.ResponseWriter, r *http.Request, name string) {
+	var request stopAtRequest
+	if !decodeApiRequest(w, r, &request) {
+		return
+	}
+	if request.CommitSeq < 0 || request.Timestamp < 0 {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument,
+			fmt.Sprintf("invalid commit seq %d or timestamp %d", request.CommitSeq, request.Timestamp))
+		return
+	}
+	if s.locateJob(name, w, r) {
+		return
+	}
+
+	if err := s.jobManager.StopAt(name, request.CommitSeq, request.Timestamp); err != nil {
+		log.Warnf("stop at failed: %+v", err)
+		writeApiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *HttpService) apiDecideBinlog(decision string) func(http.ResponseWriter, *http.Request, string) {
+	return func(w http.ResponseWriter, r *http.Request, name string) {
+		var request decideBinlogRequest
+		if !decodeApiRequest(w, r, &request) {
+			return
+		}
+		operator := requestOperator(r, request.Operator)
+		if operator == "" {
+			writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, "operator is empty")
+			return
+		}
+		if s.locateJob(name, w, r) {
+			return
+		}
+
+		if err := s.jobManager.DecideHeldBinlog(name, request.CommitSeq, decision, operator); err != nil {
+			log.Warnf("%s binlog failed: %+v", decision, err)
+			writeApiErr(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *HttpService) apiSkipBinlog(w http.ResponseWriter, r *http.Request, name string) {
+	var request skipBinlogRequest
+	if !decodeApiRequest(w, r, &request) {
+		return
+	}
+	skipBy := strings.ToLower(request.SkipBy)
+	if skipBy != ccr.SkipBySilence && skipBy != ccr.SkipByFullSync {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, fmt.Sprintf("unknown skip way: %s", request.SkipBy))
+		return
+	}
+	if request.SkipCommitSeq <= 0 && skipBy != ccr.SkipByFullSync {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument,
+			fmt.Sprintf("commit seq is not specified for %s", request.SkipBy))
+		return
+	}
+	if s.locateJob(name, w, r) {
+		return
+	}
+
+	log.Infof("skip binlog with %s, commit seq %d, job %s", skipBy, request.SkipCommitSeq, name)
+	if err := s.jobManager.SkipBinlog(name, request.SkipCommitSeq, skipBy); err != nil {
+		log.Warnf("skip binlog failed: %+v", err)
+		writeApiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *HttpService) apiUpdateJob(w http.ResponseWriter, r *http.Request, name string) {
+	var request ccr.JobUpdate
+	if !decodeApiRequest(w, r, &request) {
+		return
+	}
+	if request.IsEmpty() {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, "nothing to update")
+		return
+	}
+	if s.locateJob(name, w, r) {
+		return
+	}
+
+	if err := s.jobManager.UpdateJob(name, &request); err != nil {
+		log.Warnf("update job failed: %+v", err)
+		writeApiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *HttpService) apiUpdateHostMapping(w http.ResponseWriter, r *http.Request, name string) {
+	var request hostMappingRequest
+	if !decodeApiRequest(w, r, &request) {
+		return
+	}
+	if len(request.SrcHostMapping) == 0 && len(request.DestHostMapping) == 0 {
+		writeApiError(w, http.StatusBadRequest, CodeInvalidArgument, "host_mapping is empty")
+		return
+	}
+	if s.locateJob(name, w, r) {
+		return
+	}
+
+	if err := s.jobManager.UpdateHostMapping(name, request.SrcHostMapping, request.DestHostMapping); err != nil {
+		log.Warnf("update host mapping failed: %+v", err)
+		writeApiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *HttpService) apiOpenAPI(w http.ResponseWriter, r *http.Request, _ string) {
+	writeApiJson(w, http.StatusOK, newOpenAPIDocument(s.apiV2Routes))
+}
@@ -0,0 +1,123 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr"
+	"github.com/selectdb/ccr_syncer/pkg/test_util"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApiRouteMatch(t *testing.T) {
+	route := &apiRoute{Method: http.MethodPost, Path: "/jobs/{name}/pause"}
+
+	if name, ok := route.match("/jobs/job1/pause"); !ok || name != "job1" {
+		t.Errorf("expect match job1, but got %s, %t", name, ok)
+	}
+	for _, path := range []string{"/jobs/job1", "/jobs//pause", "/jobs/job1/resume", "/jobs/job1/pause/x"} {
+		if _, ok := route.match(path); ok {
+			t.Errorf("expect %s not matched", path)
+		}
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	type TestCase struct {
+		err    error
+		status int
+		code   string
+	}
+	tests := []TestCase{
+		{err: xerror.Errorf(xerror.Normal, "invalid"), status: http.StatusBadRequest, code: "NORMAL_ERROR"},
+		{err: xerror.Wrap(xerror.New(xerror.Normal, "timeout"), xerror.RPC, "get binlog"), status: http.StatusBadGateway, code: "RPC_ERROR"},
+		{err: xerror.New(xerror.DB, "db is closed"), status: http.StatusInternalServerError, code: "DB_ERROR"},
+		{err: http.ErrServerClosed, status: http.StatusInternalServerError, code: CodeInternalError},
+	}
+	for i, test := range tests {
+		if status, code := errorStatus(test.err); status != test.status || code != test.code {
+			t.Errorf("test %d failed, expect %d %s, but got %d %s", i, test.status, test.code, status, code)
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	s := &HttpService{}
+	doc := newOpenAPIDocument(s.apiRoutes())
+
+	paths := doc["paths"].(map[string]any)
+	item, ok := paths["/api/v2/jobs/{name}/pause"].(map[string]any)
+	if !ok || item["post"] == nil {
+		t.Fatalf("the pause api is not in the document: %v", paths["/api/v2/jobs/{name}/pause"])
+	}
+	if op := item["post"].(map[string]any); op["operationId"] != "postJobsPause" {
+		t.Errorf("unexpected operation id %v", op["operationId"])
+	}
+
+	events := paths["/api/v2/jobs/{name}/events"].(map[string]any)["get"].(map[string]any)
+	params := make(map[string]bool)
+	for _, param := range events["parameters"].([]any) {
+		params[param.(map[string]any)["name"].(string)] = true
+	}
+	for _, name := range []string{"name", "type", "since", "until", "limit"} {
+		if !params[name] {
+			t.Errorf("the parameter %s of the events api is not declared: %v", name, params)
+		}
+	}
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	status, ok := schemas["CcrJobStatus"].(map[string]any)
+	if !ok {
+		t.Fatalf("the job status schema is not generated")
+	}
+	if _, ok := status["properties"].(map[string]any)["state"]; !ok {
+		t.Errorf("the state of the job status schema is missing: %v", status)
+	}
+}
+
+func TestApiListJobsSkipInvalid(t *testing.T) {
+	job, err := json.Marshal(&ccr.Job{Name: "ok", SyncType: ccr.TableSync})
+	if err != nil {
+		t.Fatalf("marshal job failed: %v", err)
+	}
+	ctrl := gomock.NewController(t)
+	db := test_util.NewMockDB(ctrl)
+	db.EXPECT().GetAllJobInfos().Return(map[string]string{"ok": string(job), "broken": "{"}, nil)
+
+	s := &HttpService{db: db}
+	w := httptest.NewRecorder()
+	s.apiListJobs(w, httptest.NewRequest(http.MethodGet, "/api/v2/jobs", nil), "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+	}
+
+	var result listJobsResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal result failed: %v", err)
+	}
+	if result.Total != 2 || len(result.Jobs) != 1 || result.Jobs[0].Name != "ok" {
+		t.Errorf("unexpected jobs: %+v", result)
+	}
+	if len(result.InvalidJobs) != 1 || result.InvalidJobs[0].Name != "broken" {
+		t.Errorf("unexpected invalid jobs: %+v", result.InvalidJobs)
+	}
+}
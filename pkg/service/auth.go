@@ -0,0 +1,345 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The request body is buffered to forward the request, the requests are small json documents.
+const maxRequestBodySize = 4 << 20
+
+var httpAuthConfigFile string
+
+func init() {
+	flag.StringVar(&httpAuthConfigFile, "http_auth_config", "",
+		"the json file of the http auth config, the http api is not authenticated if it is empty")
+}
+
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // read the jobs
+	RoleOperator Role = "operator" // pause, resume the jobs and decide the held binlogs
+	RoleAdmin    Role = "admin"    // create, delete the jobs, and all the other endpoints
+)
+
+func (r Role) level() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Whether the role has the privileges of the required role.
+func (r Role) Allows(required Role) bool {
+	return r.level() > 0 && r.level() >= required.level()
+}
+
+type Principal struct {
+	Name   string
+	Role   Role
+	Method string // token or cert
+}
+
+func (p *Principal) String() string {
+	return fmt.Sprintf("%s(%s, %s)", p.Name, p.Method, p.Role)
+}
+
+// Authenticator identifies the principal of the request, returns nil if the request carries
+// no credentials it knows, or an error if the credentials are invalid.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+type TokenConfig struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	Role  Role   `json:"role"`
+}
+
+type AuthConfig struct {
+	// The static bearer tokens, in the `Authorization: Bearer <token>` header.
+	Tokens []TokenConfig `json:"tokens,omitempty"`
+	// The common name of the verified client certificates => the role, requires TLS.
+	CertCommonNames map[string]Role `json:"cert_common_names,omitempty"`
+	// The token used to forward the requests to the syncer owning the job, if the request
+	// is not authenticated by a token. All syncers must accept it.
+	PeerToken string `json:"peer_token,omitempty"`
+}
+
+func (c *AuthConfig) Valid() error {
+	if len(c.Tokens) == 0 && len(c.CertCommonNames) == 0 {
+		return xerror.New(xerror.Normal, "no tokens or cert common names are configured")
+	}
+	for _, token := range c.Tokens {
+		if token.Token == "" {
+			return xerror.Errorf(xerror.Normal, "the token of %s is empty", token.Name)
+		}
+		if token.Role.level() == 0 {
+			return xerror.Errorf(xerror.Normal, "invalid role %s of token %s", token.Role, token.Name)
+		}
+	}
+	if c.PeerToken != "" && !c.hasToken(c.PeerToken) {
+		return xerror.New(xerror.Normal, "the peer token is not one of the tokens")
+	}
+	for name, role := range c.CertCommonNames {
+		if role.level() == 0 {
+			return xerror.Errorf(xerror.Normal, "invalid role %s of cert %s", role, name)
+		}
+	}
+	return nil
+}
+
+func (c *AuthConfig) hasToken(token string) bool {
+	for _, config := range c.Tokens {
+		if config.Token == token {
+			return true
+		}
+	}
+	return false
+}
+
+func loadAuthConfig(path string) (*AuthConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.Normal, "read http auth config %s failed", path)
+	}
+	var config AuthConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, xerror.Wrapf(err, xerror.Normal, "parse http auth config %s failed", path)
+	}
+	if err := config.Valid(); err != nil {
+		return nil, xerror.Wrapf(err, xerror.Normal, "http auth config %s is invalid", path)
+	}
+	return &config, nil
+}
+
+type tokenAuthenticator struct {
+	tokens []TokenConfig
+}
+
+func (a *tokenAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, nil
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return nil, xerror.New(xerror.Normal, "the authorization is not a bearer token")
+	}
+
+	for _, config := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(config.Token), []byte(token)) == 1 {
+			return &Principal{Name: config.Name, Role: config.Role, Method: "token"}, nil
+		}
+	}
+	return nil, xerror.New(xerror.Normal, "invalid bearer token")
+}
+
+type certAuthenticator struct {
+	commonNames map[string]Role
+}
+
+func (a *certAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	// The certificate chains are only set if the client certificate is verified.
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+
+	commonName := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	if role, ok := a.commonNames[commonName]; ok {
+		return &Principal{Name: commonName, Role: role, Method: "cert"}, nil
+	}
+	return nil, xerror.Errorf(xerror.Normal, "unknown client certificate %s", commonName)
+}
+
+type principalKey struct{}
+
+// The header carries the name of the principal authenticated by the syncer which forwards the
+// request with the peer token.
+const forwardedPrincipalHeader = "X-Ccr-Forwarded-Principal"
+
+func getPrincipal(r *http.Request) *Principal {
+	principal, _ := r.Context().Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// The operator of the request is the authenticated principal, the operator of the request body
+// is only used if the http api is not authenticated.
+func requestOperator(r *http.Request, operator string) string {
+	if principal := getPrincipal(r); principal != nil {
+		return principal.Name
+	}
+	return operator
+}
+
+type httpAuth struct {
+	authenticators []Authenticator
+	peerToken      string
+}
+
+func newHttpAuth(config *AuthConfig) *httpAuth {
+	auth := &httpAuth{peerToken: config.PeerToken}
+	if len(config.Tokens) > 0 {
+		auth.authenticators = append(auth.authenticators, &tokenAuthenticator{tokens: config.Tokens})
+	}
+	if len(config.CertCommonNames) > 0 {
+		auth.authenticators = append(auth.authenticators, &certAuthenticator{commonNames: config.CertCommonNames})
+	}
+	return auth
+}
+
+func (a *httpAuth) authenticate(r *http.Request) (*Principal, error) {
+	for _, authenticator := range a.authenticators {
+		if principal, err := authenticator.Authenticate(r); err != nil || principal != nil {
+			return principal, err
+		}
+	}
+	return nil, xerror.New(xerror.Normal, "no credentials")
+}
+
+func (a *httpAuth) isPeerRequest(r *http.Request) bool {
+	return a.peerToken != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+a.peerToken)) == 1
+}
+
+// Wrap the handler to require the role, the request body is buffered so that it can be
+// forwarded to the syncer owning the job.
+func (a *httpAuth) wrap(pattern string, required Role, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.authenticate(r)
+		if err != nil {
+			log.Warnf("http auth denied, uri: %s, remote: %s, err: %v", r.RequestURI, r.RemoteAddr, err)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !principal.Role.Allows(required) {
+			log.Warnf("http auth denied, uri: %s, remote: %s, principal: %s, required role: %s",
+				r.RequestURI, r.RemoteAddr, principal, required)
+			http.Error(w, fmt.Sprintf("%s requires role %s", pattern, required), http.StatusForbidden)
+			return
+		}
+		if name := r.Header.Get(forwardedPrincipalHeader); name != "" && a.isPeerRequest(r) {
+			principal = &Principal{Name: name, Role: principal.Role, Method: "peer"}
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			} else {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+
+		log.Debugf("http auth accepted, uri: %s, principal: %s", r.RequestURI, principal)
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	})
+}
+
+// WrapAdmin requires the admin role for the handler served out of the http service, eg. pprof.
+// The handler is returned as is if the http api is not authenticated.
+func WrapAdmin(pattern string, handler http.Handler) (http.Handler, error) {
+	if httpAuthConfigFile == "" {
+		return handler, nil
+	}
+	config, err := loadAuthConfig(httpAuthConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	return newHttpAuth(config).wrap(pattern, RoleAdmin, handler), nil
+}
+
+// Forward the request to the syncer owning the job. Clients drop the credentials when
+// following a redirect to another host, so the request is forwarded instead.
+func (a *httpAuth) forward(host string, w http.ResponseWriter, r *http.Request) {
+	var body io.ReadCloser = http.NoBody
+	if r.GetBody != nil {
+		if b, err := r.GetBody(); err == nil {
+			body = b
+		}
+	}
+
+	forwardUrl := fmt.Sprintf("%s://%s%s", httpScheme(), host, r.RequestURI)
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, forwardUrl, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Del(forwardedPrincipalHeader)
+	if principal := getPrincipal(r); principal == nil || principal.Method != "token" {
+		if a.peerToken == "" {
+			http.Error(w, "the peer token is required to forward the request to "+host, http.StatusForbidden)
+			return
+		}
+		req.Header.Set("Authorization", "Bearer "+a.peerToken)
+		if principal != nil {
+			req.Header.Set(forwardedPrincipalHeader, principal.Name)
+		}
+	}
+
+	client, err := newPeerHttpClient()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	log.Infof("forward the request %s to syncer %s", r.RequestURI, host)
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Warnf("forward the request %s to syncer %s failed: %+v", r.RequestURI, host, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
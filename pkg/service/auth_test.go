@@ -0,0 +1,148 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHttpAuthWrap(t *testing.T) {
+	auth := newHttpAuth(&AuthConfig{
+		Tokens: []TokenConfig{
+			{Name: "viewer", Token: "viewer-token", Role: RoleViewer},
+			{Name: "admin", Token: "admin-token", Role: RoleAdmin},
+		},
+	})
+	handler := auth.wrap("/pause", RoleOperator, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal := getPrincipal(r); principal == nil || principal.Name != "admin" {
+			t.Errorf("unexpected principal: %v", principal)
+		}
+	}))
+
+	type TestCase struct {
+		authorization string
+		status        int
+	}
+	tests := []TestCase{
+		{authorization: "", status: http.StatusUnauthorized},
+		{authorization: "Basic YWRtaW46", status: http.StatusUnauthorized},
+		{authorization: "Bearer unknown-token", status: http.StatusUnauthorized},
+		{authorization: "Bearer viewer-token", status: http.StatusForbidden},
+		{authorization: "Bearer admin-token", status: http.StatusOK},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/pause", nil)
+		if test.authorization != "" {
+			req.Header.Set("Authorization", test.authorization)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != test.status {
+			t.Errorf("test %d failed, authorization: %s, expect status %d, but got %d",
+				i, test.authorization, test.status, recorder.Code)
+		}
+	}
+}
+
+func TestHttpAuthWrapBodyTooLarge(t *testing.T) {
+	auth := newHttpAuth(&AuthConfig{
+		Tokens: []TokenConfig{{Name: "admin", Token: "admin-token", Role: RoleAdmin}},
+	})
+	handler := auth.wrap("/create_ccr", RoleAdmin, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, test := range []struct {
+		size   int
+		status int
+	}{
+		{size: maxRequestBodySize, status: http.StatusOK},
+		{size: maxRequestBodySize + 1, status: http.StatusRequestEntityTooLarge},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/create_ccr", strings.NewReader(strings.Repeat("x", test.size)))
+		req.Header.Set("Authorization", "Bearer admin-token")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != test.status {
+			t.Errorf("body size %d, expect status %d, but got %d", test.size, test.status, recorder.Code)
+		}
+	}
+}
+
+func TestRequestOperator(t *testing.T) {
+	auth := newHttpAuth(&AuthConfig{
+		Tokens: []TokenConfig{
+			{Name: "alice", Token: "alice-token", Role: RoleOperator},
+			{Name: "peer", Token: "peer-token", Role: RoleAdmin},
+		},
+		PeerToken: "peer-token",
+	})
+	var operator string
+	handler := auth.wrap("/approve_binlog", RoleOperator, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operator = requestOperator(r, "mallory")
+	}))
+
+	type TestCase struct {
+		authorization string
+		forwarded     string
+		operator      string
+	}
+	tests := []TestCase{
+		{authorization: "Bearer alice-token", operator: "alice"},
+		{authorization: "Bearer alice-token", forwarded: "bob", operator: "alice"},
+		{authorization: "Bearer peer-token", operator: "peer"},
+		{authorization: "Bearer peer-token", forwarded: "bob", operator: "bob"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/approve_binlog", nil)
+		req.Header.Set("Authorization", test.authorization)
+		if test.forwarded != "" {
+			req.Header.Set(forwardedPrincipalHeader, test.forwarded)
+		}
+		operator = ""
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if operator != test.operator {
+			t.Errorf("test %d failed, expect operator %s, but got %s", i, test.operator, operator)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/approve_binlog", nil)
+	if operator := requestOperator(req, "alice"); operator != "alice" {
+		t.Errorf("the operator of the body should be used without auth, but got %s", operator)
+	}
+}
+
+func TestAuthConfigValid(t *testing.T) {
+	config := &AuthConfig{
+		Tokens:    []TokenConfig{{Name: "admin", Token: "admin-token", Role: RoleAdmin}},
+		PeerToken: "admin-token",
+	}
+	if err := config.Valid(); err != nil {
+		t.Errorf("expect valid, but got %v", err)
+	}
+
+	config.PeerToken = "unknown-token"
+	if err := config.Valid(); err == nil {
+		t.Errorf("expect invalid peer token")
+	}
+
+	config = &AuthConfig{Tokens: []TokenConfig{{Name: "root", Token: "root-token", Role: "root"}}}
+	if err := config.Valid(); err == nil {
+		t.Errorf("expect invalid role")
+	}
+}
@@ -0,0 +1,190 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package service
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/ccr"
+	"github.com/selectdb/ccr_syncer/pkg/ccr/base"
+)
+
+var persistFailureUnready time.Duration
+
+func init() {
+	flag.DurationVar(&persistFailureUnready, "persist_failure_unready", 5*time.Minute,
+		"the syncer is not ready if a job fails to persist the progress longer than it, 0 means never")
+}
+
+const (
+	metaDBPingTimeout = 3 * time.Second
+	// The jobs connectivity is checked in background in the interval, the probes only read the
+	// cached result, since the check might take long if the clusters are unreachable.
+	jobsConnectivityCheckDuration = 30 * time.Second
+)
+
+type ComponentStatus struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ReadinessResult is the result of /readyz. The syncer is ready if the meta db is reachable, the
+// checker heartbeat is fresh, no job fails to persist the progress for long and the http service
+// is not stopping. The jobs connectivity and the credentials are only informational, an unreachable
+// cluster should not take the syncer out of service.
+type ReadinessResult struct {
+	Ready   bool               `json:"ready"`
+	MetaDB  ComponentStatus    `json:"meta_db"`
+	Checker *ccr.CheckerStatus `json:"checker,omitempty"`
+	Http    ComponentStatus    `json:"http"`
+	Persist ComponentStatus    `json:"persist"`
+	// Not ok if the passwords of the jobs are persisted in plaintext.
+	Credentials ComponentStatus `json:"credentials"`
+
+	Jobs          []*ccr.JobConnectivity `json:"jobs"`
+	JobsCheckedAt int64                  `json:"jobs_checked_at"`
+}
+
+type jobsConnectivityCache struct {
+	lock      sync.Mutex
+	jobs      []*ccr.JobConnectivity
+	checkedAt int64
+}
+
+// get returns the last checked jobs connectivity, the checked time is 0 if not checked yet.
+func (c *jobsConnectivityCache) get() ([]*ccr.JobConnectivity, int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.jobs == nil {
+		return []*ccr.JobConnectivity{}, c.checkedAt
+	}
+	return c.jobs, c.checkedAt
+}
+
+func (c *jobsConnectivityCache) refresh(jobManager *ccr.JobManager) {
+	jobs := jobManager.CheckConnectivity()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.jobs = jobs
+	c.checkedAt = time.Now().UnixMilli()
+}
+
+func (c *jobsConnectivityCache) run(jobManager *ccr.JobManager, stop <-chan struct{}) {
+	ticker := time.NewTicker(jobsConnectivityCheckDuration)
+	defer ticker.Stop()
+
+	c.refresh(jobManager)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.refresh(jobManager)
+		}
+	}
+}
+
+// liveness probe, the process is alive if it could serve the request.
+func (s *HttpService) healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, map[string]string{"status": "ok"})
+}
+
+// readiness probe, responds 503 if the syncer is not ready.
+func (s *HttpService) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	result := s.readiness()
+
+	// The errors might contain the addresses of the clusters, only show them if the http api is
+	// not authenticated.
+	if s.auth != nil {
+		result.MetaDB.Error = ""
+		result.Http.Error = ""
+		result.Persist.Error = ""
+		result.Credentials.Error = ""
+		if result.Checker != nil {
+			checker := *result.Checker
+			checker.LastError = ""
+			result.Checker = &checker
+		}
+		jobs := make([]*ccr.JobConnectivity, 0, len(result.Jobs))
+		for _, job := range result.Jobs {
+			job := *job
+			job.SrcError = ""
+			job.DestError = ""
+			jobs = append(jobs, &job)
+		}
+		result.Jobs = jobs
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !result.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	writeJson(w, result)
+}
+
+func (s *HttpService) readiness() *ReadinessResult {
+	result := &ReadinessResult{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metaDBPingTimeout)
+	defer cancel()
+	if err := s.db.Ping(ctx); err != nil {
+		result.MetaDB.Error = err.Error()
+	} else {
+		result.MetaDB.OK = true
+	}
+
+	checkerFresh := false
+	if s.checker != nil {
+		result.Checker = s.checker.Status()
+		checkerFresh = result.Checker.Fresh
+	}
+
+	if s.stopping.Load() {
+		result.Http.Error = "http service is stopping"
+	} else {
+		result.Http.OK = true
+	}
+
+	result.Persist.OK = true
+	if persistFailureUnready > 0 {
+		if jobs := ccr.PersistFailingJobs(time.Now().Add(-persistFailureUnready)); len(jobs) > 0 {
+			result.Persist.OK = false
+			result.Persist.Error = fmt.Sprintf("jobs %s fail to persist the progress for more than %s",
+				strings.Join(jobs, ", "), persistFailureUnready)
+		}
+	}
+
+	if hasKey, err := base.HasCredentialKey(); err != nil {
+		result.Credentials.Error = err.Error()
+	} else if !hasKey {
+		result.Credentials.Error = "no credential key, the passwords are persisted in plaintext"
+	} else {
+		result.Credentials.OK = true
+	}
+
+	result.Jobs, result.JobsCheckedAt = s.jobsConnectivity.get()
+	result.Ready = result.MetaDB.OK && checkerFresh && result.Persist.OK && result.Http.OK
+	return result
+}
@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/selectdb/ccr_syncer/pkg/ccr"
@@ -75,12 +77,20 @@ type HttpService struct {
 	server   *http.Server
 	mux      *http.ServeMux
 	hostInfo string
+	auth     *httpAuth // nil if the http api is not authenticated
+
+	apiV2Routes []*apiRoute
 
 	db         storage.DB
 	jobManager *ccr.JobManager
+	checker    *ccr.Checker
+
+	stopping         atomic.Bool
+	stop             chan struct{}
+	jobsConnectivity jobsConnectivityCache
 }
 
-func NewHttpServer(host string, port int, db storage.DB, jobManager *ccr.JobManager) *HttpService {
+func NewHttpServer(host string, port int, db storage.DB, jobManager *ccr.JobManager, checker *ccr.Checker) *HttpService {
 	return &HttpService{
 		port:     port,
 		mux:      http.NewServeMux(),
@@ -88,6 +98,8 @@ func NewHttpServer(host string, port int, db storage.DB, jobManager *ccr.JobMana
 
 		db:         db,
 		jobManager: jobManager,
+		checker:    checker,
+		stop:       make(chan struct{}),
 	}
 }
 
@@ -100,6 +112,26 @@ type CreateCcrRequest struct {
 	// For table sync, allow to create ccr job even if the target table already exists.
 	AllowTableExists bool `json:"allow_table_exists"`
 	ReuseBinlogLabel bool `json:"reuse_binlog_label"`
+	// For db sync, only sync the tables matched by the filter.
+	TableFilter *ccr.TableFilter `json:"table_filter,omitempty"`
+	// For db sync, rename the dest tables, eg. `orders` -> `dr_orders`.
+	TableRename *ccr.TableRenameRule `json:"table_rename,omitempty"`
+	// For db sync, the dest tables owned by this job, required if several jobs sync into the same dest database.
+	OwnedTables *ccr.TableOwnership `json:"owned_tables,omitempty"`
+	// The jobs in the same fanout group read the binlogs of the same src once, and share the full sync snapshots.
+	FanoutGroup string `json:"fanout_group,omitempty"`
+	// For db sync, replicate in both directions with an opposite job.
+	Bidirectional bool `json:"bidirectional,omitempty"`
+	// For bidirectional sync, the src tables written in this direction.
+	WriteTables *ccr.TableOwnership `json:"write_tables,omitempty"`
+	// Keep the dest behind the src, only sync the binlogs older than the delay (in seconds).
+	ApplyDelaySeconds int64 `json:"apply_delay_seconds,omitempty"`
+	// Apply, ignore or hold the ddl binlogs by the binlog type.
+	DDLPolicy *ccr.DDLPolicy `json:"ddl_policy,omitempty"`
+	// Only sync the rows matched by the predicates, the filtered tables are reloaded via the catalog of dest.
+	RowFilter *ccr.RowFilter `json:"row_filter,omitempty"`
+	// Only run the preflight checks and return the report, the job is not created.
+	DryRun bool `json:"dry_run,omitempty"`
 }
 
 // Stringer
@@ -129,17 +161,7 @@ func (s *HttpService) versionHandler(w http.ResponseWriter, r *http.Request) {
 func createCcr(request *CreateCcrRequest, db storage.DB, jobManager *ccr.JobManager) error {
 	log.Infof("create ccr %s", request)
 
-	ctx := &ccr.JobContext{
-		Context:          context.Background(),
-		Src:              request.Src,
-		Dest:             request.Dest,
-		SkipError:        request.SkipError,
-		AllowTableExists: request.AllowTableExists,
-		ReuseBinlogLabel: request.ReuseBinlogLabel,
-		Db:               db,
-		Factory:          jobManager.GetFactory(),
-	}
-	job, err := ccr.NewJobFromService(request.Name, ctx)
+	job, err := newJobFromRequest(request, db, jobManager)
 	if err != nil {
 		return err
 	}
@@ -153,6 +175,47 @@ func createCcr(request *CreateCcrRequest, db storage.DB, jobManager *ccr.JobMana
 	return nil
 }
 
+// preflightCcr runs the preflight checks of the job without creating it.
+func preflightCcr(request *CreateCcrRequest, db storage.DB, jobManager *ccr.JobManager) *ccr.PreflightReport {
+	log.Infof("preflight ccr %s", request)
+
+	// The job is invalid if the name exists, so check it before the config.
+	if exist, err := db.IsJobExist(request.Name); err != nil {
+		return ccr.NewFailedPreflightReport("job_name", err)
+	} else if exist {
+		return ccr.NewFailedPreflightReport("job_name", xerror.Errorf(xerror.Normal, "job %s already exist", request.Name))
+	}
+
+	job, err := newJobFromRequest(request, db, jobManager)
+	if err != nil {
+		return ccr.NewFailedPreflightReport("config", err)
+	}
+	return jobManager.Preflight(job)
+}
+
+func newJobFromRequest(request *CreateCcrRequest, db storage.DB, jobManager *ccr.JobManager) (*ccr.Job, error) {
+	ctx := &ccr.JobContext{
+		Context:           context.Background(),
+		Src:               request.Src,
+		Dest:              request.Dest,
+		SkipError:         request.SkipError,
+		AllowTableExists:  request.AllowTableExists,
+		ReuseBinlogLabel:  request.ReuseBinlogLabel,
+		TableFilter:       request.TableFilter,
+		TableRename:       request.TableRename,
+		OwnedTables:       request.OwnedTables,
+		FanoutGroup:       request.FanoutGroup,
+		Bidirectional:     request.Bidirectional,
+		WriteTables:       request.WriteTables,
+		ApplyDelaySeconds: request.ApplyDelaySeconds,
+		DDLPolicy:         request.DDLPolicy,
+		RowFilter:         request.RowFilter,
+		Db:                db,
+		Factory:           jobManager.GetFactory(),
+	}
+	return ccr.NewJobFromService(request.Name, ctx)
+}
+
 // return exit(bool)
 func (s *HttpService) redirect(jobName string, w http.ResponseWriter, r *http.Request) bool {
 	if jobExist, err := s.db.IsJobExist(jobName); err != nil {
@@ -179,8 +242,13 @@ func (s *HttpService) redirect(jobName string, w http.ResponseWriter, r *http.Re
 		return false
 	}
 
+	if s.auth != nil {
+		s.auth.forward(belongHost, w, r)
+		return true
+	}
+
 	log.Infof("%s is located in syncer %s, please redirect to %s", jobName, belongHost, belongHost)
-	redirectUrl := fmt.Sprintf("http://%s", belongHost+r.RequestURI)
+	redirectUrl := fmt.Sprintf("%s://%s", httpScheme(), belongHost+r.RequestURI)
 	http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
 	log.Infof("the redirect url is %s", redirectUrl)
 	return true
@@ -202,6 +270,11 @@ func (s *HttpService) createHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if request.DryRun {
+		s.writePreflightResult(w, &request)
+		return
+	}
+
 	// Call the createCcr function to create the CCR
 	if err = createCcr(&request, s.db, s.jobManager); err != nil {
 		log.Warnf("create ccr failed: %+v", err)
@@ -211,6 +284,20 @@ func (s *HttpService) createHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *HttpService) writePreflightResult(w http.ResponseWriter, request *CreateCcrRequest) {
+	type result struct {
+		*defaultResult
+		Report *ccr.PreflightReport `json:"report"`
+	}
+
+	report := preflightCcr(request, s.db, s.jobManager)
+	preflightResult := &result{defaultResult: newSuccessResult(), Report: report}
+	if !report.Passed {
+		preflightResult.defaultResult = newErrorResult("preflight checks failed")
+	}
+	writeJson(w, preflightResult)
+}
+
 type CcrCommonRequest struct {
 	// must need all fields required
 	Name string `json:"name,required"`
@@ -222,7 +309,7 @@ func (s *HttpService) getLagHandler(w http.ResponseWriter, r *http.Request) {
 
 	type result struct {
 		*defaultResult
-		Lag int64 `json:"lag"`
+		*ccr.JobLag
 	}
 	var lagResult *result
 	defer func() { writeJson(w, lagResult) }()
@@ -248,71 +335,154 @@ func (s *HttpService) getLagHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var job ccr.Job
-	var jobProgress ccr.JobProgress
-
-	jobInfo, err := s.db.GetJobInfo(request.Name)
+	lag, err := s.getJobLag(request.Name)
 	if err != nil {
-		log.Warnf("db get job info failed: %+v", err)
+		log.Warnf("get lag failed: %+v", err)
 		lagResult = &result{
 			defaultResult: newErrorResult(err.Error()),
 		}
 		return
 	}
 
-	err = json.Unmarshal([]byte(jobInfo), &job)
-	if err != nil {
-		log.Warnf("unmarshal get job info failed: %+v", err)
-		lagResult = &result{
-			defaultResult: newErrorResult(err.Error()),
-		}
-		return
+	lagResult = &result{
+		defaultResult: newSuccessResult(),
+		JobLag:        lag,
 	}
+}
+
+// Get the lag of all jobs of this syncer, the failed jobs have the error message.
+func (s *HttpService) listJobsLagHandler(w http.ResponseWriter, r *http.Request) {
+	log.Infof("list jobs lag")
 
-	jobProgressData, err := s.db.GetProgress(request.Name)
+	type result struct {
+		*defaultResult
+		Jobs []*ccr.JobLag `json:"jobs"`
+	}
+	writeJson(w, &result{
+		defaultResult: newSuccessResult(),
+		Jobs:          s.getJobsLag(),
+	})
+}
+
+// Get the lag of the job, by the commit seq of the persisted progress.
+func (s *HttpService) getJobLag(name string) (*ccr.JobLag, error) {
+	jobInfo, err := s.db.GetJobInfo(name)
 	if err != nil {
-		log.Warnf("db get job progress failed: %+v", err)
-		lagResult = &result{
-			defaultResult: newErrorResult(err.Error()),
-		}
-		return
+		return nil, err
+	}
+
+	var job ccr.Job
+	if err := json.Unmarshal([]byte(jobInfo), &job); err != nil {
+		return nil, xerror.Wrap(err, xerror.Normal, "unmarshal job info failed")
 	}
 
-	err = json.Unmarshal([]byte(jobProgressData), &jobProgress)
+	jobProgress, err := s.getJobProgress(name)
 	if err != nil {
-		log.Warnf("unmarshal get job progress failed: %+v", err)
-		lagResult = &result{
-			defaultResult: newErrorResult(err.Error()),
+		return nil, err
+	}
+
+	return s.jobManager.GetJobLag(&job, jobProgress)
+}
+
+func (s *HttpService) getJobsLag() []*ccr.JobLag {
+	statuses := s.jobManager.ListJobs()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	jobsLag := make([]*ccr.JobLag, 0, len(statuses))
+	for _, status := range statuses {
+		lag, err := s.getJobLag(status.Name)
+		if err != nil {
+			log.Warnf("get lag of job %s failed: %+v", status.Name, err)
+			lag = &ccr.JobLag{Name: status.Name, Error: err.Error()}
 		}
+		jobsLag = append(jobsLag, lag)
+	}
+	return jobsLag
+}
+
+type JobEventsRequest struct {
+	Name string `json:"name,required"`
+	// Only the events of the types, all types if it is empty.
+	Types []string `json:"types,omitempty"`
+	// The time range of the events, unix time in milliseconds, [since, until).
+	Since int64 `json:"since,omitempty"`
+	Until int64 `json:"until,omitempty"`
+	Limit int   `json:"limit,omitempty"`
+}
+
+// The events are kept in the db after the job is deleted, until they are expired.
+func (s *HttpService) getJobEvents(request *JobEventsRequest) ([]*ccr.JobEvent, error) {
+	if request.Limit < 0 || request.Limit > maxListLimit {
+		return nil, xerror.Errorf(xerror.Normal, "invalid limit %d, it should be in [1, %d]", request.Limit, maxListLimit)
+	}
+
+	filter := &storage.JobEventFilter{
+		EventTypes: request.Types,
+		Since:      request.Since,
+		Until:      request.Until,
+		Limit:      request.Limit,
+	}
+	return ccr.GetJobEvents(s.db, request.Name, filter)
+}
+
+func (s *HttpService) jobEventsHandler(w http.ResponseWriter, r *http.Request) {
+	log.Infof("get job events")
+
+	type result struct {
+		*defaultResult
+		Events []*ccr.JobEvent `json:"events"`
+	}
+	var jobEventsResult *result
+	defer func() { writeJson(w, jobEventsResult) }()
+
+	var request JobEventsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Warnf("get job events failed: %+v", err)
+		jobEventsResult = &result{defaultResult: newErrorResult(err.Error())}
 		return
 	}
 
-	srcSpec := &job.Src
-	rpc, err := s.jobManager.GetFactory().NewFeRpc(srcSpec)
-	if err != nil {
-		log.Warnf("new fe rpc failed: %+v", err)
-		lagResult = &result{
-			defaultResult: newErrorResult(err.Error()),
-		}
+	if request.Name == "" {
+		log.Warnf("get job events failed: name is empty")
+		jobEventsResult = &result{defaultResult: newErrorResult("name is empty")}
 		return
 	}
 
-	commitSeq := jobProgress.CommitSeq
-	resp, err := rpc.GetBinlogLag(srcSpec, commitSeq)
+	if events, err := s.getJobEvents(&request); err != nil {
+		log.Warnf("get job events failed: %+v", err)
+		jobEventsResult = &result{defaultResult: newErrorResult(err.Error())}
+	} else {
+		jobEventsResult = &result{defaultResult: newSuccessResult(), Events: events}
+	}
+}
+
+func (s *HttpService) getJobProgress(name string) (*ccr.JobProgress, error) {
+	jobProgressData, err := s.db.GetProgress(name)
 	if err != nil {
-		log.Warnf("rpc get bin log failed: %+v", err)
-		lagResult = &result{
-			defaultResult: newErrorResult(err.Error()),
-		}
-		return
+		return nil, err
 	}
 
-	lag := resp.GetLag()
+	var jobProgress ccr.JobProgress
+	if err := json.Unmarshal([]byte(jobProgressData), &jobProgress); err != nil {
+		return nil, xerror.Wrap(err, xerror.Normal, "unmarshal job progress failed")
+	}
+	return &jobProgress, nil
+}
 
-	lagResult = &result{
-		defaultResult: newSuccessResult(),
-		Lag:           lag,
+// Get the persisted job, the passwords are redacted.
+func (s *HttpService) getJobDetail(name string) (*ccr.Job, error) {
+	jobInfo, err := s.db.GetJobInfo(name)
+	if err != nil {
+		return nil, err
 	}
+
+	var job ccr.Job
+	if err := json.Unmarshal([]byte(jobInfo), &job); err != nil {
+		return nil, xerror.Wrap(err, xerror.Normal, "unmarshal job info failed")
+	}
+	job.Src.Redact()
+	job.Dest.Redact()
+	return &job, nil
 }
 
 // Pause service
@@ -584,28 +754,18 @@ func (s *HttpService) jobProgressHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if jobProgressData, err := s.db.GetProgress(request.Name); err != nil {
+	if jobProgress, err := s.getJobProgress(request.Name); err != nil {
 		log.Warnf("get job progress failed: %+v", err)
 		jobResult = &result{
 			defaultResult: newErrorResult(err.Error()),
 		}
 	} else {
-		var jobProgress ccr.JobProgress
-		err := json.Unmarshal([]byte(jobProgressData), &jobProgress)
-		if err != nil {
-			log.Warnf("unmarshal get job progress error")
-			jobResult = &result{
-				defaultResult: newErrorResult(err.Error()),
-			}
-			return
-		}
 		jobProgress.PersistData = ""
 		jobResult = &result{
 			defaultResult: newSuccessResult(),
-			JobProgress:   jobProgress,
+			JobProgress:   *jobProgress,
 		}
 	}
-
 }
 
 // get job details
@@ -645,21 +805,15 @@ func (s *HttpService) jobDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var jobDetail ccr.Job
-	if jobDetailStr, err := s.db.GetJobInfo(request.Name); err != nil {
+	if jobDetail, err := s.getJobDetail(request.Name); err != nil {
 		log.Warnf("get job info failed: %+v", err)
 		jobResult = &result{
 			defaultResult: newErrorResult(err.Error()),
 		}
-	} else if err = json.Unmarshal([]byte(jobDetailStr), &jobDetail); err != nil {
-		log.Warnf("unmarshal job info failed: %+v", err)
-		jobResult = &result{
-			defaultResult: newErrorResult(err.Error()),
-		}
 	} else {
 		jobResult = &result{
 			defaultResult: newSuccessResult(),
-			JobDetail:     &jobDetail,
+			JobDetail:     jobDetail,
 		}
 	}
 }
@@ -769,6 +923,48 @@ func (s *HttpService) updateHostMappingHandler(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// Update the credentials, the frontends, the host mapping and the options of the job in place.
+func (s *HttpService) updateJobHandler(w http.ResponseWriter, r *http.Request) {
+	log.Infof("update job")
+
+	var result *defaultResult
+	defer func() { writeJson(w, result) }()
+
+	var request struct {
+		CcrCommonRequest
+		ccr.JobUpdate
+	}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		log.Warnf("update job failed: %+v", err)
+		result = newErrorResult(err.Error())
+		return
+	}
+
+	if request.Name == "" {
+		log.Warnf("update job failed: name is empty")
+		result = newErrorResult("name is empty")
+		return
+	}
+
+	if request.JobUpdate.IsEmpty() {
+		log.Warnf("update job failed: nothing to update")
+		result = newErrorResult("nothing to update")
+		return
+	}
+
+	if s.redirect(request.Name, w, r) {
+		return
+	}
+
+	if err := s.jobManager.UpdateJob(request.Name, &request.JobUpdate); err != nil {
+		log.Warnf("update job failed: %+v", err)
+		result = newErrorResult(err.Error())
+	} else {
+		result = newSuccessResult()
+	}
+}
+
 func (s *HttpService) skipBinlogHandler(w http.ResponseWriter, r *http.Request) {
 	var result *defaultResult
 	defer func() { writeJson(w, result) }()
@@ -821,6 +1017,143 @@ func (s *HttpService) skipBinlogHandler(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+func (s *HttpService) stopAtHandler(w http.ResponseWriter, r *http.Request) {
+	var result *defaultResult
+	defer func() { writeJson(w, result) }()
+
+	// Parse the JSON request body
+	var request struct {
+		CcrCommonRequest
+		CommitSeq int64 `json:"commit_seq"`
+		Timestamp int64 `json:"timestamp"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		log.Warnf("stop at failed: %+v", err)
+		result = newErrorResult(err.Error())
+		return
+	}
+
+	if request.Name == "" {
+		log.Warnf("stop at failed: name is empty")
+		result = newErrorResult("name is empty")
+		return
+	}
+
+	if request.CommitSeq < 0 || request.Timestamp < 0 {
+		log.Warnf("stop at failed: invalid commit seq %d or timestamp %d", request.CommitSeq, request.Timestamp)
+		result = newErrorResult(fmt.Sprintf("invalid commit seq %d or timestamp %d", request.CommitSeq, request.Timestamp))
+		return
+	}
+
+	if s.redirect(request.Name, w, r) {
+		return
+	}
+
+	if err := s.jobManager.StopAt(request.Name, request.CommitSeq, request.Timestamp); err != nil {
+		log.Warnf("stop at failed: %+v", err)
+		result = newErrorResult(err.Error())
+	} else {
+		result = newSuccessResult()
+	}
+}
+
+func (s *HttpService) pendingBinlogHandler(w http.ResponseWriter, r *http.Request) {
+	log.Infof("get pending binlog")
+
+	type result struct {
+		*defaultResult
+		PendingBinlog *ccr.HeldBinlog `json:"pending_binlog,omitempty"`
+		Record        any             `json:"record,omitempty"`
+	}
+	var pendingBinlogResult *result
+	defer func() { writeJson(w, pendingBinlogResult) }()
+
+	// Parse the JSON request body
+	var request CcrCommonRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		log.Warnf("get pending binlog failed: %+v", err)
+
+		pendingBinlogResult = &result{
+			defaultResult: newErrorResult(err.Error()),
+		}
+		return
+	}
+
+	if request.Name == "" {
+		log.Warnf("get pending binlog failed: name is empty")
+
+		pendingBinlogResult = &result{
+			defaultResult: newErrorResult("name is empty"),
+		}
+		return
+	}
+
+	if s.redirect(request.Name, w, r) {
+		return
+	}
+
+	if pendingBinlog, record, err := s.jobManager.GetPendingBinlog(request.Name); err != nil {
+		log.Warnf("get pending binlog failed: %+v", err)
+
+		pendingBinlogResult = &result{
+			defaultResult: newErrorResult(err.Error()),
+			PendingBinlog: pendingBinlog,
+		}
+	} else {
+		pendingBinlogResult = &result{
+			defaultResult: newSuccessResult(),
+			PendingBinlog: pendingBinlog,
+			Record:        record,
+		}
+	}
+}
+
+func (s *HttpService) decideBinlogHandler(decision string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var result *defaultResult
+		defer func() { writeJson(w, result) }()
+
+		// Parse the JSON request body
+		var request struct {
+			CcrCommonRequest
+			CommitSeq int64  `json:"commit_seq"`
+			Operator  string `json:"operator"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			log.Warnf("%s binlog failed: %+v", decision, err)
+			result = newErrorResult(err.Error())
+			return
+		}
+
+		if request.Name == "" {
+			log.Warnf("%s binlog failed: name is empty", decision)
+			result = newErrorResult("name is empty")
+			return
+		}
+
+		operator := requestOperator(r, request.Operator)
+		if operator == "" {
+			log.Warnf("%s binlog failed: operator is empty", decision)
+			result = newErrorResult("operator is empty")
+			return
+		}
+
+		if s.redirect(request.Name, w, r) {
+			return
+		}
+
+		if err := s.jobManager.DecideHeldBinlog(request.Name, request.CommitSeq, decision, operator); err != nil {
+			log.Warnf("%s binlog failed: %+v", decision, err)
+			result = newErrorResult(err.Error())
+		} else {
+			result = newSuccessResult()
+		}
+	}
+}
+
 func (s *HttpService) failpointHandler(w http.ResponseWriter, r *http.Request) {
 	log.Infof("inject failpoint")
 
@@ -862,33 +1195,66 @@ func (s *HttpService) failpointHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *HttpService) RegisterHandlers() {
-	s.mux.HandleFunc("/version", s.versionHandler)
-	s.mux.HandleFunc("/create_ccr", s.createHandler)
-	s.mux.HandleFunc("/pause", s.pauseHandler)
-	s.mux.HandleFunc("/resume", s.resumeHandler)
-	s.mux.HandleFunc("/delete", s.deleteHandler)
-	s.mux.HandleFunc("/desync", s.desyncHandler)
-	s.mux.HandleFunc("/get_lag", s.getLagHandler)
-	s.mux.HandleFunc("/list_jobs", s.listJobsHandler)
-	s.mux.HandleFunc("/job_detail", s.jobDetailHandler)
-	s.mux.HandleFunc("/job_status", s.statusHandler)
-	s.mux.HandleFunc("/job_progress", s.jobProgressHandler)
-	s.mux.HandleFunc("/force_fullsync", s.forceFullsyncHandler)
-	s.mux.HandleFunc("/features", s.featuresHandler)
-	s.mux.HandleFunc("/update_host_mapping", s.updateHostMappingHandler)
-	s.mux.HandleFunc("/job_skip_binlog", s.skipBinlogHandler)
-	s.mux.HandleFunc("/failpoint", s.failpointHandler)
-	s.mux.Handle("/metrics", promhttp.Handler())
+	s.handle("/version", RoleViewer, s.versionHandler)
+	s.handle("/create_ccr", RoleAdmin, s.createHandler)
+	s.handle("/pause", RoleOperator, s.pauseHandler)
+	s.handle("/resume", RoleOperator, s.resumeHandler)
+	s.handle("/delete", RoleAdmin, s.deleteHandler)
+	s.handle("/desync", RoleAdmin, s.desyncHandler)
+	s.handle("/get_lag", RoleViewer, s.getLagHandler)
+	s.handle("/list_jobs_lag", RoleViewer, s.listJobsLagHandler)
+	s.handle("/list_jobs", RoleViewer, s.listJobsHandler)
+	s.handle("/job_detail", RoleViewer, s.jobDetailHandler)
+	s.handle("/job_status", RoleViewer, s.statusHandler)
+	s.handle("/job_progress", RoleViewer, s.jobProgressHandler)
+	s.handle("/job_events", RoleViewer, s.jobEventsHandler)
+	s.handle("/force_fullsync", RoleAdmin, s.forceFullsyncHandler)
+	s.handle("/features", RoleViewer, s.featuresHandler)
+	s.handle("/update_host_mapping", RoleAdmin, s.updateHostMappingHandler)
+	s.handle("/update_job", RoleAdmin, s.updateJobHandler)
+	s.handle("/job_skip_binlog", RoleAdmin, s.skipBinlogHandler)
+	s.handle("/job_stop_at", RoleOperator, s.stopAtHandler)
+	s.handle("/job_pending_binlog", RoleViewer, s.pendingBinlogHandler)
+	s.handle("/approve_binlog", RoleOperator, s.decideBinlogHandler(ccr.BinlogApproved))
+	s.handle("/reject_binlog", RoleOperator, s.decideBinlogHandler(ccr.BinlogRejected))
+	s.handle("/failpoint", RoleAdmin, s.failpointHandler)
+	s.mux.Handle("/metrics", s.authorize("/metrics", RoleViewer, promhttp.Handler()))
+	// the probes are not authenticated
+	s.mux.HandleFunc("/healthz", s.healthzHandler)
+	s.mux.HandleFunc("/readyz", s.readyzHandler)
+
+	s.registerApiV2()
+}
+
+// Register the handler, it requires the role if the http api is authenticated.
+func (s *HttpService) handle(pattern string, role Role, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.Handle(pattern, s.authorize(pattern, role, http.HandlerFunc(handler)))
+}
+
+func (s *HttpService) authorize(pattern string, role Role, handler http.Handler) http.Handler {
+	if s.auth == nil {
+		return handler
+	}
+	return s.auth.wrap(pattern, role, handler)
 }
 
 func (s *HttpService) Start() error {
 	addr := fmt.Sprintf(":%d", s.port)
-	log.Infof("Server listening on %s", addr)
+	log.Infof("Server listening on %s, tls: %t", addr, TLSEnabled())
 
+	if httpAuthConfigFile != "" {
+		config, err := loadAuthConfig(httpAuthConfigFile)
+		if err != nil {
+			return err
+		}
+		log.Infof("http auth is enabled, %d tokens, %d cert common names", len(config.Tokens), len(config.CertCommonNames))
+		s.auth = newHttpAuth(config)
+	}
 	s.RegisterHandlers()
+	go s.jobsConnectivity.run(s.jobManager, s.stop)
 
 	s.server = &http.Server{Addr: addr, Handler: s.mux}
-	err := s.server.ListenAndServe()
+	err := ListenAndServe(s.server)
 	if err == nil {
 		return nil
 	} else if err == http.ErrServerClosed {
@@ -902,6 +1268,8 @@ func (s *HttpService) Start() error {
 // Stop stops the HTTP server gracefully.
 // It returns an error if the server shutdown fails.
 func (s *HttpService) Stop() error {
+	s.stopping.Store(true)
+	close(s.stop)
 	if err := s.server.Shutdown(context.TODO()); err != nil {
 		return xerror.Wrapf(err, xerror.Normal, "http server close failed")
 	}
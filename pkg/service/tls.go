@@ -0,0 +1,209 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	tlsCertFile       string
+	tlsKeyFile        string
+	tlsCAFile         string
+	tlsReloadInterval time.Duration
+)
+
+func init() {
+	flag.StringVar(&tlsCertFile, "tls_cert_file", "", "the cert file of the http server, serve https if it is set")
+	flag.StringVar(&tlsKeyFile, "tls_key_file", "", "the key file of the http server")
+	flag.StringVar(&tlsCAFile, "tls_ca_file", "",
+		"the ca file to verify the client certs and the certs of the other syncers")
+	flag.DurationVar(&tlsReloadInterval, "tls_reload_interval", 30*time.Second,
+		"the interval to check whether the tls files are rotated")
+}
+
+// Whether the http server, the pprof listener and the requests between syncers use TLS.
+func TLSEnabled() bool {
+	return tlsCertFile != ""
+}
+
+func httpScheme() string {
+	if TLSEnabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// tlsFiles loads the cert, key and ca files, and reloads them once they are rotated.
+type tlsFiles struct {
+	mu        sync.Mutex
+	checkedAt time.Time
+	modTimes  [3]time.Time
+	cert      *tls.Certificate
+	caPool    *x509.CertPool
+}
+
+var (
+	sharedTLSFiles     *tlsFiles
+	sharedTLSFilesOnce sync.Once
+	sharedTLSFilesErr  error
+)
+
+func getTLSFiles() (*tlsFiles, error) {
+	sharedTLSFilesOnce.Do(func() {
+		if tlsKeyFile == "" {
+			sharedTLSFilesErr = xerror.New(xerror.Normal, "the tls key file is required")
+			return
+		}
+		files := &tlsFiles{}
+		if sharedTLSFilesErr = files.load(); sharedTLSFilesErr == nil {
+			sharedTLSFiles = files
+		}
+	})
+	return sharedTLSFiles, sharedTLSFilesErr
+}
+
+func fileModTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	if info, err := os.Stat(path); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+func (f *tlsFiles) load() error {
+	modTimes := [3]time.Time{fileModTime(tlsCertFile), fileModTime(tlsKeyFile), fileModTime(tlsCAFile)}
+
+	cert, err := tls.LoadX509KeyPair(tlsCertFile, tlsKeyFile)
+	if err != nil {
+		return xerror.Wrapf(err, xerror.Normal, "load tls cert %s and key %s failed", tlsCertFile, tlsKeyFile)
+	}
+
+	var caPool *x509.CertPool
+	if tlsCAFile != "" {
+		data, err := os.ReadFile(tlsCAFile)
+		if err != nil {
+			return xerror.Wrapf(err, xerror.Normal, "read tls ca file %s failed", tlsCAFile)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(data) {
+			return xerror.Errorf(xerror.Normal, "no certs found in tls ca file %s", tlsCAFile)
+		}
+	}
+
+	f.cert = &cert
+	f.caPool = caPool
+	f.modTimes = modTimes
+	return nil
+}
+
+// Get the latest cert and ca pool, the files are reloaded if they are rotated.
+func (f *tlsFiles) get() (*tls.Certificate, *x509.CertPool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if time.Since(f.checkedAt) >= tlsReloadInterval {
+		f.checkedAt = time.Now()
+		modTimes := [3]time.Time{fileModTime(tlsCertFile), fileModTime(tlsKeyFile), fileModTime(tlsCAFile)}
+		if modTimes != f.modTimes {
+			// Keep the old files if the rotated ones are broken, eg. only the cert is written.
+			if err := f.load(); err != nil {
+				log.Warnf("reload the rotated tls files failed, keep the old ones, err: %+v", err)
+			} else {
+				log.Infof("the rotated tls files are reloaded, cert: %s", tlsCertFile)
+			}
+		}
+	}
+	return f.cert, f.caPool
+}
+
+// The tls config of the servers, the client certs are verified if they are given and the ca is set.
+func NewServerTLSConfig() (*tls.Config, error) {
+	files, err := getTLSFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := files.get()
+			return cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, caPool := files.get()
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if caPool != nil {
+				config.ClientCAs = caPool
+				config.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+			return config, nil
+		},
+	}, nil
+}
+
+// The http client to request the other syncers, it presents the cert of this syncer. The
+// connections are not reused, so the rotated ca is always used.
+func newPeerHttpClient() (*http.Client, error) {
+	if !TLSEnabled() {
+		return http.DefaultClient, nil
+	}
+
+	files, err := getTLSFiles()
+	if err != nil {
+		return nil, err
+	}
+	_, caPool := files.get()
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    caPool, // the system roots are used if it is nil
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := files.get()
+			return cert, nil
+		},
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}, nil
+}
+
+// Serve the server over TLS if it is enabled.
+func ListenAndServe(server *http.Server) error {
+	if !TLSEnabled() {
+		return server.ListenAndServe()
+	}
+
+	config, err := NewServerTLSConfig()
+	if err != nil {
+		return err
+	}
+	server.TLSConfig = config
+	return server.ListenAndServeTLS("", "")
+}
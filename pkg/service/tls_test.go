@@ -0,0 +1,102 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSelfSignedCert(t *testing.T, certFile, keyFile, commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create cert failed: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key failed: %v", err)
+	}
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	if err := os.WriteFile(certFile, certPem, 0600); err != nil {
+		t.Fatalf("write cert failed: %v", err)
+	}
+	if err := os.WriteFile(keyFile, keyPem, 0600); err != nil {
+		t.Fatalf("write key failed: %v", err)
+	}
+}
+
+func TestTLSFilesReload(t *testing.T) {
+	dir := t.TempDir()
+	tlsCertFile = filepath.Join(dir, "server.crt")
+	tlsKeyFile = filepath.Join(dir, "server.key")
+	tlsReloadInterval = 0
+	defer func() { tlsCertFile, tlsKeyFile, tlsReloadInterval = "", "", 30*time.Second }()
+
+	writeSelfSignedCert(t, tlsCertFile, tlsKeyFile, "old")
+	files := &tlsFiles{}
+	if err := files.load(); err != nil {
+		t.Fatalf("load tls files failed: %v", err)
+	}
+
+	commonName := func() string {
+		cert, _ := files.get()
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatalf("parse cert failed: %v", err)
+		}
+		return leaf.Subject.CommonName
+	}
+	if name := commonName(); name != "old" {
+		t.Fatalf("expect cert old, but got %s", name)
+	}
+
+	writeSelfSignedCert(t, tlsCertFile, tlsKeyFile, "new")
+	rotatedAt := time.Now().Add(time.Minute)
+	os.Chtimes(tlsCertFile, rotatedAt, rotatedAt)
+	os.Chtimes(tlsKeyFile, rotatedAt, rotatedAt)
+	if name := commonName(); name != "new" {
+		t.Fatalf("expect the rotated cert new, but got %s", name)
+	}
+
+	// The broken files are ignored.
+	os.WriteFile(tlsKeyFile, []byte("broken"), 0600)
+	os.Chtimes(tlsKeyFile, rotatedAt.Add(time.Minute), rotatedAt.Add(time.Minute))
+	if name := commonName(); name != "new" {
+		t.Fatalf("expect the cert new is kept, but got %s", name)
+	}
+}
@@ -17,6 +17,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"flag"
@@ -56,6 +57,8 @@ type DB interface {
 	GetJobInfo(jobName string) (string, error)
 	// Get job_belong
 	GetJobBelong(jobName string) (string, error)
+	// Get the job_info of all jobs, job_name -> job_info
+	GetAllJobInfos() (map[string]string, error)
 
 	// Update ccr sync progress
 	UpdateProgress(jobName string, progress string) error
@@ -77,6 +80,16 @@ type DB interface {
 
 	// GetAllData
 	GetAllData() (map[string][]string, error)
+
+	// Append a job event
+	AddJobEvent(event *JobEvent) error
+	// Get the job events matched the filter, the latest first
+	GetJobEvents(jobName string, filter *JobEventFilter) ([]*JobEvent, error)
+	// Remove the job events before the timestamp (in milliseconds), return the number of removed events
+	RemoveJobEventsBefore(timestamp int64) (int64, error)
+
+	// Ping checks whether the db is reachable
+	Ping(ctx context.Context) error
 }
 
 func SetDBOptions(db *sql.DB) {
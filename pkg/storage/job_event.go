@@ -0,0 +1,97 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const defaultJobEventsLimit = 100
+
+// JobEvent is a row of the append-only job events table, the detail is a json string.
+type JobEvent struct {
+	Id        int64
+	JobName   string
+	EventType string
+	Timestamp int64 // unix time in milliseconds
+	Message   string
+	Detail    string
+}
+
+// JobEventFilter filters the job events, the zero values mean no limit, except the Limit.
+type JobEventFilter struct {
+	EventTypes []string
+	Since      int64 // unix time in milliseconds, inclusive
+	Until      int64 // unix time in milliseconds, exclusive
+	Limit      int   // the max number of the events, defaultJobEventsLimit if it is zero
+}
+
+// Build the where clause and the args of the filter, placeholder returns the placeholder of the
+// i-th (start from 1) arg.
+func (f *JobEventFilter) whereClause(jobName string, placeholder func(i int) string) (string, []any) {
+	conds := []string{"job_name = " + placeholder(1)}
+	args := []any{jobName}
+	if len(f.EventTypes) > 0 {
+		holders := make([]string, 0, len(f.EventTypes))
+		for _, eventType := range f.EventTypes {
+			args = append(args, eventType)
+			holders = append(holders, placeholder(len(args)))
+		}
+		conds = append(conds, fmt.Sprintf("event_type IN (%s)", strings.Join(holders, ", ")))
+	}
+	if f.Since > 0 {
+		args = append(args, f.Since)
+		conds = append(conds, "timestamp >= "+placeholder(len(args)))
+	}
+	if f.Until > 0 {
+		args = append(args, f.Until)
+		conds = append(conds, "timestamp < "+placeholder(len(args)))
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func (f *JobEventFilter) limit() int {
+	if f.Limit <= 0 {
+		return defaultJobEventsLimit
+	}
+	return f.Limit
+}
+
+func questionPlaceholder(i int) string {
+	return "?"
+}
+
+func dollarPlaceholder(i int) string {
+	return fmt.Sprintf("$%d", i)
+}
+
+func scanJobEvents(rows *sql.Rows) ([]*JobEvent, error) {
+	events := make([]*JobEvent, 0)
+	for rows.Next() {
+		event := &JobEvent{}
+		if err := rows.Scan(&event.Id, &event.JobName, &event.EventType, &event.Timestamp, &event.Message, &event.Detail); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,76 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteJobEvents(t *testing.T) {
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "ccr.db"))
+	if err != nil {
+		t.Fatalf("new sqlite db failed: %+v", err)
+	}
+
+	events := []*JobEvent{
+		{JobName: "job1", EventType: "created", Timestamp: 1000},
+		{JobName: "job1", EventType: "full_sync_started", Timestamp: 2000, Detail: `{"commit_seq":10}`},
+		{JobName: "job1", EventType: "restore_finished", Timestamp: 3000},
+		{JobName: "job2", EventType: "full_sync_started", Timestamp: 2500},
+	}
+	for _, event := range events {
+		if err := db.AddJobEvent(event); err != nil {
+			t.Fatalf("add job event failed: %+v", err)
+		}
+	}
+
+	type TestCase struct {
+		filter JobEventFilter
+		expect []string
+	}
+	tests := []TestCase{
+		{filter: JobEventFilter{}, expect: []string{"restore_finished", "full_sync_started", "created"}},
+		{filter: JobEventFilter{EventTypes: []string{"created", "restore_finished"}}, expect: []string{"restore_finished", "created"}},
+		{filter: JobEventFilter{Since: 2000, Until: 3000}, expect: []string{"full_sync_started"}},
+		{filter: JobEventFilter{Limit: 1}, expect: []string{"restore_finished"}},
+	}
+	for i, test := range tests {
+		result, err := db.GetJobEvents("job1", &test.filter)
+		if err != nil {
+			t.Fatalf("test %d get job events failed: %+v", i, err)
+		}
+		types := make([]string, 0, len(result))
+		for _, event := range result {
+			types = append(types, event.EventType)
+		}
+		if len(types) != len(test.expect) {
+			t.Errorf("test %d expect %v, but got %v", i, test.expect, types)
+			continue
+		}
+		for j := range types {
+			if types[j] != test.expect[j] {
+				t.Errorf("test %d expect %v, but got %v", i, test.expect, types)
+				break
+			}
+		}
+	}
+
+	if num, err := db.RemoveJobEventsBefore(2500); err != nil || num != 2 {
+		t.Errorf("expect remove 2 events, but got %d, err: %v", num, err)
+	}
+}
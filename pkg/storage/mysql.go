@@ -68,6 +68,10 @@ func NewMysqlDB(host string, port int, user string, password string, remoteDBNam
 		return nil, xerror.Wrap(err, xerror.DB, "mysql: create table syncers failed")
 	}
 
+	if _, err = db.Exec("CREATE TABLE IF NOT EXISTS job_events (`id` BIGINT AUTO_INCREMENT PRIMARY KEY, `job_name` VARCHAR(512), `event_type` VARCHAR(64), `timestamp` BIGINT, `message` TEXT, `detail` TEXT, INDEX idx_job_events_job_time (`job_name`, `timestamp`))"); err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "mysql: create table job_events failed")
+	}
+
 	return &MysqlDB{db: db, dbName: remoteDBName}, nil
 }
 
@@ -169,6 +173,27 @@ func (s *MysqlDB) GetJobBelong(jobName string) (string, error) {
 	return belong, nil
 }
 
+func (s *MysqlDB) GetAllJobInfos() (map[string]string, error) {
+	rows, err := s.db.Query("SELECT job_name, job_info FROM jobs")
+	if err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "mysql: get all job infos failed")
+	}
+	defer rows.Close()
+
+	jobInfos := make(map[string]string)
+	for rows.Next() {
+		var jobName, jobInfo string
+		if err := rows.Scan(&jobName, &jobInfo); err != nil {
+			return nil, xerror.Wrap(err, xerror.DB, "mysql: scan job info failed")
+		}
+		jobInfos[jobName] = jobInfo
+	}
+	if err := rows.Err(); err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "mysql: iterate job infos failed")
+	}
+	return jobInfos, nil
+}
+
 func (s *MysqlDB) UpdateProgress(jobName string, progress string) error {
 	// quoteProgress := strings.ReplaceAll(progress, "\"", "\\\"")
 	encodeProgress := base64.StdEncoding.EncodeToString([]byte(progress))
@@ -447,3 +472,46 @@ func (s *MysqlDB) GetAllData() (map[string][]string, error) {
 
 	return ans, nil
 }
+
+func (s *MysqlDB) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return xerror.Wrap(err, xerror.DB, "mysql: ping failed")
+	}
+	return nil
+}
+
+func (s *MysqlDB) AddJobEvent(event *JobEvent) error {
+	if _, err := s.db.Exec("INSERT INTO job_events (job_name, event_type, timestamp, message, detail) VALUES (?, ?, ?, ?, ?)",
+		event.JobName, event.EventType, event.Timestamp, event.Message, event.Detail); err != nil {
+		return xerror.Wrapf(err, xerror.DB, "mysql: add job event failed, name: %s", event.JobName)
+	}
+	return nil
+}
+
+func (s *MysqlDB) GetJobEvents(jobName string, filter *JobEventFilter) ([]*JobEvent, error) {
+	where, args := filter.whereClause(jobName, questionPlaceholder)
+	query := fmt.Sprintf("SELECT id, job_name, event_type, timestamp, message, detail FROM job_events WHERE %s ORDER BY timestamp DESC, id DESC LIMIT %d", where, filter.limit())
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.DB, "mysql: get job events failed, name: %s", jobName)
+	}
+	defer rows.Close()
+
+	events, err := scanJobEvents(rows)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.DB, "mysql: scan job events failed, name: %s", jobName)
+	}
+	return events, nil
+}
+
+func (s *MysqlDB) RemoveJobEventsBefore(timestamp int64) (int64, error) {
+	result, err := s.db.Exec("DELETE FROM job_events WHERE timestamp < ?", timestamp)
+	if err != nil {
+		return 0, xerror.Wrap(err, xerror.DB, "mysql: remove job events failed")
+	}
+	if rowNum, err := result.RowsAffected(); err != nil {
+		return 0, xerror.Wrap(err, xerror.DB, "mysql: remove job events get affected rows failed")
+	} else {
+		return rowNum, nil
+	}
+}
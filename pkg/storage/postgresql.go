@@ -59,6 +59,14 @@ func NewPostgresqlDB(host string, port int, user string, password string, remote
 		return nil, xerror.Wrap(err, xerror.DB, "postgresql: create table syncers failed")
 	}
 
+	if _, err = db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.job_events (id BIGSERIAL PRIMARY KEY, job_name VARCHAR(512), event_type VARCHAR(64), timestamp BIGINT, message TEXT, detail TEXT)", remoteDBName)); err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "postgresql: create table job_events failed")
+	}
+
+	if _, err = db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_job_events_job_time ON %s.job_events (job_name, timestamp)", remoteDBName)); err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "postgresql: create index of job_events failed")
+	}
+
 	return &PostgresqlDB{db: db, dbName: remoteDBName}, nil
 }
 
@@ -160,6 +168,27 @@ func (s *PostgresqlDB) GetJobBelong(jobName string) (string, error) {
 	return belong, nil
 }
 
+func (s *PostgresqlDB) GetAllJobInfos() (map[string]string, error) {
+	rows, err := s.db.Query(fmt.Sprintf("SELECT job_name, job_info FROM %s.jobs", s.dbName))
+	if err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "postgresql: get all job infos failed")
+	}
+	defer rows.Close()
+
+	jobInfos := make(map[string]string)
+	for rows.Next() {
+		var jobName, jobInfo string
+		if err := rows.Scan(&jobName, &jobInfo); err != nil {
+			return nil, xerror.Wrap(err, xerror.DB, "postgresql: scan job info failed")
+		}
+		jobInfos[jobName] = jobInfo
+	}
+	if err := rows.Err(); err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "postgresql: iterate job infos failed")
+	}
+	return jobInfos, nil
+}
+
 func (s *PostgresqlDB) UpdateProgress(jobName string, progress string) error {
 	// quoteProgress := strings.ReplaceAll(progress, "\"", "\\\"")
 	encodeProgress := base64.StdEncoding.EncodeToString([]byte(progress))
@@ -445,3 +474,46 @@ func (s *PostgresqlDB) GetAllData() (map[string][]string, error) {
 
 	return ans, nil
 }
+
+func (s *PostgresqlDB) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return xerror.Wrap(err, xerror.DB, "postgresql: ping failed")
+	}
+	return nil
+}
+
+func (s *PostgresqlDB) AddJobEvent(event *JobEvent) error {
+	insertSql := fmt.Sprintf("INSERT INTO %s.job_events (job_name, event_type, timestamp, message, detail) VALUES ($1, $2, $3, $4, $5)", s.dbName)
+	if _, err := s.db.Exec(insertSql, event.JobName, event.EventType, event.Timestamp, event.Message, event.Detail); err != nil {
+		return xerror.Wrapf(err, xerror.DB, "postgresql: add job event failed, name: %s", event.JobName)
+	}
+	return nil
+}
+
+func (s *PostgresqlDB) GetJobEvents(jobName string, filter *JobEventFilter) ([]*JobEvent, error) {
+	where, args := filter.whereClause(jobName, dollarPlaceholder)
+	query := fmt.Sprintf("SELECT id, job_name, event_type, timestamp, message, detail FROM %s.job_events WHERE %s ORDER BY timestamp DESC, id DESC LIMIT %d", s.dbName, where, filter.limit())
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.DB, "postgresql: get job events failed, name: %s", jobName)
+	}
+	defer rows.Close()
+
+	events, err := scanJobEvents(rows)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.DB, "postgresql: scan job events failed, name: %s", jobName)
+	}
+	return events, nil
+}
+
+func (s *PostgresqlDB) RemoveJobEventsBefore(timestamp int64) (int64, error) {
+	result, err := s.db.Exec(fmt.Sprintf("DELETE FROM %s.job_events WHERE timestamp < $1", s.dbName), timestamp)
+	if err != nil {
+		return 0, xerror.Wrap(err, xerror.DB, "postgresql: remove job events failed")
+	}
+	if rowNum, err := result.RowsAffected(); err != nil {
+		return 0, xerror.Wrap(err, xerror.DB, "postgresql: remove job events get affected rows failed")
+	} else {
+		return rowNum, nil
+	}
+}
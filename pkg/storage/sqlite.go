@@ -53,6 +53,14 @@ func NewSQLiteDB(dbPath string) (DB, error) {
 		return nil, xerror.Wrap(err, xerror.DB, "sqlite: create table syncers failed")
 	}
 
+	if _, err = db.Exec("CREATE TABLE IF NOT EXISTS job_events (id INTEGER PRIMARY KEY AUTOINCREMENT, job_name TEXT, event_type TEXT, timestamp INTEGER, message TEXT, detail TEXT)"); err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "sqlite: create table job_events failed")
+	}
+
+	if _, err = db.Exec("CREATE INDEX IF NOT EXISTS idx_job_events_job_time ON job_events (job_name, timestamp)"); err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "sqlite: create index of job_events failed")
+	}
+
 	return &SQLiteDB{db: db}, nil
 }
 
@@ -153,6 +161,27 @@ func (s *SQLiteDB) GetJobBelong(jobName string) (string, error) {
 	return belong, nil
 }
 
+func (s *SQLiteDB) GetAllJobInfos() (map[string]string, error) {
+	rows, err := s.db.Query("SELECT job_name, job_info FROM jobs")
+	if err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "sqlite: get all job infos failed")
+	}
+	defer rows.Close()
+
+	jobInfos := make(map[string]string)
+	for rows.Next() {
+		var jobName, jobInfo string
+		if err := rows.Scan(&jobName, &jobInfo); err != nil {
+			return nil, xerror.Wrap(err, xerror.DB, "sqlite: scan job info failed")
+		}
+		jobInfos[jobName] = jobInfo
+	}
+	if err := rows.Err(); err != nil {
+		return nil, xerror.Wrap(err, xerror.DB, "sqlite: iterate job infos failed")
+	}
+	return jobInfos, nil
+}
+
 func (s *SQLiteDB) UpdateProgress(jobName string, progress string) error {
 	if result, err := s.db.Exec("INSERT INTO progresses VALUES (?, ?) ON CONFLICT (job_name) DO UPDATE SET progress = ?", jobName, progress, progress); err != nil {
 		return xerror.Wrap(err, xerror.DB, "sqlite: update progress failed")
@@ -420,3 +449,46 @@ func (s *SQLiteDB) GetAllData() (map[string][]string, error) {
 
 	return ans, nil
 }
+
+func (s *SQLiteDB) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return xerror.Wrap(err, xerror.DB, "sqlite: ping failed")
+	}
+	return nil
+}
+
+func (s *SQLiteDB) AddJobEvent(event *JobEvent) error {
+	if _, err := s.db.Exec("INSERT INTO job_events (job_name, event_type, timestamp, message, detail) VALUES (?, ?, ?, ?, ?)",
+		event.JobName, event.EventType, event.Timestamp, event.Message, event.Detail); err != nil {
+		return xerror.Wrapf(err, xerror.DB, "sqlite: add job event failed, name: %s", event.JobName)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) GetJobEvents(jobName string, filter *JobEventFilter) ([]*JobEvent, error) {
+	where, args := filter.whereClause(jobName, questionPlaceholder)
+	query := fmt.Sprintf("SELECT id, job_name, event_type, timestamp, message, detail FROM job_events WHERE %s ORDER BY timestamp DESC, id DESC LIMIT %d", where, filter.limit())
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.DB, "sqlite: get job events failed, name: %s", jobName)
+	}
+	defer rows.Close()
+
+	events, err := scanJobEvents(rows)
+	if err != nil {
+		return nil, xerror.Wrapf(err, xerror.DB, "sqlite: scan job events failed, name: %s", jobName)
+	}
+	return events, nil
+}
+
+func (s *SQLiteDB) RemoveJobEventsBefore(timestamp int64) (int64, error) {
+	result, err := s.db.Exec("DELETE FROM job_events WHERE timestamp < ?", timestamp)
+	if err != nil {
+		return 0, xerror.Wrap(err, xerror.DB, "sqlite: remove job events failed")
+	}
+	if rowNum, err := result.RowsAffected(); err != nil {
+		return 0, xerror.Wrap(err, xerror.DB, "sqlite: remove job events get affected rows failed")
+	} else {
+		return rowNum, nil
+	}
+}
@@ -21,8 +21,10 @@
 package test_util
 
 import (
+	context "context"
 	reflect "reflect"
 
+	storage "github.com/selectdb/ccr_syncer/pkg/storage"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -92,6 +94,21 @@ func (mr *MockDBMockRecorder) GetAllData() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllData", reflect.TypeOf((*MockDB)(nil).GetAllData))
 }
 
+// GetAllJobInfos mocks base method.
+func (m *MockDB) GetAllJobInfos() (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllJobInfos")
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllJobInfos indicates an expected call of GetAllJobInfos.
+func (mr *MockDBMockRecorder) GetAllJobInfos() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllJobInfos", reflect.TypeOf((*MockDB)(nil).GetAllJobInfos))
+}
+
 // GetDeadSyncers mocks base method.
 func (m *MockDB) GetDeadSyncers(expiredTime int64) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -268,3 +285,61 @@ func (mr *MockDBMockRecorder) UpdateProgress(jobName, progress interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProgress", reflect.TypeOf((*MockDB)(nil).UpdateProgress), jobName, progress)
 }
+
+// Ping mocks base method.
+func (m *MockDB) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockDBMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockDB)(nil).Ping), ctx)
+}
+
+// AddJobEvent mocks base method.
+func (m *MockDB) AddJobEvent(event *storage.JobEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddJobEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddJobEvent indicates an expected call of AddJobEvent.
+func (mr *MockDBMockRecorder) AddJobEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJobEvent", reflect.TypeOf((*MockDB)(nil).AddJobEvent), event)
+}
+
+// GetJobEvents mocks base method.
+func (m *MockDB) GetJobEvents(jobName string, filter *storage.JobEventFilter) ([]*storage.JobEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobEvents", jobName, filter)
+	ret0, _ := ret[0].([]*storage.JobEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobEvents indicates an expected call of GetJobEvents.
+func (mr *MockDBMockRecorder) GetJobEvents(jobName, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobEvents", reflect.TypeOf((*MockDB)(nil).GetJobEvents), jobName, filter)
+}
+
+// RemoveJobEventsBefore mocks base method.
+func (m *MockDB) RemoveJobEventsBefore(timestamp int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveJobEventsBefore", timestamp)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveJobEventsBefore indicates an expected call of RemoveJobEventsBefore.
+func (mr *MockDBMockRecorder) RemoveJobEventsBefore(timestamp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveJobEventsBefore", reflect.TypeOf((*MockDB)(nil).RemoveJobEventsBefore), timestamp)
+}
@@ -0,0 +1,187 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package xmetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The metrics registered to the prometheus directly, since the go-metrics sink has no histogram,
+// and drops the gauges which are not updated in a minute.
+const namespace = "ccr_syncer"
+
+var (
+	jobNum = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "jobs",
+		Help:      "The number of the jobs run by this syncer.",
+	})
+
+	jobState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_state",
+		Help:      "The state of the job, 0 running, 1 paused.",
+	}, []string{"job"})
+	jobSyncState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_sync_state",
+		Help: "The sync state of the job, 0 DBFullSync, 1 DBTablesIncrementalSync, 2 DBSpecificTableFullSync, " +
+			"3 DBIncrementalSync, 4 DBPartialSync, 500 TableFullSync, 501 TableIncrementalSync, 502 TablePartialSync.",
+	}, []string{"job"})
+	jobLagSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_lag_seconds",
+		Help:      "The age of the oldest unapplied binlog, zero if all binlogs are applied.",
+	}, []string{"job"})
+	jobLagCommitSeqs = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_lag_commit_seqs",
+		Help:      "The number of the unapplied binlogs.",
+	}, []string{"job"})
+
+	binlogs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "binlogs_total",
+		Help:      "The number of the handled binlogs by the binlog type.",
+	}, []string{"job", "type"})
+	binlogBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "binlog_bytes_total",
+		Help:      "The size of the handled binlogs by the binlog type.",
+	}, []string{"job", "type"})
+	tableUpserts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "table_upserts_total",
+		Help:      "The number of the applied upserts by the source table.",
+	}, []string{"job", "table"})
+	tableIngestRows = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "table_ingest_rows_total",
+		Help:      "The number of the rows ingested by the source table, read from the src rowsets of the base index.",
+	}, []string{"job", "table"})
+	tableIngestBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "table_ingest_bytes_total",
+		Help:      "The disk size of the rowsets ingested by the source table, only the base index is counted.",
+	}, []string{"job", "table"})
+
+	upsertApplySeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upsert_apply_seconds",
+		Help:      "The latency of applying an upsert binlog, from begin txn to commit txn.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14),
+	}, []string{"job"})
+	ingestBinlogSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ingest_binlog_seconds",
+		Help:      "The latency of the IngestBinlog rpc of the dest backends.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"job"})
+	txnSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "txn_seconds",
+		Help:      "The latency of the begin and commit txn rpcs of the dest frontend.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"job", "op"})
+	fullSyncSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "full_sync_seconds",
+		Help:      "The duration of the full sync, from creating the snapshot to the incremental sync.",
+		Buckets:   prometheus.ExponentialBuckets(10, 2, 12),
+	}, []string{"job"})
+
+	notifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_total",
+		Help:      "The number of the webhook notifications by the event and the result, sent, failed or rate_limited.",
+	}, []string{"event", "result"})
+
+	jobVecs = []interface {
+		DeletePartialMatch(prometheus.Labels) int
+	}{
+		jobState, jobSyncState, jobLagSeconds, jobLagCommitSeqs, binlogs, binlogBytes, tableUpserts,
+		tableIngestRows, tableIngestBytes,
+		upsertApplySeconds, ingestBinlogSeconds, txnSeconds, fullSyncSeconds,
+	}
+)
+
+const (
+	TxnBegin  = "begin"
+	TxnCommit = "commit"
+)
+
+func SetJobNum(num int) {
+	jobNum.Set(float64(num))
+}
+
+// RemoveJob removes all metrics of the job.
+func RemoveJob(jobName string) {
+	labels := prometheus.Labels{"job": jobName}
+	for _, vec := range jobVecs {
+		vec.DeletePartialMatch(labels)
+	}
+}
+
+func JobState(jobName string, state, syncState int32) {
+	jobState.WithLabelValues(jobName).Set(float64(state))
+	jobSyncState.WithLabelValues(jobName).Set(float64(syncState))
+}
+
+func JobLagSeconds(jobName string, seconds int64) {
+	jobLagSeconds.WithLabelValues(jobName).Set(float64(seconds))
+}
+
+func JobLagCommitSeqs(jobName string, lag int64) {
+	jobLagCommitSeqs.WithLabelValues(jobName).Set(float64(lag))
+}
+
+func HandleBinlog(jobName, binlogType string, size int) {
+	binlogs.WithLabelValues(jobName, binlogType).Inc()
+	binlogBytes.WithLabelValues(jobName, binlogType).Add(float64(size))
+}
+
+func UpsertTable(jobName, table string) {
+	tableUpserts.WithLabelValues(jobName, table).Inc()
+}
+
+func IngestTable(jobName, table string, rows, bytes int64) {
+	tableIngestRows.WithLabelValues(jobName, table).Add(float64(rows))
+	tableIngestBytes.WithLabelValues(jobName, table).Add(float64(bytes))
+}
+
+func ObserveUpsertApply(jobName string, duration time.Duration) {
+	upsertApplySeconds.WithLabelValues(jobName).Observe(duration.Seconds())
+}
+
+func ObserveIngestBinlog(jobName string, duration time.Duration) {
+	ingestBinlogSeconds.WithLabelValues(jobName).Observe(duration.Seconds())
+}
+
+func ObserveTxn(jobName, op string, duration time.Duration) {
+	txnSeconds.WithLabelValues(jobName, op).Observe(duration.Seconds())
+}
+
+func ObserveFullSync(jobName string, duration time.Duration) {
+	fullSyncSeconds.WithLabelValues(jobName).Observe(duration.Seconds())
+}
+
+func Notification(event, result string) {
+	notifications.WithLabelValues(event, result).Inc()
+}
@@ -0,0 +1,47 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package xmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRemoveJob(t *testing.T) {
+	JobState("ccr_test", 1, 501)
+	JobLagSeconds("ccr_test", 30)
+	HandleBinlog("ccr_test", "UPSERT", 128)
+	ObserveTxn("ccr_test", TxnBegin, 10*time.Millisecond)
+	JobLagSeconds("ccr_other", 10)
+
+	if value := testutil.ToFloat64(jobSyncState.WithLabelValues("ccr_test")); value != 501 {
+		t.Errorf("unexpected sync state: %v", value)
+	}
+	if value := testutil.ToFloat64(binlogBytes.WithLabelValues("ccr_test", "UPSERT")); value != 128 {
+		t.Errorf("unexpected binlog bytes: %v", value)
+	}
+
+	RemoveJob("ccr_test")
+	if count := testutil.CollectAndCount(jobLagSeconds); count != 1 {
+		t.Errorf("expect only the lag of ccr_other, but got %d", count)
+	}
+	if count := testutil.CollectAndCount(txnSeconds) + testutil.CollectAndCount(binlogs); count != 0 {
+		t.Errorf("expect the metrics of ccr_test are removed, but got %d", count)
+	}
+}
@@ -41,11 +41,6 @@ func (d *dashboardMetrics) Tag() []string {
 	return d.tags
 }
 
-func (d *dashboardMetrics) JobNum() IMetricsTag {
-	d.tags = append(d.tags, "jobNum")
-	return d
-}
-
 func (d *dashboardMetrics) BinlogNum() IMetricsTag {
 	d.tags = append(d.tags, "binlogNum")
 	return d
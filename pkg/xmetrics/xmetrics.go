@@ -41,8 +41,6 @@ func AddError(err *xerror.XError) {
 
 func AddNewJob(jobName string) {
 	metrics.SetGauge(JobMetrics(jobName).HandlingCommitSeq().Tag(), -1)
-
-	metrics.IncrCounter(DashboardMetrics().JobNum().Tag(), 1)
 }
 
 func HandlingBinlog(jobName string, commitSeq int64) {
@@ -0,0 +1,255 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package xtrace
+
+import (
+	"context"
+	"flag"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/modern-go/gls"
+	"github.com/selectdb/ccr_syncer/pkg/version"
+	"github.com/selectdb/ccr_syncer/pkg/xerror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ExporterNone     = ""
+	ExporterOtlpHttp = "otlphttp"
+	ExporterOtlpGrpc = "otlpgrpc"
+	ExporterStdout   = "stdout"
+
+	serviceName     = "ccr_syncer"
+	instrumentation = "github.com/selectdb/ccr_syncer"
+	shutdownTimeout = 5 * time.Second
+
+	// the key of the current span context in the goroutine local storage
+	glsSpanKey = "span"
+)
+
+var (
+	traceExporter    string
+	traceEndpoint    string
+	traceInsecure    bool
+	traceSampleRatio float64
+)
+
+func init() {
+	flag.StringVar(&traceExporter, "trace_exporter", ExporterNone,
+		"the exporter of the tracing spans, otlphttp, otlpgrpc or stdout, the tracing is disabled if it is empty")
+	flag.StringVar(&traceEndpoint, "trace_endpoint", "",
+		"the host:port of the otlp collector, the OTEL_EXPORTER_OTLP_ENDPOINT env or the default one of the exporter is used if it is empty")
+	flag.BoolVar(&traceInsecure, "trace_insecure", false, "connect to the otlp collector without tls")
+	flag.Float64Var(&traceSampleRatio, "trace_sample_ratio", 1.0,
+		"the ratio of the sampled binlogs, in [0, 1]")
+}
+
+var tracer = otel.Tracer(instrumentation)
+
+var provider atomic.Pointer[sdktrace.TracerProvider]
+
+func newExporter(ctx context.Context, exporter, endpoint string, insecure bool) (sdktrace.SpanExporter, error) {
+	switch exporter {
+	case ExporterOtlpHttp:
+		opts := make([]otlptracehttp.Option, 0, 2)
+		if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterOtlpGrpc:
+		opts := make([]otlptracegrpc.Option, 0, 2)
+		if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, xerror.Errorf(xerror.Normal, "unknown trace exporter %s", exporter)
+	}
+}
+
+// The root spans are sampled by the ratio, the child spans follow their parents.
+func newSampler(ratio float64) (sdktrace.Sampler, error) {
+	if ratio < 0 || ratio > 1 {
+		return nil, xerror.Errorf(xerror.Normal, "the trace sample ratio %v is not in [0, 1]", ratio)
+	}
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio)), nil
+}
+
+// Create the tracer provider by the flags and register it globally, do nothing if the exporter is not set.
+func Init(hostInfo string) error {
+	if traceExporter == ExporterNone {
+		return nil
+	}
+
+	sampler, err := newSampler(traceSampleRatio)
+	if err != nil {
+		return err
+	}
+	exporter, err := newExporter(context.Background(), traceExporter, traceEndpoint, traceInsecure)
+	if err != nil {
+		return xerror.Wrapf(err, xerror.Normal, "create trace exporter %s failed", traceExporter)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", version.GetVersion()),
+		attribute.String("service.instance.id", hostInfo),
+	)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	provider.Store(tp)
+	log.Infof("tracing is enabled, exporter: %s, endpoint: %s, sample ratio: %v", traceExporter, traceEndpoint, traceSampleRatio)
+	return nil
+}
+
+// Flush the pending spans and stop the exporter.
+func Shutdown() {
+	tp := provider.Swap(nil)
+	if tp == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		log.Warnf("shutdown tracer provider failed, err: %+v", err)
+	}
+}
+
+type Span struct {
+	ctx  context.Context
+	span trace.Span
+
+	// the previous current span of the goroutine, restored by End
+	prev    context.Context
+	current bool
+}
+
+// The context of the current span of the goroutine, or the background context if there is none.
+func Current() context.Context {
+	if ctx, ok := gls.Get(glsSpanKey).(context.Context); ok {
+		return ctx
+	}
+	return context.Background()
+}
+
+// Start a span as the child of the current span, and make it the current span of the goroutine until End.
+// The goroutine without the gls only starts the span.
+func Start(name string, attrs ...attribute.KeyValue) *Span {
+	prev := Current()
+	s := StartWithParent(prev, name, attrs...)
+	if gls.IsGlsEnabled(gls.GoID()) {
+		gls.Set(glsSpanKey, s.ctx)
+		s.prev = prev
+		s.current = true
+	}
+	return s
+}
+
+// Start a span as the child of the span in the parent context, it is used in the new goroutines.
+func StartWithParent(parent context.Context, name string, attrs ...attribute.KeyValue) *Span {
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, span := tracer.Start(parent, name, trace.WithAttributes(attrs...))
+	return &Span{ctx: ctx, span: span}
+}
+
+func (s *Span) Context() context.Context {
+	return s.ctx
+}
+
+func (s *Span) SetAttributes(attrs ...attribute.KeyValue) {
+	s.span.SetAttributes(attrs...)
+}
+
+// End the span, and mark it failed if err is not nil.
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+
+	if s.current {
+		gls.Set(glsSpanKey, s.prev)
+	}
+}
+
+func Job(name string) attribute.KeyValue {
+	return attribute.String("ccr.job", name)
+}
+
+func CommitSeq(commitSeq int64) attribute.KeyValue {
+	return attribute.Int64("ccr.commit_seq", commitSeq)
+}
+
+func BinlogType(binlogType string) attribute.KeyValue {
+	return attribute.String("ccr.binlog_type", binlogType)
+}
+
+func Binlogs(num int) attribute.KeyValue {
+	return attribute.Int("ccr.binlogs", num)
+}
+
+func TxnId(txnId int64) attribute.KeyValue {
+	return attribute.Int64("ccr.txn_id", txnId)
+}
+
+func TableId(tableId int64) attribute.KeyValue {
+	return attribute.Int64("ccr.table_id", tableId)
+}
+
+func TableIds(tableIds []int64) attribute.KeyValue {
+	return attribute.Int64Slice("ccr.table_ids", tableIds)
+}
+
+func TabletId(tabletId int64) attribute.KeyValue {
+	return attribute.Int64("ccr.tablet_id", tabletId)
+}
+
+func SrcBackendIds(backendIds []int64) attribute.KeyValue {
+	return attribute.Int64Slice("ccr.src_backend_ids", backendIds)
+}
+
+func DestBackendIds(backendIds []int64) attribute.KeyValue {
+	return attribute.Int64Slice("ccr.dest_backend_ids", backendIds)
+}
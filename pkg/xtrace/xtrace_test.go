@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License
+package xtrace
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/modern-go/gls"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewSampler(t *testing.T) {
+	for _, ratio := range []float64{-0.1, 1.1} {
+		if _, err := newSampler(ratio); err == nil {
+			t.Errorf("the sample ratio %v should be invalid", ratio)
+		}
+	}
+	for _, ratio := range []float64{0, 0.5, 1} {
+		if _, err := newSampler(ratio); err != nil {
+			t.Errorf("the sample ratio %v should be valid, err: %v", ratio, err)
+		}
+	}
+}
+
+func TestSpanPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	gls.ResetGls(gls.GoID(), map[interface{}]interface{}{})
+	defer gls.DeleteGls(gls.GoID())
+
+	root := Start("HandleBinlog", Job("job"), CommitSeq(10))
+	child := Start("BeginTransaction")
+	child.End(nil)
+	if Current() != root.Context() {
+		t.Fatalf("the current span is not restored after the child span ends")
+	}
+
+	done := make(chan struct{})
+	parent := Current()
+	go func() {
+		defer close(done)
+		StartWithParent(parent, "IngestBinlog").End(errors.New("ingest failed"))
+	}()
+	<-done
+	root.End(nil)
+	if trace.SpanContextFromContext(Current()).IsValid() {
+		t.Fatalf("the current span is not cleared after the root span ends")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expect 3 spans, got %d", len(spans))
+	}
+	rootSpan := spans[2]
+	if rootSpan.Name() != "HandleBinlog" || rootSpan.Parent().IsValid() {
+		t.Fatalf("unexpected root span %s", rootSpan.Name())
+	}
+	for _, span := range spans[:2] {
+		if span.Parent().SpanID() != rootSpan.SpanContext().SpanID() {
+			t.Errorf("the parent of span %s is not the root span", span.Name())
+		}
+	}
+	if status := spans[1].Status(); spans[1].Name() != "IngestBinlog" || status.Code != codes.Error {
+		t.Errorf("the failed ingest span is not marked as error, name: %s, status: %v", spans[1].Name(), status)
+	}
+}
@@ -36,6 +36,11 @@ Options:
     --db_port <arg>             the port of meta database
     --db_user <arg>             the user name of meta database
     --db_password <arg>         the password of meta database
+    --credential_key_file <arg> the file of the key to encrypt the passwords of the jobs at rest
+    --http_auth_config <arg>    the json file of the http auth config, the http api is not authenticated if not set
+    --tls_cert_file <arg>       the cert file of the http server, serve https if set
+    --tls_key_file <arg>        the key file of the http server
+    --tls_ca_file <arg>         the ca file to verify the client certs and the certs of the other syncers
 "
     exit 1
 }
@@ -62,6 +67,11 @@ OPTS="$(getopt \
     -l 'connect_timeout:' \
     -l 'rpc_timeout:' \
     -l 'config_file:' \
+    -l 'credential_key_file:' \
+    -l 'http_auth_config:' \
+    -l 'tls_cert_file:' \
+    -l 'tls_key_file:' \
+    -l 'tls_ca_file:' \
     -- "$@")"
 
 eval set -- "${OPTS}"
@@ -157,6 +167,26 @@ while true; do
         CONFIG_FILE=$2
         shift 2
         ;;
+    --credential_key_file)
+        CREDENTIAL_KEY_FILE=$2
+        shift 2
+        ;;
+    --http_auth_config)
+        HTTP_AUTH_CONFIG=$2
+        shift 2
+        ;;
+    --tls_cert_file)
+        TLS_CERT_FILE=$2
+        shift 2
+        ;;
+    --tls_key_file)
+        TLS_KEY_FILE=$2
+        shift 2
+        ;;
+    --tls_ca_file)
+        TLS_CA_FILE=$2
+        shift 2
+        ;;
     --)
         shift
         break
@@ -210,6 +240,11 @@ if [[ "${RUN_DAEMON}" -eq 1 ]]; then
           "-db_user=${DB_USER}" \
           "-db_password=${DB_PASSWORD}" \
           "-config_file=${CONFIG_FILE}" \
+          "-credential_key_file=${CREDENTIAL_KEY_FILE}" \
+          "-http_auth_config=${HTTP_AUTH_CONFIG}" \
+          "-tls_cert_file=${TLS_CERT_FILE}" \
+          "-tls_key_file=${TLS_KEY_FILE}" \
+          "-tls_ca_file=${TLS_CA_FILE}" \
           "-host=${HOST}" \
           "-port=${PORT}" \
           "-pprof=${PPROF}" \
@@ -229,6 +264,11 @@ else
         "-db_user=${DB_USER}" \
         "-db_password=${DB_PASSWORD}" \
         "-config_file=${CONFIG_FILE}" \
+        "-credential_key_file=${CREDENTIAL_KEY_FILE}" \
+        "-http_auth_config=${HTTP_AUTH_CONFIG}" \
+        "-tls_cert_file=${TLS_CERT_FILE}" \
+        "-tls_key_file=${TLS_KEY_FILE}" \
+        "-tls_ca_file=${TLS_CA_FILE}" \
         "-host=${HOST}" \
         "-port=${PORT}" \
         "-pprof=${PPROF}" \